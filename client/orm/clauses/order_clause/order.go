@@ -14,12 +14,22 @@ const (
 	Descending Sort = 2
 )
 
+// Nulls is the position of NULL values in the order
+type Nulls int8
+
+const (
+	NullsDefault Nulls = 0
+	NullsFirst   Nulls = 1
+	NullsLast    Nulls = 2
+)
+
 type Option func(order *Order)
 
 type Order struct {
 	column string
 	sort   Sort
 	isRaw  bool
+	nulls  Nulls
 }
 
 func Clause(options ...Option) *Order {
@@ -54,6 +64,21 @@ func (o *Order) IsRaw() bool {
 	return o.isRaw
 }
 
+func (o *Order) GetNulls() Nulls {
+	return o.nulls
+}
+
+func (o *Order) NullsString() string {
+	switch o.GetNulls() {
+	case NullsFirst:
+		return "NULLS FIRST"
+	case NullsLast:
+		return "NULLS LAST"
+	}
+
+	return ``
+}
+
 func ParseOrder(expressions ...string) []*Order {
 	var orders []*Order
 	for _, expression := range expressions {
@@ -97,6 +122,12 @@ func SortNone() Option {
 	return sort(None)
 }
 
+func WithNulls(nulls Nulls) Option {
+	return func(order *Order) {
+		order.nulls = nulls
+	}
+}
+
 func Raw() Option {
 	return func(order *Order) {
 		order.isRaw = true
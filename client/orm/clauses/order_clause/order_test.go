@@ -135,3 +135,22 @@ func TestSortString(t *testing.T) {
 		t.Errorf(template, o3.SortString(), ``)
 	}
 }
+
+func TestNullsString(t *testing.T) {
+	template := "got: %s, want: %s"
+
+	o1 := Clause(WithNulls(NullsFirst))
+	if o1.NullsString() != "NULLS FIRST" {
+		t.Errorf(template, o1.NullsString(), "NULLS FIRST")
+	}
+
+	o2 := Clause(WithNulls(NullsLast))
+	if o2.NullsString() != "NULLS LAST" {
+		t.Errorf(template, o2.NullsString(), "NULLS LAST")
+	}
+
+	o3 := Clause()
+	if o3.GetNulls() != NullsDefault || o3.NullsString() != `` {
+		t.Errorf(template, o3.NullsString(), ``)
+	}
+}
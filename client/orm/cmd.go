@@ -297,3 +297,82 @@ func RunSyncdb(name string, force bool, verbose bool) error {
 	cmd.rtOnError = true
 	return cmd.Run()
 }
+
+// GenerateCreateSQL return the sql to create the tables and indexes of
+// all registered models for the database alias, like the sqlall command.
+// the sql is not executed.
+func GenerateCreateSQL(aliasName string) ([]string, error) {
+	BootStrap()
+
+	al, ok := dataBaseCache.get(aliasName)
+	if !ok {
+		return nil, fmt.Errorf("unknown DataBase alias name %s", aliasName)
+	}
+	createQueries, indexes, err := modelCache.getDbCreateSQL(al)
+	if err != nil {
+		return nil, err
+	}
+
+	var queries []string
+	for i, mi := range modelCache.allOrdered() {
+		queries = append(queries, createQueries[i])
+		for _, idx := range indexes[mi.table] {
+			queries = append(queries, idx.SQL)
+		}
+	}
+	return queries, nil
+}
+
+// GenerateAlterSQL compare the registered models with the tables in the database alias,
+// and return the sql to reconcile them: create the missing tables, add the missing columns and indexes.
+// the columns and indexes not in models are kept, and the sql is not executed.
+func GenerateAlterSQL(aliasName string) ([]string, error) {
+	BootStrap()
+
+	al, ok := dataBaseCache.get(aliasName)
+	if !ok {
+		return nil, fmt.Errorf("unknown DataBase alias name %s", aliasName)
+	}
+	createQueries, indexes, err := modelCache.getDbCreateSQL(al)
+	if err != nil {
+		return nil, err
+	}
+
+	db := al.DB
+	tables, err := al.DbBaser.GetTables(db)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	var queries []string
+	for i, mi := range modelCache.allOrdered() {
+		if !isApplicableTableForDB(mi.addrField, al.Name) {
+			continue
+		}
+
+		if !tables[mi.table] {
+			queries = append(queries, createQueries[i])
+			for _, idx := range indexes[mi.table] {
+				queries = append(queries, idx.SQL)
+			}
+			continue
+		}
+
+		columns, err := al.DbBaser.GetColumns(ctx, db, mi.table)
+		if err != nil {
+			return nil, err
+		}
+		for _, fi := range mi.fields.fieldsDB {
+			if _, ok := columns[fi.column]; !ok {
+				queries = append(queries, getColumnAddQuery(al, fi))
+			}
+		}
+		for _, idx := range indexes[mi.table] {
+			if !al.DbBaser.IndexExists(ctx, db, idx.Table, idx.Name) {
+				queries = append(queries, idx.SQL)
+			}
+		}
+	}
+	return queries, nil
+}
@@ -27,6 +27,9 @@ type dbIndex struct {
 
 // get database column type string.
 func getColumnTyp(al *alias, fi *fieldInfo) (col string) {
+	if typ, ok := fi.dbTypes[al.Driver]; ok {
+		return typ
+	}
 	T := al.DbBaser.DbTypes()
 	fieldType := fi.fieldType
 	fieldSize := fi.size
@@ -36,7 +39,9 @@ checkColumn:
 	case TypeBooleanField:
 		col = T["bool"]
 	case TypeVarCharField:
-		if al.Driver == DRPostgres && fi.toText {
+		if al.Driver == DRMySQL && fi.enumNative {
+			col = fmt.Sprintf("enum('%s')", strings.Join(fi.enum, "','"))
+		} else if al.Driver == DRPostgres && fi.toText {
 			col = T["string-text"]
 		} else {
 			col = fmt.Sprintf(T["string"], fieldSize)
@@ -44,7 +49,15 @@ checkColumn:
 	case TypeCharField:
 		col = fmt.Sprintf(T["string-char"], fieldSize)
 	case TypeTextField:
-		col = T["string-text"]
+		if fi.array {
+			col = getArrayColumnTyp(al, fi)
+		} else if fi.point {
+			col = getPointColumnTyp(al)
+		} else if fi.codec != nil {
+			col = getCodecColumnTyp(al)
+		} else {
+			col = T["string-text"]
+		}
 	case TypeTimeField:
 		col = T["time.Time-clock"]
 	case TypeDateField:
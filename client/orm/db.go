@@ -17,12 +17,18 @@ package orm
 import (
 	"context"
 	"database/sql"
+	sqldriver "database/sql/driver"
 	"errors"
 	"fmt"
+	"io"
+	"net"
 	"reflect"
+	"sort"
 	"strings"
+	"syscall"
 	"time"
 
+	"github.com/beego/beego/v2/client/orm/clauses/order_clause"
 	"github.com/beego/beego/v2/client/orm/hints"
 )
 
@@ -95,8 +101,9 @@ func (d *dbBase) collectValues(mi *modelInfo, ind reflect.Value, cols []string,
 			return nil, nil, err
 		}
 
-		// ignore empty value auto field
-		if insert && fi.auto {
+		// ignore empty value auto field, and sequence pk field which is not
+		// in a prepared insert, let database assign the value
+		if insert && (fi.auto || fi.sequence && !skipAuto) {
 			if fi.fieldType&IsPositiveIntegerField > 0 {
 				if vu, ok := value.(uint64); !ok || vu == 0 {
 					continue
@@ -115,6 +122,37 @@ func (d *dbBase) collectValues(mi *modelInfo, ind reflect.Value, cols []string,
 	return
 }
 
+// get the columns to insert, the fields with tag `orm:"use_db_default"` are omitted
+// if they are zero value, so the database assigns the column default.
+// InsertMulti and the prepared insert always insert all the writable columns.
+func (d *dbBase) insertColumns(mi *modelInfo, ind reflect.Value) []string {
+	cols := make([]string, 0, len(mi.fields.dbcols))
+	for _, column := range d.writableColumns(mi) {
+		fi := mi.fields.GetByColumn(column)
+		if fi != nil && fi.useDbDefault && ind.FieldByIndex(fi.fieldIndex).IsZero() {
+			continue
+		}
+		cols = append(cols, column)
+	}
+	return cols
+}
+
+// get the columns to insert and update, the fields with tag `orm:"generated"`
+// are computed by database and only read.
+func (d *dbBase) writableColumns(mi *modelInfo) []string {
+	if !mi.hasGenerated {
+		return mi.fields.dbcols
+	}
+	cols := make([]string, 0, len(mi.fields.dbcols))
+	for _, column := range mi.fields.dbcols {
+		if fi := mi.fields.GetByColumn(column); fi != nil && fi.generated {
+			continue
+		}
+		cols = append(cols, column)
+	}
+	return cols
+}
+
 // get one field value in struct column as interface.
 func (d *dbBase) collectFieldValue(mi *modelInfo, fi *fieldInfo, ind reflect.Value, insert bool, tz *time.Location) (interface{}, error) {
 	var value interface{}
@@ -125,6 +163,22 @@ func (d *dbBase) collectFieldValue(mi *modelInfo, fi *fieldInfo, ind reflect.Val
 		if fi.isFielder {
 			f := field.Addr().Interface().(Fielder)
 			value = f.RawValue()
+		} else if fi.array {
+			value = getArrayFieldValue(field)
+		} else if fi.point {
+			value = getPointFieldValue(field)
+		} else if fi.codec != nil {
+			v, err := fi.codec.encode(field.Interface())
+			if err != nil {
+				return nil, err
+			}
+			value = v
+		} else if fi.isScanner {
+			v, err := getScannerFieldValue(field)
+			if err != nil {
+				return nil, err
+			}
+			value = v
 		} else {
 			switch fi.fieldType {
 			case TypeBooleanField:
@@ -238,7 +292,7 @@ func (d *dbBase) collectFieldValue(mi *modelInfo, fi *fieldInfo, ind reflect.Val
 						break
 					}
 				}
-				tnow := time.Now()
+				tnow := nowFunc()
 				d.ins.TimeToDB(&tnow, tz)
 				value = tnow
 				if fi.isFielder {
@@ -251,6 +305,16 @@ func (d *dbBase) collectFieldValue(mi *modelInfo, fi *fieldInfo, ind reflect.Val
 					field.Set(reflect.ValueOf(tnow.In(DefaultTimeLoc)))
 				}
 			}
+		case TypeVarCharField, TypeCharField, TypeTextField:
+			if err := fi.checkEnum(value); err != nil {
+				return nil, err
+			}
+			if fi.transformer != nil {
+				var err error
+				if value, err = fi.transformer.encode(value); err != nil {
+					return nil, err
+				}
+			}
 		case TypeJSONField, TypeJsonbField:
 			if s, ok := value.(string); (ok && len(s) == 0) || value == nil {
 				if fi.colDefault && fi.initial.Exist() {
@@ -271,7 +335,7 @@ func (d *dbBase) PrepareInsert(ctx context.Context, q dbQuerier, mi *modelInfo)
 	dbcols := make([]string, 0, len(mi.fields.dbcols))
 	marks := make([]string, 0, len(mi.fields.dbcols))
 	for _, fi := range mi.fields.fieldsDB {
-		if !fi.auto {
+		if !fi.auto && !fi.generated {
 			dbcols = append(dbcols, fi.column)
 			marks = append(marks, "?")
 		}
@@ -292,16 +356,16 @@ func (d *dbBase) PrepareInsert(ctx context.Context, q dbQuerier, mi *modelInfo)
 
 // insert struct with prepared statement and given struct reflect value.
 func (d *dbBase) InsertStmt(ctx context.Context, stmt stmtQuerier, mi *modelInfo, ind reflect.Value, tz *time.Location) (int64, error) {
-	values, _, err := d.collectValues(mi, ind, mi.fields.dbcols, true, true, nil, tz)
+	names := make([]string, 0, len(mi.fields.dbcols))
+	values, _, err := d.collectValues(mi, ind, d.writableColumns(mi), true, true, &names, tz)
 	if err != nil {
 		return 0, err
 	}
+	ctx = withArgFields(ctx, columnFields(mi, names))
 
 	if d.ins.HasReturningID(mi, nil) {
 		row := stmt.QueryRow(values...)
-		var id int64
-		err := row.Scan(&id)
-		return id, err
+		return scanReturningID(row, mi)
 	}
 	res, err := stmt.ExecContext(ctx, values...)
 	if err == nil {
@@ -314,9 +378,15 @@ func (d *dbBase) InsertStmt(ctx context.Context, stmt stmtQuerier, mi *modelInfo
 func (d *dbBase) Read(ctx context.Context, q dbQuerier, mi *modelInfo, ind reflect.Value, tz *time.Location, cols []string, isForUpdate bool) error {
 	var whereCols []string
 	var args []interface{}
+	var pk interface{}
 
 	// if specify cols length > 0, then use it for where condition.
 	if len(cols) > 0 {
+		for _, col := range cols {
+			if fi, ok := mi.fields.GetByAny(col); !ok || !fi.dbcol {
+				return fmt.Errorf("%w `%s` for model `%s`", ErrWrongColumn, col, mi.fullName)
+			}
+		}
 		var err error
 		whereCols = make([]string, 0, len(cols))
 		args, _, err = d.collectValues(mi, ind, cols, false, false, &whereCols, tz)
@@ -331,6 +401,7 @@ func (d *dbBase) Read(ctx context.Context, q dbQuerier, mi *modelInfo, ind refle
 		}
 		whereCols = []string{pkColumn}
 		args = append(args, pkValue)
+		pk = pkValue
 	}
 
 	Q := d.ins.TableQuote()
@@ -357,12 +428,14 @@ func (d *dbBase) Read(ctx context.Context, q dbQuerier, mi *modelInfo, ind refle
 
 	d.ins.ReplaceMarks(&query)
 
+	ctx, cancel := withQueryTimeout(withArgFields(ctx, columnFields(mi, whereCols)))
+	defer cancel()
 	row := q.QueryRowContext(ctx, query, args...)
 	if err := row.Scan(refs...); err != nil {
 		if err == sql.ErrNoRows {
-			return ErrNoRows
+			return notFoundError(mi.table, pk)
 		}
-		return err
+		return wrapQueryError(ctx, query, args, err)
 	}
 	elm := reflect.New(mi.addrField.Elem().Type())
 	mind := reflect.Indirect(elm)
@@ -374,7 +447,7 @@ func (d *dbBase) Read(ctx context.Context, q dbQuerier, mi *modelInfo, ind refle
 // execute insert sql dbQuerier with given struct reflect.Value.
 func (d *dbBase) Insert(ctx context.Context, q dbQuerier, mi *modelInfo, ind reflect.Value, tz *time.Location) (int64, error) {
 	names := make([]string, 0, len(mi.fields.dbcols))
-	values, autoFields, err := d.collectValues(mi, ind, mi.fields.dbcols, false, true, &names, tz)
+	values, autoFields, err := d.collectValues(mi, ind, d.insertColumns(mi, ind), false, true, &names, tz)
 	if err != nil {
 		return 0, err
 	}
@@ -392,16 +465,32 @@ func (d *dbBase) Insert(ctx context.Context, q dbQuerier, mi *modelInfo, ind ref
 
 // multi-insert sql with given slice struct reflect.Value.
 func (d *dbBase) InsertMulti(ctx context.Context, q dbQuerier, mi *modelInfo, sind reflect.Value, bulk int, tz *time.Location) (int64, error) {
+	var cnt int64
+	results, err := d.InsertMultiResult(ctx, q, mi, sind, bulk, tz)
+	for _, res := range results {
+		num, e := res.RowsAffected()
+		if e != nil {
+			return cnt, e
+		}
+		cnt += num
+	}
+	return cnt, err
+}
+
+// multi-insert sql with given slice struct reflect.Value, return the result of each statement.
+func (d *dbBase) InsertMultiResult(ctx context.Context, q dbQuerier, mi *modelInfo, sind reflect.Value, bulk int, tz *time.Location) ([]sql.Result, error) {
 	var (
-		cnt    int64
-		nums   int
-		values []interface{}
-		names  []string
+		results []sql.Result
+		nums    int
+		values  []interface{}
+		names   []string
 	)
 
 	// typ := reflect.Indirect(mi.addrField).Type()
 
 	length, autoFields := sind.Len(), make([]string, 0, 1)
+	// the index of the first row of current batch
+	start := 0
 
 	for i := 1; i <= length; i++ {
 
@@ -417,32 +506,48 @@ func (d *dbBase) InsertMulti(ctx context.Context, q dbQuerier, mi *modelInfo, si
 				vus []interface{}
 				err error
 			)
-			vus, autoFields, err = d.collectValues(mi, ind, mi.fields.dbcols, false, true, &names, tz)
+			vus, autoFields, err = d.collectValues(mi, ind, d.writableColumns(mi), false, true, &names, tz)
 			if err != nil {
-				return cnt, err
+				return results, err
 			}
 			values = make([]interface{}, bulk*len(vus))
 			nums += copy(values, vus)
 		} else {
-			vus, _, err := d.collectValues(mi, ind, mi.fields.dbcols, false, true, nil, tz)
+			vus, _, err := d.collectValues(mi, ind, d.writableColumns(mi), false, true, nil, tz)
 			if err != nil {
-				return cnt, err
+				return results, err
 			}
 
 			if len(vus) != len(names) {
-				return cnt, ErrArgs
+				return results, ErrArgs
 			}
 
 			nums += copy(values[nums:], vus)
 		}
 
-		if i > 1 && i%bulk == 0 || length == i {
-			num, err := d.InsertValue(ctx, q, mi, true, names, values[:nums])
-			if err != nil {
-				return cnt, err
+		if i%bulk == 0 || length == i {
+			// the pks are set back only if they are generated by the database
+			if !mi.fields.pk.auto || len(autoFields) > 0 {
+				query := d.insertValuesSQL(mi, true, names, values[:nums])
+				res, err := q.ExecContext(withArgFields(ctx, insertArgFields(mi, names, nums)), query, values[:nums]...)
+				if err != nil {
+					return results, err
+				}
+				results = append(results, res)
+			} else {
+				res, ids, err := d.insertValuesWithIDs(ctx, q, mi, names, values[:nums])
+				if err != nil {
+					return results, err
+				}
+				results = append(results, res)
+				if len(ids) == i-start {
+					for j, id := range ids {
+						setAutoPk(mi, reflect.Indirect(sind.Index(start+j)), id)
+					}
+				}
 			}
-			cnt += num
 			nums = 0
+			start = i
 		}
 	}
 
@@ -451,12 +556,11 @@ func (d *dbBase) InsertMulti(ctx context.Context, q dbQuerier, mi *modelInfo, si
 		err = d.ins.setval(ctx, q, mi, autoFields)
 	}
 
-	return cnt, err
+	return results, err
 }
 
-// execute insert sql with given struct and given values.
-// insert the given values, not the field values in struct.
-func (d *dbBase) InsertValue(ctx context.Context, q dbQuerier, mi *modelInfo, isMulti bool, names []string, values []interface{}) (int64, error) {
+// generate insert sql of the rows of values
+func (d *dbBase) insertValuesSQL(mi *modelInfo, isMulti bool, names []string, values []interface{}) string {
 	Q := d.ins.TableQuote()
 
 	marks := make([]string, len(names))
@@ -477,6 +581,55 @@ func (d *dbBase) InsertValue(ctx context.Context, q dbQuerier, mi *modelInfo, is
 	query := fmt.Sprintf("INSERT INTO %s%s%s (%s%s%s) VALUES (%s)", Q, mi.table, Q, Q, columns, Q, qmarks)
 
 	d.ins.ReplaceMarks(&query)
+	return query
+}
+
+// insert the rows of values in one statement, return the result and the auto pks of the rows.
+// the pks are returned by RETURNING sql, or calculated from the last insert id,
+// they are nil if the database doesn't support both of them.
+// the result of RETURNING sql only has the affected rows.
+func (d *dbBase) insertValuesWithIDs(ctx context.Context, q dbQuerier, mi *modelInfo, names []string, values []interface{}) (sql.Result, []int64, error) {
+	query := d.insertValuesSQL(mi, true, names, values)
+	multi := len(values) / len(names)
+	ctx = withArgFields(ctx, insertArgFields(mi, names, len(values)))
+
+	if d.ins.HasReturningID(mi, &query) {
+		ctx, cancel := withQueryTimeout(ctx)
+		defer cancel()
+		rows, err := q.QueryContext(ctx, query, values...)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer rows.Close()
+		ids := make([]int64, 0, multi)
+		for rows.Next() {
+			id, err := scanReturningID(rows, mi)
+			if err != nil {
+				return nil, nil, err
+			}
+			ids = append(ids, id)
+		}
+		return sqldriver.RowsAffected(len(ids)), ids, rows.Err()
+	}
+
+	res, err := q.ExecContext(ctx, query, values...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res, d.ins.insertedIDs(res, multi), nil
+}
+
+// the auto pks of the rows inserted by one statement,
+// nil if the database can't tell them.
+func (d *dbBase) insertedIDs(res sql.Result, rows int) []int64 {
+	return nil
+}
+
+// execute insert sql with given struct and given values.
+// insert the given values, not the field values in struct.
+func (d *dbBase) InsertValue(ctx context.Context, q dbQuerier, mi *modelInfo, isMulti bool, names []string, values []interface{}) (int64, error) {
+	query := d.insertValuesSQL(mi, isMulti, names, values)
+	ctx = withArgFields(ctx, insertArgFields(mi, names, len(values)))
 
 	if isMulti || !d.ins.HasReturningID(mi, &query) {
 		res, err := q.ExecContext(ctx, query, values...)
@@ -495,10 +648,104 @@ func (d *dbBase) InsertValue(ctx context.Context, q dbQuerier, mi *modelInfo, is
 		}
 		return 0, err
 	}
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 	row := q.QueryRowContext(ctx, query, values...)
-	var id int64
-	err := row.Scan(&id)
-	return id, err
+	id, err := scanReturningID(row, mi)
+	return id, wrapQueryError(ctx, query, values, err)
+}
+
+// InsertWithPK insert a row with the pk value of model even if the pk is auto,
+// return the affected rows. the pk isn't read back and the sequence isn't synced.
+func (d *dbBase) InsertWithPK(ctx context.Context, q dbQuerier, mi *modelInfo, ind reflect.Value, tz *time.Location) (int64, error) {
+	if ind.FieldByIndex(mi.fields.pk.fieldIndex).IsZero() {
+		return 0, ErrMissPK
+	}
+
+	names := make([]string, 0, len(mi.fields.dbcols))
+	values, _, err := d.collectValues(mi, ind, d.insertColumns(mi, ind), false, true, &names, tz)
+	if err != nil {
+		return 0, err
+	}
+
+	query := d.insertValuesSQL(mi, false, names, values)
+	ctx = withArgFields(ctx, columnFields(mi, names))
+	res, err := q.ExecContext(ctx, query, values...)
+	if err != nil {
+		return 0, wrapQueryError(ctx, query, values, err)
+	}
+	return res.RowsAffected()
+}
+
+// InsertOrIgnore insert a row, do nothing if it conflicts with an existing row.
+// return the affected rows and the auto pk of the inserted row.
+func (d *dbBase) InsertOrIgnore(ctx context.Context, q dbQuerier, mi *modelInfo, ind reflect.Value, a *alias, conflictCols []string) (int64, int64, error) {
+	Q := d.ins.TableQuote()
+
+	insert, conflict := "INSERT INTO", ""
+	switch a.Driver {
+	case DRMySQL, DRTiDB:
+		insert = "INSERT IGNORE INTO"
+	case DRPostgres, DRSqlite:
+		conflict = " ON CONFLICT DO NOTHING"
+		if len(conflictCols) > 0 {
+			cols := make([]string, 0, len(conflictCols))
+			for _, col := range conflictCols {
+				fi, ok := mi.fields.GetByAny(col)
+				if !ok || !fi.dbcol {
+					return 0, 0, fmt.Errorf("%w `%s` for model `%s`", ErrWrongColumn, col, mi.fullName)
+				}
+				cols = append(cols, fmt.Sprintf("%s%s%s", Q, fi.column, Q))
+			}
+			conflict = fmt.Sprintf(" ON CONFLICT (%s) DO NOTHING", strings.Join(cols, ", "))
+		}
+	default:
+		return 0, 0, fmt.Errorf("`%s` nonsupport InsertOrIgnore in beego", a.DriverName)
+	}
+
+	names := make([]string, 0, len(mi.fields.dbcols))
+	values, _, err := d.collectValues(mi, ind, d.insertColumns(mi, ind), true, true, &names, a.TZ)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	sep := fmt.Sprintf("%s, %s", Q, Q)
+	marks := make([]string, len(names))
+	for i := range marks {
+		marks[i] = "?"
+	}
+	query := fmt.Sprintf("%s %s%s%s (%s%s%s) VALUES (%s)%s", insert, Q, mi.table, Q, Q, strings.Join(names, sep), Q, strings.Join(marks, ", "), conflict)
+
+	d.ins.ReplaceMarks(&query)
+	ctx, cancel := withQueryTimeout(withArgFields(ctx, columnFields(mi, names)))
+	defer cancel()
+
+	if d.ins.HasReturningID(mi, &query) {
+		// no row is returned if skipped
+		id, err := scanReturningID(q.QueryRowContext(ctx, query, values...), mi)
+		if err == sql.ErrNoRows {
+			return 0, 0, nil
+		}
+		if err != nil {
+			return 0, 0, wrapQueryError(ctx, query, values, err)
+		}
+		return 1, id, nil
+	}
+
+	res, err := q.ExecContext(ctx, query, values...)
+	if err != nil {
+		return 0, 0, wrapQueryError(ctx, query, values, err)
+	}
+	cnt, err := res.RowsAffected()
+	if err != nil || cnt == 0 || !mi.fields.pk.auto {
+		return cnt, 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		DebugLog.Println(ErrLastInsertIdUnavailable, ':', err)
+		return cnt, 0, ErrLastInsertIdUnavailable
+	}
+	return cnt, id, nil
 }
 
 // InsertOrUpdate a row
@@ -515,14 +762,26 @@ func (d *dbBase) InsertOrUpdate(ctx context.Context, q dbQuerier, mi *modelInfo,
 		if len(args) == 0 {
 			return 0, fmt.Errorf("`%s` use InsertOrUpdate must have a conflict column", a.DriverName)
 		}
-		args0 = strings.ToLower(args[0])
-		iouStr = fmt.Sprintf("ON CONFLICT (%s) DO UPDATE SET", args0)
+		conflictCol, conflictWhere := splitConflictTarget(args[0])
+		if isQuoteAlways(a.Driver) {
+			Q := d.ins.TableQuote()
+			args0 = fmt.Sprintf("%s%s%s", Q, conflictCol, Q)
+		} else {
+			args0 = strings.ToLower(conflictCol)
+		}
+		iouStr = fmt.Sprintf("ON CONFLICT (%s)%s DO UPDATE SET", args0, conflictWhere)
 	default:
 		return 0, fmt.Errorf("`%s` nonsupport InsertOrUpdate in beego", a.DriverName)
 	}
 
 	// Get on the key-value pairs
-	for _, v := range args {
+	updateWhere := ""
+	for i, v := range args {
+		// the condition of update, args[0] is the conflict target
+		if i > 0 && strings.HasPrefix(strings.ToUpper(strings.TrimSpace(v)), "WHERE ") {
+			updateWhere = " " + strings.TrimSpace(v)
+			continue
+		}
 		kv := strings.Split(v, "=")
 		if len(kv) == 2 {
 			argsMap[strings.ToLower(kv[0])] = kv[1]
@@ -532,7 +791,7 @@ func (d *dbBase) InsertOrUpdate(ctx context.Context, q dbQuerier, mi *modelInfo,
 	isMulti := false
 	names := make([]string, 0, len(mi.fields.dbcols)-1)
 	Q := d.ins.TableQuote()
-	values, _, err := d.collectValues(mi, ind, mi.fields.dbcols, true, true, &names, a.TZ)
+	values, _, err := d.collectValues(mi, ind, d.insertColumns(mi, ind), true, true, &names, a.TZ)
 	if err != nil {
 		return 0, err
 	}
@@ -540,7 +799,9 @@ func (d *dbBase) InsertOrUpdate(ctx context.Context, q dbQuerier, mi *modelInfo,
 	marks := make([]string, len(names))
 	updateValues := make([]interface{}, 0)
 	updates := make([]string, len(names))
+	fields := columnFields(mi, names)
 	var conflitValue interface{}
+	var conflitField *fieldInfo
 	for i, v := range names {
 		// identifier in database may not be case-sensitive, so quote it
 		v = fmt.Sprintf("%s%s%s", Q, v, Q)
@@ -548,6 +809,7 @@ func (d *dbBase) InsertOrUpdate(ctx context.Context, q dbQuerier, mi *modelInfo,
 		valueStr := argsMap[strings.ToLower(v)]
 		if v == args0 {
 			conflitValue = values[i]
+			conflitField = fields[i]
 		}
 		if valueStr != "" {
 			switch a.Driver {
@@ -556,8 +818,9 @@ func (d *dbBase) InsertOrUpdate(ctx context.Context, q dbQuerier, mi *modelInfo,
 			case DRPostgres:
 				if conflitValue != nil {
 					// postgres ON CONFLICT DO UPDATE SET can`t use colu=colu+values
-					updates[i] = fmt.Sprintf("%s=(select %s from %s where %s = ? )", v, valueStr, mi.table, args0)
+					updates[i] = fmt.Sprintf("%s=(select %s from %s%s%s where %s = ? )", v, valueStr, Q, mi.table, Q, args0)
 					updateValues = append(updateValues, conflitValue)
+					fields = append(fields, conflitField)
 				} else {
 					return 0, fmt.Errorf("`%s` must be in front of `%s` in your struct", args0, v)
 				}
@@ -565,6 +828,7 @@ func (d *dbBase) InsertOrUpdate(ctx context.Context, q dbQuerier, mi *modelInfo,
 		} else {
 			updates[i] = v + "=?"
 			updateValues = append(updateValues, values[i])
+			fields = append(fields, fields[i])
 		}
 	}
 
@@ -581,9 +845,10 @@ func (d *dbBase) InsertOrUpdate(ctx context.Context, q dbQuerier, mi *modelInfo,
 		qmarks = strings.Repeat(qmarks+"), (", multi-1) + qmarks
 	}
 	// conflitValue maybe is a int,can`t use fmt.Sprintf
-	query := fmt.Sprintf("INSERT INTO %s%s%s (%s%s%s) VALUES (%s) %s "+qupdates, Q, mi.table, Q, Q, columns, Q, qmarks, iouStr)
+	query := fmt.Sprintf("INSERT INTO %s%s%s (%s%s%s) VALUES (%s) %s "+qupdates+updateWhere, Q, mi.table, Q, Q, columns, Q, qmarks, iouStr)
 
 	d.ins.ReplaceMarks(&query)
+	ctx = withArgFields(ctx, fields)
 
 	if isMulti || !d.ins.HasReturningID(mi, &query) {
 		res, err := q.ExecContext(ctx, query, values...)
@@ -603,13 +868,18 @@ func (d *dbBase) InsertOrUpdate(ctx context.Context, q dbQuerier, mi *modelInfo,
 		return 0, err
 	}
 
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 	row := q.QueryRowContext(ctx, query, values...)
-	var id int64
-	err = row.Scan(&id)
+	id, err := scanReturningID(row, mi)
+	// no row is returned if the update condition is false
+	if err == sql.ErrNoRows && updateWhere != "" {
+		return 0, nil
+	}
 	if err != nil && err.Error() == `pq: syntax error at or near "ON"` {
 		err = fmt.Errorf("postgres version must 9.5 or higher")
 	}
-	return id, err
+	return id, wrapQueryError(ctx, query, values, err)
 }
 
 // execute update sql dbQuerier with given struct reflect.Value.
@@ -623,7 +893,7 @@ func (d *dbBase) Update(ctx context.Context, q dbQuerier, mi *modelInfo, ind ref
 
 	// if specify cols length is zero, then commit all columns.
 	if len(cols) == 0 {
-		cols = mi.fields.dbcols
+		cols = d.writableColumns(mi)
 		setNames = make([]string, 0, len(mi.fields.dbcols)-1)
 	} else {
 		setNames = make([]string, 0, len(cols))
@@ -654,7 +924,7 @@ func (d *dbBase) Update(ctx context.Context, q dbQuerier, mi *modelInfo, ind ref
 		for col, info := range mi.fields.columns {
 			if info.autoNow {
 				setNames = append(setNames, col)
-				setValues = append(setValues, time.Now())
+				setValues = append(setValues, nowFunc())
 			}
 		}
 	}
@@ -670,7 +940,8 @@ func (d *dbBase) Update(ctx context.Context, q dbQuerier, mi *modelInfo, ind ref
 
 	d.ins.ReplaceMarks(&query)
 
-	res, err := q.ExecContext(ctx, query, setValues...)
+	fields := columnFields(mi, append(setNames, pkName))
+	res, err := q.ExecContext(withArgFields(ctx, fields), query, setValues...)
 	if err == nil {
 		return res.RowsAffected()
 	}
@@ -708,7 +979,7 @@ func (d *dbBase) Delete(ctx context.Context, q dbQuerier, mi *modelInfo, ind ref
 	query := fmt.Sprintf("DELETE FROM %s%s%s WHERE %s%s%s = ?", Q, mi.table, Q, Q, wheres, Q)
 
 	d.ins.ReplaceMarks(&query)
-	res, err := q.ExecContext(ctx, query, args...)
+	res, err := q.ExecContext(withArgFields(ctx, columnFields(mi, whereCols)), query, args...)
 	if err == nil {
 		num, err := res.RowsAffected()
 		if err != nil {
@@ -725,18 +996,48 @@ func (d *dbBase) Delete(ctx context.Context, q dbQuerier, mi *modelInfo, ind ref
 	return 0, err
 }
 
+// get the table name used by querySet, a table set by UseTable takes precedence.
+func getQsTable(qs *querySet, mi *modelInfo) string {
+	if qs != nil && qs.table != "" {
+		return qs.table
+	}
+	return mi.table
+}
+
 // update table-related record by querySet.
 // need querySet not struct reflect.Value to update related records.
 func (d *dbBase) UpdateBatch(ctx context.Context, q dbQuerier, qs *querySet, mi *modelInfo, cond *Condition, params Params, tz *time.Location) (int64, error) {
-	columns := make([]string, 0, len(params))
-	values := make([]interface{}, 0, len(params))
-	for col, val := range params {
-		if fi, ok := mi.fields.GetByAny(col); !ok || !fi.dbcol {
-			panic(fmt.Errorf("wrong field/column name `%s`", col))
-		} else {
-			columns = append(columns, fi.column)
-			values = append(values, val)
+	table := getQsTable(qs, mi)
+	var updates []updateSet
+	if qs != nil {
+		updates = qs.sets
+	}
+	// the columns of Set keep their order, then the params sorted by name
+	names := make([]string, 0, len(params))
+	for col := range params {
+		names = append(names, col)
+	}
+	sort.Strings(names)
+	for _, col := range names {
+		updates = append(updates[:len(updates):len(updates)], updateSet{col: col, value: params[col]})
+	}
+
+	columns := make([]*fieldInfo, 0, len(updates))
+	values := make([]interface{}, 0, len(updates))
+	indexes := make(map[*fieldInfo]int, len(updates))
+	for _, s := range updates {
+		fi, ok := mi.fields.GetByAny(s.col)
+		if !ok || !fi.dbcol {
+			panic(fmt.Errorf("wrong field/column name `%s`", s.col))
+		}
+		// the later value of the same column wins
+		if i, ok := indexes[fi]; ok {
+			values[i] = s.value
+			continue
 		}
+		indexes[fi] = len(columns)
+		columns = append(columns, fi)
+		values = append(values, s.value)
 	}
 
 	if len(columns) == 0 {
@@ -747,12 +1048,10 @@ func (d *dbBase) UpdateBatch(ctx context.Context, q dbQuerier, qs *querySet, mi
 	var specifyIndexes string
 	if qs != nil {
 		tables.parseRelated(qs.related, qs.relDepth)
-		specifyIndexes = tables.getIndexSql(mi.table, qs.useIndex, qs.indexes)
+		specifyIndexes = tables.getIndexSql(table, qs.useIndex, qs.indexes)
 	}
 
-	where, args := tables.getCondSQL(cond, false, tz)
-
-	values = append(values, args...)
+	where, args, fields := tables.getCondSQL(cond, false, tz)
 
 	join := tables.getJoinSQL()
 
@@ -765,10 +1064,13 @@ func (d *dbBase) UpdateBatch(ctx context.Context, q dbQuerier, qs *querySet, mi
 	}
 
 	cols := make([]string, 0, len(columns))
+	setValues := make([]interface{}, 0, len(values)+len(args))
+	setFields := make([]*fieldInfo, 0, len(values)+len(fields))
 
-	for i, v := range columns {
-		col := fmt.Sprintf("%s%s%s%s", T, Q, v, Q)
-		if c, ok := values[i].(colValue); ok {
+	for i, fi := range columns {
+		col := fmt.Sprintf("%s%s%s%s", T, Q, fi.column, Q)
+		switch c := values[i].(type) {
+		case colValue:
 			switch c.opt {
 			case ColAdd:
 				cols = append(cols, col+" = "+col+" + ?")
@@ -789,26 +1091,61 @@ func (d *dbBase) UpdateBatch(ctx context.Context, q dbQuerier, qs *querySet, mi
 			case ColBitOr:
 				cols = append(cols, col+" = "+col+" | ?")
 			}
-			values[i] = c.value
-		} else {
+			setValues = append(setValues, c.value)
+			setFields = append(setFields, fi)
+		case colRef:
+			rfi, ok := mi.fields.GetByAny(c.name)
+			if !ok || !rfi.dbcol {
+				panic(fmt.Errorf("wrong field/column name `%s`", c.name))
+			}
+			if !isCompatibleColumn(fi, rfi) {
+				panic(fmt.Errorf("column `%s` cannot be assigned to column `%s`, type mismatch", rfi.column, fi.column))
+			}
+			cols = append(cols, fmt.Sprintf("%s = %s%s%s%s", col, T, Q, rfi.column, Q))
+		default:
+			value := values[i]
+			if fi.array {
+				value = getArrayParamValue(value)
+			} else if fi.point {
+				value = getPointParamValue(value)
+			} else if fi.codec != nil {
+				var err error
+				if value, err = fi.codec.encode(value); err != nil {
+					return 0, err
+				}
+			}
+			if err := fi.checkEnum(value); err != nil {
+				return 0, err
+			}
+			if fi.transformer != nil {
+				var err error
+				if value, err = fi.transformer.encode(value); err != nil {
+					return 0, err
+				}
+			}
 			cols = append(cols, col+" = ?")
+			setValues = append(setValues, value)
+			setFields = append(setFields, fi)
 		}
 	}
 
+	values = append(setValues, args...)
+	fields = append(setFields, fields...)
+
 	sets := strings.Join(cols, ", ") + " "
 
 	if d.ins.SupportUpdateJoin() {
-		query = fmt.Sprintf("UPDATE %s%s%s T0 %s%sSET %s%s", Q, mi.table, Q, specifyIndexes, join, sets, where)
+		query = fmt.Sprintf("UPDATE %s%s%s T0 %s%sSET %s%s", Q, table, Q, specifyIndexes, join, sets, where)
 	} else {
 		supQuery := fmt.Sprintf("SELECT T0.%s%s%s FROM %s%s%s T0 %s%s%s",
 			Q, mi.fields.pk.column, Q,
-			Q, mi.table, Q,
+			Q, table, Q,
 			specifyIndexes, join, where)
-		query = fmt.Sprintf("UPDATE %s%s%s SET %sWHERE %s%s%s IN ( %s )", Q, mi.table, Q, sets, Q, mi.fields.pk.column, Q, supQuery)
+		query = fmt.Sprintf("UPDATE %s%s%s SET %sWHERE %s%s%s IN ( %s )", Q, table, Q, sets, Q, mi.fields.pk.column, Q, supQuery)
 	}
 
 	d.ins.ReplaceMarks(&query)
-	res, err := q.ExecContext(ctx, query, values...)
+	res, err := q.ExecContext(withArgFields(ctx, fields), query, values...)
 	if err == nil {
 		return res.RowsAffected()
 	}
@@ -845,31 +1182,34 @@ func (d *dbBase) deleteRels(ctx context.Context, q dbQuerier, mi *modelInfo, arg
 
 // delete table-related records.
 func (d *dbBase) DeleteBatch(ctx context.Context, q dbQuerier, qs *querySet, mi *modelInfo, cond *Condition, tz *time.Location) (int64, error) {
+	table := getQsTable(qs, mi)
 	tables := newDbTables(mi, d.ins)
 	tables.skipEnd = true
 
 	var specifyIndexes string
 	if qs != nil {
 		tables.parseRelated(qs.related, qs.relDepth)
-		specifyIndexes = tables.getIndexSql(mi.table, qs.useIndex, qs.indexes)
+		specifyIndexes = tables.getIndexSql(table, qs.useIndex, qs.indexes)
 	}
 
-	if cond == nil || cond.IsEmpty() {
+	if cond == nil || cond.IsEmpty() && !cond.allowEmpty {
 		panic(fmt.Errorf("delete operation cannot execute without condition"))
 	}
 
 	Q := d.ins.TableQuote()
 
-	where, args := tables.getCondSQL(cond, false, tz)
+	where, args, fields := tables.getCondSQL(cond, false, tz)
 	join := tables.getJoinSQL()
 
 	cols := fmt.Sprintf("T0.%s%s%s", Q, mi.fields.pk.column, Q)
-	query := fmt.Sprintf("SELECT %s FROM %s%s%s T0 %s%s%s", cols, Q, mi.table, Q, specifyIndexes, join, where)
+	query := fmt.Sprintf("SELECT %s FROM %s%s%s T0 %s%s%s", cols, Q, table, Q, specifyIndexes, join, where)
 
 	d.ins.ReplaceMarks(&query)
 
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 	var rs *sql.Rows
-	r, err := q.QueryContext(ctx, query, args...)
+	r, err := q.QueryContext(withArgFields(ctx, fields), query, args...)
 	if err != nil {
 		return 0, err
 	}
@@ -878,6 +1218,7 @@ func (d *dbBase) DeleteBatch(ctx context.Context, q dbQuerier, qs *querySet, mi
 
 	var ref interface{}
 	args = make([]interface{}, 0)
+	fields = make([]*fieldInfo, 0)
 	cnt := 0
 	for rs.Next() {
 		if err := rs.Scan(&ref); err != nil {
@@ -888,6 +1229,7 @@ func (d *dbBase) DeleteBatch(ctx context.Context, q dbQuerier, qs *querySet, mi
 			return 0, err
 		}
 		args = append(args, pkValue)
+		fields = append(fields, mi.fields.pk)
 		cnt++
 	}
 
@@ -900,10 +1242,10 @@ func (d *dbBase) DeleteBatch(ctx context.Context, q dbQuerier, qs *querySet, mi
 		marks[i] = "?"
 	}
 	sqlIn := fmt.Sprintf("IN (%s)", strings.Join(marks, ", "))
-	query = fmt.Sprintf("DELETE FROM %s%s%s WHERE %s%s%s %s", Q, mi.table, Q, Q, mi.fields.pk.column, Q, sqlIn)
+	query = fmt.Sprintf("DELETE FROM %s%s%s WHERE %s%s%s %s", Q, table, Q, Q, mi.fields.pk.column, Q, sqlIn)
 
 	d.ins.ReplaceMarks(&query)
-	res, err := q.ExecContext(ctx, query, args...)
+	res, err := q.ExecContext(withArgFields(ctx, fields), query, args...)
 	if err == nil {
 		num, err := res.RowsAffected()
 		if err != nil {
@@ -922,6 +1264,7 @@ func (d *dbBase) DeleteBatch(ctx context.Context, q dbQuerier, qs *querySet, mi
 
 // read related records.
 func (d *dbBase) ReadBatch(ctx context.Context, q dbQuerier, qs *querySet, mi *modelInfo, cond *Condition, container interface{}, tz *time.Location, cols []string) (int64, error) {
+	table := getQsTable(qs, mi)
 	val := reflect.ValueOf(container)
 	ind := reflect.Indirect(val)
 
@@ -930,7 +1273,11 @@ func (d *dbBase) ReadBatch(ctx context.Context, q dbQuerier, qs *querySet, mi *m
 	isPtr := true
 	name := ""
 
-	if val.Kind() == reflect.Ptr {
+	handler, isHandler := container.(rowHandler)
+	if isHandler {
+		unregister = false
+		one = false
+	} else if val.Kind() == reflect.Ptr {
 		fn := ""
 		if ind.Kind() == reflect.Slice {
 			one = false
@@ -959,6 +1306,28 @@ func (d *dbBase) ReadBatch(ctx context.Context, q dbQuerier, qs *querySet, mi *m
 
 	Q := d.ins.TableQuote()
 
+	tables := newDbTables(mi, d.ins)
+	tables.parseRelated(qs.related, qs.relDepth)
+
+	var relCols map[string][]string
+	if len(qs.selectCols) > 0 {
+		selCols, rels, err := tables.getSelectColumns(qs.selectCols)
+		if err != nil {
+			return 0, err
+		}
+		if len(cols) == 0 {
+			cols = selCols
+		}
+		relCols = rels
+	}
+	// the columns to select of the related table
+	tblCols := func(tbl *dbTable) []string {
+		if c, ok := relCols[tbl.name]; ok {
+			return c
+		}
+		return tbl.mi.fields.dbcols
+	}
+
 	var tCols []string
 	if len(cols) > 0 {
 		hasRel := len(qs.related) > 0 || qs.relDepth > 0
@@ -994,21 +1363,24 @@ func (d *dbBase) ReadBatch(ctx context.Context, q dbQuerier, qs *querySet, mi *m
 	sep := fmt.Sprintf("%s, T0.%s", Q, Q)
 	sels := fmt.Sprintf("T0.%s%s%s", Q, strings.Join(tCols, sep), Q)
 
-	tables := newDbTables(mi, d.ins)
-	tables.parseRelated(qs.related, qs.relDepth)
-
-	where, args := tables.getCondSQL(cond, false, tz)
+	where, args, fields := tables.getCondSQL(cond, false, tz)
 	groupBy := tables.getGroupSQL(qs.groups)
-	orderBy := tables.getOrderSQL(qs.orders)
+	having, hargs, err := tables.getHavingSQL(qs.aggregate, qs.havings, tz)
+	if err != nil {
+		return 0, err
+	}
+	args = append(args, hargs...)
+	fields = append(fields, make([]*fieldInfo, len(hargs))...)
+	orderBy := tables.getOrderSQL(qs.orders, qs.orderRandom)
 	limit := tables.getLimitSQL(mi, offset, rlimit)
 	join := tables.getJoinSQL()
-	specifyIndexes := tables.getIndexSql(mi.table, qs.useIndex, qs.indexes)
+	specifyIndexes := tables.getIndexSql(table, qs.useIndex, qs.indexes)
 
 	for _, tbl := range tables.tables {
 		if tbl.sel {
-			colsNum += len(tbl.mi.fields.dbcols)
+			colsNum += len(tblCols(tbl))
 			sep := fmt.Sprintf("%s, %s.%s", Q, tbl.index, Q)
-			sels += fmt.Sprintf(", %s.%s%s%s", tbl.index, Q, strings.Join(tbl.mi.fields.dbcols, sep), Q)
+			sels += fmt.Sprintf(", %s.%s%s%s", tbl.index, Q, strings.Join(tblCols(tbl), sep), Q)
 		}
 	}
 
@@ -1019,18 +1391,33 @@ func (d *dbBase) ReadBatch(ctx context.Context, q dbQuerier, qs *querySet, mi *m
 	if qs.aggregate != "" {
 		sels = qs.aggregate
 	}
-	query := fmt.Sprintf("%s %s FROM %s%s%s T0 %s%s%s%s%s%s",
-		sqlSelect, sels, Q, mi.table, Q,
-		specifyIndexes, join, where, groupBy, orderBy, limit)
+	// the total count of rows without limit is selected as the last column
+	if qs.total != nil {
+		sels += ", COUNT(*) OVER()"
+	}
+	query := fmt.Sprintf("%s %s FROM %s%s%s T0 %s%s%s%s%s%s%s",
+		sqlSelect, sels, Q, table, Q,
+		specifyIndexes, join, where, groupBy, having, orderBy, limit)
 
 	if qs.forUpdate {
 		query += " FOR UPDATE"
+		if qs.noWait {
+			if !isNoWaitSupported(qs.orm.alias.Driver) {
+				return 0, ErrNotImplement
+			}
+			query += " NOWAIT"
+		}
 	}
 
 	d.ins.ReplaceMarks(&query)
 
+	ctx, cancel := withQueryTimeout(withArgFields(ctx, fields))
+	defer cancel()
 	rs, err := q.QueryContext(ctx, query, args...)
 	if err != nil {
+		if qs.noWait {
+			err = convertLockError(err)
+		}
 		return 0, err
 	}
 
@@ -1042,6 +1429,9 @@ func (d *dbBase) ReadBatch(ctx context.Context, q dbQuerier, qs *querySet, mi *m
 		tCols = mi.fields.dbcols
 		colsNum = len(tCols)
 	}
+	if qs.total != nil {
+		colsNum++
+	}
 
 	refs := make([]interface{}, colsNum)
 	for i := range refs {
@@ -1054,6 +1444,12 @@ func (d *dbBase) ReadBatch(ctx context.Context, q dbQuerier, qs *querySet, mi *m
 			if err := rs.Scan(refs...); err != nil {
 				return 0, err
 			}
+			if qs.total != nil {
+				total := reflect.ValueOf(refs[colsNum-1]).Elem().Interface()
+				if *qs.total, err = StrTo(ToStr(total)).Int64(); err != nil {
+					return 0, err
+				}
+			}
 
 			elm := reflect.New(mi.addrField.Elem().Type())
 			mind := reflect.Indirect(elm)
@@ -1085,7 +1481,7 @@ func (d *dbBase) ReadBatch(ctx context.Context, q dbQuerier, qs *querySet, mi *m
 							if last.Kind() != reflect.Invalid {
 								field = reflect.Indirect(last.FieldByIndex(fi.fieldIndex))
 								if field.IsValid() {
-									d.setColsValues(mmi, &field, mmi.fields.dbcols, trefs[:len(mmi.fields.dbcols)], tz)
+									d.setColsValues(mmi, &field, tblCols(tbl), trefs[:len(tblCols(tbl))], tz)
 									for _, fi := range mmi.fields.fieldsReverse {
 										if fi.inModel && fi.reverseFieldInfo.mi == lastm {
 											if fi.reverseFieldInfo != nil {
@@ -1103,11 +1499,15 @@ func (d *dbBase) ReadBatch(ctx context.Context, q dbQuerier, qs *querySet, mi *m
 							cacheM[names] = mmi
 						}
 					}
-					trefs = trefs[len(mmi.fields.dbcols):]
+					trefs = trefs[len(tblCols(tbl)):]
 				}
 			}
 
-			if one {
+			if isHandler {
+				if err := handler(mind.Addr().Interface()); err != nil {
+					return cnt, err
+				}
+			} else if one {
 				ind.Set(mind)
 			} else {
 				if cnt == 0 {
@@ -1130,6 +1530,10 @@ func (d *dbBase) ReadBatch(ctx context.Context, q dbQuerier, qs *querySet, mi *m
 		cnt++
 	}
 
+	if isHandler {
+		return cnt, rs.Err()
+	}
+
 	if !one {
 		if cnt > 0 {
 			ind.Set(slice)
@@ -1147,20 +1551,27 @@ func (d *dbBase) ReadBatch(ctx context.Context, q dbQuerier, qs *querySet, mi *m
 
 // excute count sql and return count result int64.
 func (d *dbBase) Count(ctx context.Context, q dbQuerier, qs *querySet, mi *modelInfo, cond *Condition, tz *time.Location) (cnt int64, err error) {
+	table := getQsTable(qs, mi)
 	tables := newDbTables(mi, d.ins)
 	tables.parseRelated(qs.related, qs.relDepth)
 
-	where, args := tables.getCondSQL(cond, false, tz)
+	where, args, fields := tables.getCondSQL(cond, false, tz)
 	groupBy := tables.getGroupSQL(qs.groups)
-	tables.getOrderSQL(qs.orders)
+	having, hargs, err := tables.getHavingSQL(qs.aggregate, qs.havings, tz)
+	if err != nil {
+		return 0, err
+	}
+	args = append(args, hargs...)
+	fields = append(fields, make([]*fieldInfo, len(hargs))...)
+	tables.getOrderSQL(qs.orders, qs.orderRandom)
 	join := tables.getJoinSQL()
-	specifyIndexes := tables.getIndexSql(mi.table, qs.useIndex, qs.indexes)
+	specifyIndexes := tables.getIndexSql(table, qs.useIndex, qs.indexes)
 
 	Q := d.ins.TableQuote()
 
-	query := fmt.Sprintf("SELECT COUNT(*) FROM %s%s%s T0 %s%s%s%s",
-		Q, mi.table, Q,
-		specifyIndexes, join, where, groupBy)
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s%s%s T0 %s%s%s%s%s",
+		Q, table, Q,
+		specifyIndexes, join, where, groupBy, having)
 
 	if groupBy != "" {
 		query = fmt.Sprintf("SELECT COUNT(*) FROM (%s) AS T", query)
@@ -1168,19 +1579,112 @@ func (d *dbBase) Count(ctx context.Context, q dbQuerier, qs *querySet, mi *model
 
 	d.ins.ReplaceMarks(&query)
 
+	ctx, cancel := withQueryTimeout(withArgFields(ctx, fields))
+	defer cancel()
 	row := q.QueryRowContext(ctx, query, args...)
-	err = row.Scan(&cnt)
+	err = wrapQueryError(ctx, query, args, row.Scan(&cnt))
 	return
 }
 
+// query the aggregate function fn of the column expr, e.g. SUM(T0.`age`), and scan it into result.
+// fn can be followed by DISTINCT, e.g. COUNT DISTINCT for COUNT(DISTINCT T0.`age`).
+// result is set to zero value if the aggregate value is NULL.
+func (d *dbBase) AggregateColumn(ctx context.Context, q dbQuerier, qs *querySet, mi *modelInfo, cond *Condition, fn string, expr string, result interface{}, tz *time.Location) error {
+	val := reflect.ValueOf(result)
+	if val.Kind() != reflect.Ptr || val.IsNil() {
+		return fmt.Errorf("%w, result of %s must be a non-nil pointer", ErrArgs, fn)
+	}
+	ind := val.Elem()
+
+	table := getQsTable(qs, mi)
+	tables := newDbTables(mi, d.ins)
+	tables.parseRelated(qs.related, qs.relDepth)
+
+	index, _, fi, suc := tables.parseExprs(mi, strings.Split(expr, ExprSep))
+	if !suc || !fi.dbcol {
+		return fmt.Errorf("%w `%s` for model `%s`", ErrWrongColumn, expr, mi.fullName)
+	}
+
+	where, args, fields := tables.getCondSQL(cond, false, tz)
+	join := tables.getJoinSQL()
+	specifyIndexes := tables.getIndexSql(table, qs.useIndex, qs.indexes)
+
+	Q := d.ins.TableQuote()
+
+	name, distinct := fn, ""
+	if strings.HasSuffix(fn, " DISTINCT") {
+		name, distinct = strings.TrimSuffix(fn, " DISTINCT"), "DISTINCT "
+	}
+	query := fmt.Sprintf("SELECT %s(%s%s.%s%s%s) FROM %s%s%s T0 %s%s%s",
+		name, distinct, index, Q, fi.column, Q,
+		Q, table, Q,
+		specifyIndexes, join, where)
+
+	d.ins.ReplaceMarks(&query)
+
+	ctx, cancel := withQueryTimeout(withArgFields(ctx, fields))
+	defer cancel()
+	row := q.QueryRowContext(ctx, query, args...)
+
+	// time may be returned as string, convert it as the field
+	if _, ok := result.(*time.Time); ok {
+		var ref interface{}
+		if err := row.Scan(&ref); err != nil {
+			return wrapQueryError(ctx, query, args, err)
+		}
+		if ref == nil {
+			ind.Set(reflect.Zero(ind.Type()))
+			return nil
+		}
+		value, err := d.convertValueFromDB(fi, ref, tz)
+		if err != nil {
+			return err
+		}
+		t, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("%s of `%s` is not time", fn, expr)
+		}
+		ind.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	// scan into **T, nil for NULL
+	ref := reflect.New(val.Type())
+	if err := row.Scan(ref.Interface()); err != nil {
+		return wrapQueryError(ctx, query, args, err)
+	}
+	if ref.Elem().IsNil() {
+		ind.Set(reflect.Zero(ind.Type()))
+	} else {
+		ind.Set(ref.Elem().Elem())
+	}
+	return nil
+}
+
 // generate sql with replacing operator string placeholders and replaced values.
 func (d *dbBase) GenerateOperatorSQL(mi *modelInfo, fi *fieldInfo, operator string, args []interface{}, tz *time.Location) (string, []interface{}) {
+	// array is compared as a whole
+	if fi.array && operator == "exact" {
+		return d.ins.OperatorSQL(operator), []interface{}{getArrayArgsValue(args)}
+	}
+
 	var sql string
 	params := getFlatParams(fi, args, tz)
 
 	if len(params) == 0 {
 		panic(fmt.Errorf("operator `%s` need at least one args", operator))
 	}
+
+	// only exact match is possible for transformed values
+	if fi.transformer != nil && (operator == "exact" || operator == "in") {
+		for i, p := range params {
+			v, err := fi.transformer.encode(p)
+			if err != nil {
+				panic(err)
+			}
+			params[i] = v
+		}
+	}
 	arg := params[0]
 
 	switch operator {
@@ -1266,6 +1770,26 @@ func (d *dbBase) convertValueFromDB(fi *fieldInfo, val interface{}, tz *time.Loc
 		return nil, nil
 	}
 
+	// the expression which isn't a field, e.g. GROUP_CONCAT
+	if fi == nil {
+		if b, ok := val.([]byte); ok {
+			return string(b), nil
+		}
+		return val, nil
+	}
+
+	if fi.transformer != nil {
+		var err error
+		if val, err = fi.transformer.decode(val); err != nil {
+			return nil, err
+		}
+	}
+
+	// the raw value is converted by sql.Scanner or decoded by codec of the field
+	if fi.isScanner || fi.codec != nil {
+		return val, nil
+	}
+
 	var value interface{}
 	var tErr error
 
@@ -1429,6 +1953,34 @@ func (d *dbBase) setFieldValue(fi *fieldInfo, value interface{}, field reflect.V
 	fieldType := fi.fieldType
 	isNative := !fi.isFielder
 
+	if fi.array {
+		if err := setArrayFieldValue(field, value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+
+	if fi.point {
+		if err := setPointFieldValue(field, value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+
+	if fi.codec != nil {
+		if err := fi.codec.decode(field, value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+
+	if fi.isScanner {
+		if err := setScannerFieldValue(field, value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+
 setValue:
 	switch {
 	case fieldType == TypeBooleanField:
@@ -1643,66 +2195,29 @@ func (d *dbBase) ReadValues(ctx context.Context, q dbQuerier, qs *querySet, mi *
 			list = d
 		}
 		typ = 3
+	case typedParamsLists:
+		d := *v.lists
+		if len(d) == 0 {
+			lists = d
+		}
+		typ = 4
 	default:
 		panic(fmt.Errorf("unsupport read values type `%T`", container))
 	}
 
-	tables := newDbTables(mi, d.ins)
-
-	var (
-		cols  []string
-		infos []*fieldInfo
-	)
-
-	hasExprs := len(exprs) > 0
-
-	Q := d.ins.TableQuote()
-
-	if hasExprs {
-		cols = make([]string, 0, len(exprs))
-		infos = make([]*fieldInfo, 0, len(exprs))
-		for _, ex := range exprs {
-			index, name, fi, suc := tables.parseExprs(mi, strings.Split(ex, ExprSep))
-			if !suc {
-				panic(fmt.Errorf("unknown field/column name `%s`", ex))
-			}
-			cols = append(cols, fmt.Sprintf("%s.%s%s%s %s%s%s", index, Q, fi.column, Q, Q, name, Q))
-			infos = append(infos, fi)
-		}
-	} else {
-		cols = make([]string, 0, len(mi.fields.dbcols))
-		infos = make([]*fieldInfo, 0, len(exprs))
-		for _, fi := range mi.fields.fieldsDB {
-			cols = append(cols, fmt.Sprintf("T0.%s%s%s %s%s%s", Q, fi.column, Q, Q, fi.name, Q))
-			infos = append(infos, fi)
-		}
-	}
-
-	where, args := tables.getCondSQL(cond, false, tz)
-	groupBy := tables.getGroupSQL(qs.groups)
-	orderBy := tables.getOrderSQL(qs.orders)
-	limit := tables.getLimitSQL(mi, qs.offset, qs.limit)
-	join := tables.getJoinSQL()
-	specifyIndexes := tables.getIndexSql(mi.table, qs.useIndex, qs.indexes)
-
-	sels := strings.Join(cols, ", ")
-
-	sqlSelect := "SELECT"
-	if qs.distinct {
-		sqlSelect += " DISTINCT"
+	query, args, fields, infos, err := d.ins.ValuesSQL(qs, mi, cond, exprs, tz, false)
+	if err != nil {
+		return 0, err
 	}
-	query := fmt.Sprintf("%s %s FROM %s%s%s T0 %s%s%s%s%s%s",
-		sqlSelect, sels,
-		Q, mi.table, Q,
-		specifyIndexes, join, where, groupBy, orderBy, limit)
-
 	d.ins.ReplaceMarks(&query)
 
+	ctx, cancel := withQueryTimeout(withArgFields(ctx, fields))
+	defer cancel()
 	rs, err := q.QueryContext(ctx, query, args...)
 	if err != nil {
 		return 0, err
 	}
-	refs := make([]interface{}, len(cols))
+	refs := make([]interface{}, len(infos))
 	for i := range refs {
 		var ref interface{}
 		refs[i] = &ref
@@ -1729,7 +2244,7 @@ func (d *dbBase) ReadValues(ctx context.Context, q dbQuerier, qs *querySet, mi *
 
 		switch typ {
 		case 1:
-			params := make(Params, len(cols))
+			params := make(Params, len(infos))
 			for i, ref := range refs {
 				fi := infos[i]
 
@@ -1744,7 +2259,7 @@ func (d *dbBase) ReadValues(ctx context.Context, q dbQuerier, qs *querySet, mi *
 			}
 			maps = append(maps, params)
 		case 2:
-			params := make(ParamsList, 0, len(cols))
+			params := make(ParamsList, 0, len(infos))
 			for i, ref := range refs {
 				fi := infos[i]
 
@@ -1771,6 +2286,24 @@ func (d *dbBase) ReadValues(ctx context.Context, q dbQuerier, qs *querySet, mi *
 
 				list = append(list, value)
 			}
+		case 4:
+			params := make(ParamsList, 0, len(infos))
+			for i, ref := range refs {
+				fi := infos[i]
+
+				val := reflect.Indirect(reflect.ValueOf(ref)).Interface()
+
+				value, err := d.convertValueFromDB(fi, val, tz)
+				if err != nil {
+					panic(fmt.Errorf("db value convert failed `%v` %s", val, err.Error()))
+				}
+				if value, err = d.typedValue(fi, value); err != nil {
+					return 0, err
+				}
+
+				params = append(params, value)
+			}
+			lists = append(lists, params)
 		}
 
 		cnt++
@@ -1783,16 +2316,129 @@ func (d *dbBase) ReadValues(ctx context.Context, q dbQuerier, qs *querySet, mi *
 		*v = lists
 	case *ParamsList:
 		*v = list
+	case typedParamsLists:
+		*v.lists = lists
 	}
 
 	return cnt, nil
 }
 
+// ValuesSQL return the select sql of ReadValues, the fields bound to the args and the fields of the selected columns,
+// the columns are named by the field names, or by the exprs and the column names if rawNames is true.
+// the placeholders in sql are not replaced.
+func (d *dbBase) ValuesSQL(qs *querySet, mi *modelInfo, cond *Condition, exprs []string, tz *time.Location, rawNames bool) (string, []interface{}, []*fieldInfo, []*fieldInfo, error) {
+	table := getQsTable(qs, mi)
+	tables := newDbTables(mi, d.ins)
+
+	var (
+		cols  []string
+		infos []*fieldInfo
+	)
+
+	hasExprs := len(exprs) > 0
+
+	Q := d.ins.TableQuote()
+
+	if hasExprs {
+		cols = make([]string, 0, len(exprs))
+		infos = make([]*fieldInfo, 0, len(exprs))
+		for _, ex := range exprs {
+			index, name, fi, suc := tables.parseExprs(mi, strings.Split(ex, ExprSep))
+			if !suc {
+				panic(fmt.Errorf("unknown field/column name `%s`", ex))
+			}
+			if rawNames {
+				name = ex
+			}
+			cols = append(cols, fmt.Sprintf("%s.%s%s%s %s%s%s", index, Q, fi.column, Q, Q, name, Q))
+			infos = append(infos, fi)
+		}
+	} else {
+		cols = make([]string, 0, len(mi.fields.dbcols))
+		infos = make([]*fieldInfo, 0, len(exprs))
+		for _, fi := range mi.fields.fieldsDB {
+			name := fi.name
+			if rawNames {
+				name = fi.column
+			}
+			cols = append(cols, fmt.Sprintf("T0.%s%s%s %s%s%s", Q, fi.column, Q, Q, name, Q))
+			infos = append(infos, fi)
+		}
+	}
+
+	// the values of group concat are not converted by field
+	for _, gc := range qs.concats {
+		index, _, fi, suc := tables.parseExprs(mi, strings.Split(gc.col, ExprSep))
+		if !suc {
+			panic(fmt.Errorf("unknown field/column name `%s`", gc.col))
+		}
+		expr, err := d.ins.GenerateGroupConcatSQL(fmt.Sprintf("%s.%s%s%s", index, Q, fi.column, Q), gc.sep)
+		if err != nil {
+			return "", nil, nil, nil, fmt.Errorf("<QuerySeter.GroupConcat> %w", err)
+		}
+		cols = append(cols, fmt.Sprintf("%s %s", expr, quoteIdentifier(gc.alias, Q)))
+		infos = append(infos, nil)
+	}
+
+	where, args, fields := tables.getCondSQL(cond, false, tz)
+	groupBy := tables.getGroupSQL(qs.groups)
+	having, hargs, err := tables.getHavingSQL(qs.aggregate, qs.havings, tz)
+	if err != nil {
+		return "", nil, nil, nil, err
+	}
+	args = append(args, hargs...)
+	fields = append(fields, make([]*fieldInfo, len(hargs))...)
+	orderBy := tables.getOrderSQL(qs.orders, qs.orderRandom)
+	limit := tables.getLimitSQL(mi, qs.offset, qs.limit)
+	join := tables.getJoinSQL()
+	specifyIndexes := tables.getIndexSql(table, qs.useIndex, qs.indexes)
+
+	sels := strings.Join(cols, ", ")
+
+	sqlSelect := "SELECT"
+	if qs.distinct {
+		sqlSelect += " DISTINCT"
+	}
+	query := fmt.Sprintf("%s %s FROM %s%s%s T0 %s%s%s%s%s%s%s",
+		sqlSelect, sels,
+		Q, table, Q,
+		specifyIndexes, join, where, groupBy, having, orderBy, limit)
+
+	return query, args, fields, infos, nil
+}
+
+// the container of ReadValues which values are converted to the go types of fields
+type typedParamsLists struct {
+	lists *[]ParamsList
+}
+
+// convert the value from db to the go type of field,
+// the value of relation field is converted to the type of pk of related model.
+func (d *dbBase) typedValue(fi *fieldInfo, value interface{}) (interface{}, error) {
+	if value == nil || fi == nil {
+		return value, nil
+	}
+	if fi.fieldType&IsRelField > 0 {
+		fi = fi.relModelInfo.fields.pk
+	}
+	typ := fi.mi.addrField.Elem().Type().FieldByIndex(fi.fieldIndex).Type
+	field := reflect.New(typ).Elem()
+	if _, err := d.setFieldValue(fi, value, field); err != nil {
+		return nil, err
+	}
+	return field.Interface(), nil
+}
+
 // flag of update joined record.
 func (d *dbBase) SupportUpdateJoin() bool {
 	return true
 }
 
+// flag of transaction isolation level, let the driver decide by default.
+func (d *dbBase) SupportIsolationLevel(level sql.IsolationLevel) bool {
+	return true
+}
+
 func (d *dbBase) MaxLimit() uint64 {
 	return 18446744073709551615
 }
@@ -1802,6 +2448,21 @@ func (d *dbBase) TableQuote() string {
 	return "`"
 }
 
+// set the dbBaser which the sql building methods dispatch to
+func (d *dbBase) setIns(ins dbBaser) {
+	d.ins = ins
+}
+
+// check whether err means the connection is lost.
+func (d *dbBase) IsConnError(err error) bool {
+	if errors.Is(err, sqldriver.ErrBadConn) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNABORTED) {
+		return true
+	}
+	var netErr *net.OpError
+	return errors.As(err, &netErr) && !netErr.Timeout()
+}
+
 // replace value placeholder in parametered sql string.
 func (d *dbBase) ReplaceMarks(query *string) {
 	// default use `?` as mark, do nothing
@@ -1812,6 +2473,20 @@ func (d *dbBase) HasReturningID(*modelInfo, *string) bool {
 	return false
 }
 
+// scan the id returned by RETURNING sql.
+// unsigned pk is scanned as uint64, so the ids larger than math.MaxInt64 are not overflowed,
+// the returned int64 keeps the same bits and setPk converts it back to uint64.
+func scanReturningID(row interface{ Scan(...interface{}) error }, mi *modelInfo) (int64, error) {
+	if mi.fields.pk != nil && mi.fields.pk.fieldType&IsPositiveIntegerField > 0 {
+		var id uint64
+		err := row.Scan(&id)
+		return int64(id), err
+	}
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
 // sync auto key
 func (d *dbBase) setval(ctx context.Context, db dbQuerier, mi *modelInfo, autoFields []string) error {
 	return nil
@@ -1904,6 +2579,43 @@ func (d *dbBase) IndexExists(context.Context, dbQuerier, string, string) bool {
 	panic(ErrNotImplement)
 }
 
+// GenerateOrderNulls return the order clause of column with the position of NULL values
+func (d *dbBase) GenerateOrderNulls(column string, sort string, nulls order_clause.Nulls) string {
+	clause := order_clause.Clause(order_clause.WithNulls(nulls))
+	return strings.TrimSpace(fmt.Sprintf("%s %s %s", column, sort, clause.NullsString()))
+}
+
+// GenerateRandomOrder return the expression to sort the rows randomly
+func (d *dbBase) GenerateRandomOrder() string {
+	return "RANDOM()"
+}
+
+// GenerateFullTextSQL return the full text search predicate on the columns,
+// the placeholder is bound to the search query.
+func (d *dbBase) GenerateFullTextSQL(columns []string) (string, error) {
+	return "", ErrNotImplement
+}
+
+// GenerateGroupConcatSQL return the aggregate expression joining the values of column by sep.
+func (d *dbBase) GenerateGroupConcatSQL(column string, sep string) (string, error) {
+	return "", ErrNotImplement
+}
+
+// GenerateResetAutoIncrementSQL return the statement restarting the auto increment column of table from 1.
+func (d *dbBase) GenerateResetAutoIncrementSQL(table string, column string) (string, error) {
+	return "", ErrNotImplement
+}
+
+// quote s as the sql string literal
+func quoteStringLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// quote s as an identifier by the quote Q, the quotes in s are doubled.
+func quoteIdentifier(s string, Q string) string {
+	return Q + strings.ReplaceAll(s, Q, Q+Q) + Q
+}
+
 // GenerateSpecifyIndex return a specifying index clause
 func (d *dbBase) GenerateSpecifyIndex(tableName string, useIndex int, indexes []string) string {
 	var s []string
@@ -17,8 +17,10 @@ package orm
 import (
 	"context"
 	"database/sql"
+	sqldriver "database/sql/driver"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	lru "github.com/hashicorp/golang-lru"
@@ -105,11 +107,46 @@ func (ac *_dbCache) getDefault() (al *alias) {
 	return
 }
 
+// SQLRewriter rewrites sql before it is prepared or executed.
+// op is one of "Prepare", "Exec", "Query" and "QueryRow".
+type SQLRewriter func(op, query string) string
+
+// the registered rewriters, the []SQLRewriter is copied on registration
+var (
+	sqlRewriters   atomic.Value
+	sqlRewritersMu sync.Mutex
+)
+
+// RegisterSQLRewriter register a SQLRewriter applied to every sql,
+// both generated by orm and passed to Raw.
+// rewriters are applied in the order of registration.
+func RegisterSQLRewriter(rewriter SQLRewriter) {
+	sqlRewritersMu.Lock()
+	defer sqlRewritersMu.Unlock()
+	rewriters := getSQLRewriters()
+	sqlRewriters.Store(append(rewriters[:len(rewriters):len(rewriters)], rewriter))
+}
+
+func getSQLRewriters() []SQLRewriter {
+	rewriters, _ := sqlRewriters.Load().([]SQLRewriter)
+	return rewriters
+}
+
+func rewriteSQL(op, query string) string {
+	for _, rewriter := range getSQLRewriters() {
+		query = rewriter(op, query)
+	}
+	return query
+}
+
 type DB struct {
 	*sync.RWMutex
 	DB                  *sql.DB
 	stmtDecorators      *lru.Cache
 	stmtDecoratorsLimit int
+	isConnError         func(error) bool
+	reconnect           bool
+	reconnectWrites     bool
 }
 
 var (
@@ -117,6 +154,39 @@ var (
 	_ txer      = new(DB)
 )
 
+// get the querier which executes the sql directly instead of the cached prepared statements,
+// the query log and scan guard wrappers are kept.
+// q is returned if it doesn't cache statements, e.g. the transaction.
+func noStmtCacheQuerier(q dbQuerier) dbQuerier {
+	switch d := q.(type) {
+	case *DB:
+		if d.stmtDecorators == nil {
+			return q
+		}
+		nd := *d
+		nd.stmtDecorators = nil
+		nd.stmtDecoratorsLimit = 0
+		return &nd
+	case *dbQueryLog:
+		db := noStmtCacheQuerier(d.db)
+		if db == d.db {
+			return q
+		}
+		nd := *d
+		nd.db = db
+		return &nd
+	case *scanGuard:
+		db := noStmtCacheQuerier(d.db)
+		if db == d.db {
+			return q
+		}
+		nd := *d
+		nd.db = db
+		return &nd
+	}
+	return q
+}
+
 func (d *DB) Begin() (*sql.Tx, error) {
 	return d.DB.Begin()
 }
@@ -144,7 +214,7 @@ func (d *DB) getStmtDecorator(query string) (*stmtDecorator, error) {
 		return c.(*stmtDecorator), nil
 	}
 
-	stmt, err := d.Prepare(query)
+	stmt, err := d.DB.Prepare(query)
 	if err != nil {
 		d.Unlock()
 		return nil, err
@@ -158,11 +228,11 @@ func (d *DB) getStmtDecorator(query string) (*stmtDecorator, error) {
 }
 
 func (d *DB) Prepare(query string) (*sql.Stmt, error) {
-	return d.DB.Prepare(query)
+	return d.DB.Prepare(rewriteSQL("Prepare", query))
 }
 
 func (d *DB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
-	return d.DB.PrepareContext(ctx, query)
+	return d.DB.PrepareContext(ctx, rewriteSQL("Prepare", query))
 }
 
 func (d *DB) Exec(query string, args ...interface{}) (sql.Result, error) {
@@ -170,6 +240,18 @@ func (d *DB) Exec(query string, args ...interface{}) (sql.Result, error) {
 }
 
 func (d *DB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+	defer d.measureConnWait()()
+	query = rewriteSQL("Exec", query)
+	res, err := d.execContext(ctx, query, args...)
+	if d.shouldReconnect(ctx, query, err) {
+		res, err = d.execContext(ctx, query, args...)
+	}
+	return res, wrapQueryError(ctx, query, args, err)
+}
+
+func (d *DB) execContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
 	if d.stmtDecorators == nil {
 		return d.DB.ExecContext(ctx, query, args...)
 	}
@@ -188,6 +270,17 @@ func (d *DB) Query(query string, args ...interface{}) (*sql.Rows, error) {
 }
 
 func (d *DB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	// the rows are read after return, the callers apply the timeout and cancel it after closing the rows
+	defer d.measureConnWait()()
+	query = rewriteSQL("Query", query)
+	rows, err := d.queryContext(ctx, query, args...)
+	if d.shouldReconnect(ctx, query, err) {
+		rows, err = d.queryContext(ctx, query, args...)
+	}
+	return rows, wrapQueryError(ctx, query, args, err)
+}
+
+func (d *DB) queryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
 	if d.stmtDecorators == nil {
 		return d.DB.QueryContext(ctx, query, args...)
 	}
@@ -206,6 +299,17 @@ func (d *DB) QueryRow(query string, args ...interface{}) *sql.Row {
 }
 
 func (d *DB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	// the row is scanned after return, the callers apply the timeout and cancel it after the scan
+	defer d.measureConnWait()()
+	query = rewriteSQL("QueryRow", query)
+	row := d.queryRowContext(ctx, query, args...)
+	if d.shouldReconnect(ctx, query, row.Err()) {
+		row = d.queryRowContext(ctx, query, args...)
+	}
+	return row
+}
+
+func (d *DB) queryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
 	if d.stmtDecorators == nil {
 		return d.DB.QueryRowContext(ctx, query, args...)
 	}
@@ -221,6 +325,8 @@ func (d *DB) QueryRowContext(ctx context.Context, query string, args ...interfac
 
 type TxDB struct {
 	tx *sql.Tx
+	// committed or rolled back
+	done bool
 }
 
 var (
@@ -229,14 +335,17 @@ var (
 )
 
 func (t *TxDB) Commit() error {
+	t.done = true
 	return t.tx.Commit()
 }
 
 func (t *TxDB) Rollback() error {
+	t.done = true
 	return t.tx.Rollback()
 }
 
 func (t *TxDB) RollbackUnlessCommit() error {
+	t.done = true
 	err := t.tx.Rollback()
 	if err != sql.ErrTxDone {
 		return err
@@ -254,7 +363,7 @@ func (t *TxDB) Prepare(query string) (*sql.Stmt, error) {
 }
 
 func (t *TxDB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
-	return t.tx.PrepareContext(ctx, query)
+	return t.tx.PrepareContext(ctx, rewriteSQL("Prepare", query))
 }
 
 func (t *TxDB) Exec(query string, args ...interface{}) (sql.Result, error) {
@@ -262,7 +371,11 @@ func (t *TxDB) Exec(query string, args ...interface{}) (sql.Result, error) {
 }
 
 func (t *TxDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
-	return t.tx.ExecContext(ctx, query, args...)
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+	query = rewriteSQL("Exec", query)
+	res, err := t.tx.ExecContext(ctx, query, args...)
+	return res, wrapQueryError(ctx, query, args, err)
 }
 
 func (t *TxDB) Query(query string, args ...interface{}) (*sql.Rows, error) {
@@ -270,7 +383,9 @@ func (t *TxDB) Query(query string, args ...interface{}) (*sql.Rows, error) {
 }
 
 func (t *TxDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
-	return t.tx.QueryContext(ctx, query, args...)
+	query = rewriteSQL("Query", query)
+	rows, err := t.tx.QueryContext(ctx, query, args...)
+	return rows, wrapQueryError(ctx, query, args, err)
 }
 
 func (t *TxDB) QueryRow(query string, args ...interface{}) *sql.Row {
@@ -278,7 +393,7 @@ func (t *TxDB) QueryRow(query string, args ...interface{}) *sql.Row {
 }
 
 func (t *TxDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
-	return t.tx.QueryRowContext(ctx, query, args...)
+	return t.tx.QueryRowContext(ctx, rewriteSQL("QueryRow", query), args...)
 }
 
 type alias struct {
@@ -289,11 +404,16 @@ type alias struct {
 	MaxIdleConns    int
 	MaxOpenConns    int
 	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
 	StmtCacheSize   int
 	DB              *DB
 	DbBaser         dbBaser
 	TZ              *time.Location
 	Engine          string
+	ScanGuard       bool
+	ScanGuardRows   int64
+	// set if the database rejects the window function, e.g. mysql 5.7 or sqlite before 3.25
+	noWindowFunc int32
 }
 
 func detectTZ(al *alias) {
@@ -401,7 +521,11 @@ func newAliasWithDb(aliasName, driverName string, db *sql.DB, params ...DBOption
 
 	if dr, ok := drivers[driverName]; ok {
 		al.DbBaser = dbBasers[dr]
+		if style, ok := driverPlaceholders[driverName]; ok {
+			al.DbBaser = newdbBasePlaceholder(dr, style)
+		}
 		al.Driver = dr
+		al.DB.isConnError = al.DbBaser.IsConnError
 	} else {
 		return nil, fmt.Errorf("driver name `%s` have not registered", driverName)
 	}
@@ -447,6 +571,41 @@ func (al *alias) SetConnMaxLifetime(lifeTime time.Duration) {
 	al.DB.DB.SetConnMaxLifetime(lifeTime)
 }
 
+func (al *alias) SetConnMaxIdleTime(idleTime time.Duration) {
+	al.ConnMaxIdleTime = idleTime
+	al.DB.DB.SetConnMaxIdleTime(idleTime)
+}
+
+// ConnPoolConfig is the connection pool settings of database.
+// the zero value keeps the current setting, the negative value means no limit.
+type ConnPoolConfig struct {
+	MaxIdleConns    int
+	MaxOpenConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+// SetConnPoolConfig Change the connection pool settings of the database alias, it takes effect immediately.
+func SetConnPoolConfig(aliasName string, cfg ConnPoolConfig) error {
+	al, ok := dataBaseCache.get(aliasName)
+	if !ok {
+		return fmt.Errorf("unknown DataBase alias name %s", aliasName)
+	}
+	if cfg.MaxIdleConns != 0 {
+		al.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+	if cfg.MaxOpenConns != 0 {
+		al.SetMaxOpenConns(cfg.MaxOpenConns)
+	}
+	if cfg.ConnMaxLifetime != 0 {
+		al.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	}
+	if cfg.ConnMaxIdleTime != 0 {
+		al.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+	}
+	return nil
+}
+
 // AddAliasWthDB add a aliasName for the drivename
 func AddAliasWthDB(aliasName, driverName string, db *sql.DB, params ...DBOption) error {
 	_, err := addAliasWthDB(aliasName, driverName, db, params...)
@@ -461,7 +620,15 @@ func RegisterDataBase(aliasName, driverName, dataSource string, params ...DBOpti
 		al  *alias
 	)
 
-	db, err = sql.Open(driverName, dataSource)
+	if _, ok := connInitializers.Load(aliasName); ok {
+		var connector sqldriver.Connector
+		connector, err = newDsnConnector(driverName, dataSource)
+		if err == nil {
+			db = sql.OpenDB(getConnInitConnector(aliasName, connector))
+		}
+	} else {
+		db, err = sql.Open(driverName, dataSource)
+	}
 	if err != nil {
 		err = fmt.Errorf("register db `%s`, %s", aliasName, err.Error())
 		goto end
@@ -485,6 +652,22 @@ end:
 	return err
 }
 
+// RegisterDataBaseWithConnector Setting the database with connector instead of dataSource,
+// e.g. the connector which rotates the credentials of IAM authentication.
+// driverName is used to find the driver type registered by RegisterDriver.
+func RegisterDataBaseWithConnector(aliasName, driverName string, connector sqldriver.Connector, params ...DBOption) error {
+	if c := getConnInitConnector(aliasName, connector); c != nil {
+		connector = c
+	}
+	db := sql.OpenDB(connector)
+	_, err := addAliasWthDB(aliasName, driverName, db, params...)
+	if err != nil {
+		db.Close()
+		DebugLog.Println(err.Error())
+	}
+	return err
+}
+
 // RegisterDriver Register a database driver use specify driver name, this can be definition the driver is which database type.
 func RegisterDriver(driverName string, typ DriverType) error {
 	if t, ok := drivers[driverName]; !ok {
@@ -497,6 +680,61 @@ func RegisterDriver(driverName string, typ DriverType) error {
 	return nil
 }
 
+// drivers which always quote identifiers
+var identifierQuoting sync.Map
+
+// SetIdentifierQuoting Change whether identifiers are always quoted for the driver.
+// by default the conflict column of InsertOrUpdate is lower cased and not quoted,
+// set always to true if your tables or columns are created with mixed case.
+func SetIdentifierQuoting(driver DriverType, always bool) {
+	identifierQuoting.Store(driver, always)
+}
+
+// check whether identifiers should always be quoted for the driver.
+func isQuoteAlways(driver DriverType) bool {
+	always, ok := identifierQuoting.Load(driver)
+	return ok && always.(bool)
+}
+
+// default max number of placeholders in one statement for each driver
+var defaultMaxPlaceholders = map[DriverType]int{
+	DRMySQL:    65535,
+	DRSqlite:   999,
+	DROracle:   65535,
+	DRPostgres: 65535,
+	DRTiDB:     65535,
+}
+
+// drivers which max number of placeholders is changed
+var maxPlaceholders sync.Map
+
+// SetMaxPlaceholders Change the max number of placeholders in one statement for the driver.
+// InsertMulti splits the rows into chunks so that no statement exceeds the limit,
+// whatever the bulk is. use 0 to disable the limit.
+func SetMaxPlaceholders(driver DriverType, limit int) {
+	maxPlaceholders.Store(driver, limit)
+}
+
+// get the max number of placeholders in one statement for the driver.
+func getMaxPlaceholders(driver DriverType) int {
+	if limit, ok := maxPlaceholders.Load(driver); ok {
+		return limit.(int)
+	}
+	return defaultMaxPlaceholders[driver]
+}
+
+// get a bulk size which keeps the placeholders of one statement under the driver limit.
+func safeBulk(driver DriverType, bulk int, columns int) int {
+	limit := getMaxPlaceholders(driver)
+	if limit <= 0 || columns <= 0 {
+		return bulk
+	}
+	if max := limit / columns; bulk > max {
+		bulk = max
+	}
+	return bulk
+}
+
 // SetDataBaseTZ Change the database default used timezone
 func SetDataBaseTZ(aliasName string, tz *time.Location) error {
 	if al, ok := dataBaseCache.get(aliasName); ok {
@@ -591,6 +829,13 @@ func ConnMaxLifetime(v time.Duration) DBOption {
 	}
 }
 
+// ConnMaxIdleTime return a hint about ConnMaxIdleTime
+func ConnMaxIdleTime(v time.Duration) DBOption {
+	return func(al *alias) {
+		al.SetConnMaxIdleTime(v)
+	}
+}
+
 // MaxStmtCacheSize return a hint about MaxStmtCacheSize
 func MaxStmtCacheSize(v int) DBOption {
 	return func(al *alias) {
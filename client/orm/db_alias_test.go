@@ -15,6 +15,13 @@
 package orm
 
 import (
+	"context"
+	"database/sql"
+	sqldriver "database/sql/driver"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"syscall"
 	"testing"
 	"time"
 
@@ -75,6 +82,242 @@ func TestRegisterDataBaseMaxStmtCacheSize841(t *testing.T) {
 	assert.Equal(t, al.DB.stmtDecoratorsLimit, 841)
 }
 
+func TestSetConnPoolConfig(t *testing.T) {
+	err := RegisterDataBase("test-pool", DBARGS.Driver, DBARGS.Source,
+		MaxIdleConnections(20),
+		ConnMaxIdleTime(time.Second))
+	assert.Nil(t, err)
+
+	err = SetConnPoolConfig("test-pool", ConnPoolConfig{
+		MaxOpenConns:    30,
+		ConnMaxLifetime: time.Minute,
+		ConnMaxIdleTime: -1,
+	})
+	assert.Nil(t, err)
+
+	al := getDbAlias("test-pool")
+	assert.Equal(t, 20, al.MaxIdleConns)
+	assert.Equal(t, 30, al.MaxOpenConns)
+	assert.Equal(t, 30, al.DB.DB.Stats().MaxOpenConnections)
+	assert.Equal(t, time.Minute, al.ConnMaxLifetime)
+	assert.Equal(t, time.Duration(-1), al.ConnMaxIdleTime)
+
+	err = SetConnPoolConfig("test-pool-unknown", ConnPoolConfig{MaxOpenConns: 1})
+	assert.NotNil(t, err)
+}
+
+func TestRegisterConnInitializer(t *testing.T) {
+	if DBARGS.Driver != "sqlite3" {
+		return
+	}
+	var inits int32
+	aliasName := "test-conn-init"
+	assert.NotNil(t, RegisterConnInitializer(aliasName, nil))
+	err := RegisterConnInitializer(aliasName, func(ctx context.Context, conn *sql.Conn) error {
+		atomic.AddInt32(&inits, 1)
+		_, err := conn.ExecContext(ctx, "PRAGMA cache_size = 1234")
+		return err
+	})
+	assert.Nil(t, err)
+	err = RegisterDataBase(aliasName, DBARGS.Driver, DBARGS.Source, MaxOpenConnections(2))
+	assert.Nil(t, err)
+	assert.NotNil(t, RegisterConnInitializer(aliasName, nil))
+
+	db, err := GetDB(aliasName)
+	assert.Nil(t, err)
+	// hold the connections to make the pool open the new ones
+	conns := make([]*sql.Conn, 0, 2)
+	for i := 0; i < 2; i++ {
+		conn, err := db.Conn(context.Background())
+		assert.Nil(t, err)
+		var size int
+		assert.Nil(t, conn.QueryRowContext(context.Background(), "PRAGMA cache_size").Scan(&size))
+		assert.Equal(t, 1234, size)
+		conns = append(conns, conn)
+	}
+	for _, conn := range conns {
+		conn.Close()
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&inits))
+
+	// the connection is dropped if the initializer fails
+	aliasName = "test-conn-init-error"
+	err = RegisterConnInitializer(aliasName, func(ctx context.Context, conn *sql.Conn) error {
+		return errors.New("init error")
+	})
+	assert.Nil(t, err)
+	err = RegisterDataBase(aliasName, DBARGS.Driver, DBARGS.Source)
+	assert.NotNil(t, err)
+}
+
+func TestRegisterDriverWithPlaceholder(t *testing.T) {
+	assert.Equal(t, "SELECT $1, $2", replacePlaceholders("SELECT ?, ?", "$"))
+	assert.Equal(t, "SELECT 1", replacePlaceholders("SELECT 1", "$"))
+
+	err := RegisterDriverWithPlaceholder("test-mssql", DRMySQL, PlaceholderAtP)
+	assert.Nil(t, err)
+	assert.Equal(t, DRMySQL, drivers["test-mssql"])
+	err = RegisterDriverWithPlaceholder("test-mssql", DRPostgres, PlaceholderAtP)
+	assert.NotNil(t, err)
+	err = RegisterDriverWithPlaceholder("test-unknown-style", DRMySQL, PlaceholderStyle(100))
+	assert.NotNil(t, err)
+
+	d := newdbBasePlaceholder(DRMySQL, PlaceholderAtP)
+	query := "SELECT ? FROM t WHERE a = ?"
+	d.ReplaceMarks(&query)
+	assert.Equal(t, "SELECT @p1 FROM t WHERE a = @p2", query)
+
+	// the $n placeholders of postgres are not used
+	d = newdbBasePlaceholder(DRPostgres, PlaceholderQuestion)
+	query = "SELECT ?"
+	d.ReplaceMarks(&query)
+	assert.Equal(t, "SELECT ?", query)
+}
+
+type testConnector struct {
+	dsn     string
+	driver  sqldriver.Driver
+	connect int
+}
+
+func (c *testConnector) Connect(context.Context) (sqldriver.Conn, error) {
+	c.connect++
+	return c.driver.Open(c.dsn)
+}
+
+func (c *testConnector) Driver() sqldriver.Driver {
+	return c.driver
+}
+
+func TestRegisterDataBaseWithConnector(t *testing.T) {
+	db, err := sql.Open(DBARGS.Driver, DBARGS.Source)
+	assert.Nil(t, err)
+	defer db.Close()
+
+	connector := &testConnector{dsn: DBARGS.Source, driver: db.Driver()}
+	err = RegisterDataBaseWithConnector("test-connector", DBARGS.Driver, connector, MaxIdleConnections(2))
+	assert.Nil(t, err)
+	assert.True(t, connector.connect > 0)
+
+	al := getDbAlias("test-connector")
+	assert.NotNil(t, al)
+	assert.Equal(t, 2, al.MaxIdleConns)
+	assert.Nil(t, al.DB.DB.Ping())
+
+	err = RegisterDataBaseWithConnector("test-connector", DBARGS.Driver, connector)
+	assert.NotNil(t, err)
+	err = RegisterDataBaseWithConnector("test-connector-unknown", "unknown", connector)
+	assert.NotNil(t, err)
+}
+
+// the connection fails the next queries with a connection lost error
+type flakyConn struct {
+	sqldriver.Conn
+	fails *int
+}
+
+func (c *flakyConn) lost() error {
+	if *c.fails > 0 {
+		*c.fails--
+		return fmt.Errorf("read: %w", syscall.ECONNRESET)
+	}
+	return nil
+}
+
+func (c *flakyConn) QueryContext(ctx context.Context, query string, args []sqldriver.NamedValue) (sqldriver.Rows, error) {
+	if err := c.lost(); err != nil {
+		return nil, err
+	}
+	return c.Conn.(sqldriver.QueryerContext).QueryContext(ctx, query, args)
+}
+
+func (c *flakyConn) ExecContext(ctx context.Context, query string, args []sqldriver.NamedValue) (sqldriver.Result, error) {
+	if err := c.lost(); err != nil {
+		return nil, err
+	}
+	return c.Conn.(sqldriver.ExecerContext).ExecContext(ctx, query, args)
+}
+
+type flakyConnector struct {
+	testConnector
+	fails int
+}
+
+func (c *flakyConnector) Connect(ctx context.Context) (sqldriver.Conn, error) {
+	conn, err := c.testConnector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &flakyConn{Conn: conn, fails: &c.fails}, nil
+}
+
+func TestSetReconnectOnError(t *testing.T) {
+	db, err := sql.Open(DBARGS.Driver, DBARGS.Source)
+	assert.Nil(t, err)
+	defer db.Close()
+
+	connector := &flakyConnector{testConnector: testConnector{dsn: DBARGS.Source, driver: db.Driver()}}
+	err = RegisterDataBaseWithConnector("test-reconnect", DBARGS.Driver, connector, MaxIdleConnections(1))
+	assert.Nil(t, err)
+	al := getDbAlias("test-reconnect")
+
+	var n int
+	connector.fails = 1
+	assert.NotNil(t, al.DB.QueryRow("SELECT 1").Scan(&n))
+
+	assert.Nil(t, SetReconnectOnError("test-reconnect", true))
+	connector.fails = 1
+	assert.Nil(t, al.DB.QueryRow("SELECT 1").Scan(&n))
+	assert.Equal(t, 1, n)
+	connector.fails = 1
+	rows, err := al.DB.Query("SELECT 1")
+	assert.Nil(t, err)
+	assert.Nil(t, rows.Close())
+
+	// the writes are not retried by default
+	connector.fails = 1
+	_, err = al.DB.Exec("CREATE TABLE reconnect_test (id integer)")
+	assert.NotNil(t, err)
+
+	assert.Nil(t, SetReconnectWritesOnError("test-reconnect", true))
+	connector.fails = 1
+	_, err = al.DB.Exec("CREATE TABLE reconnect_test (id integer)")
+	assert.Nil(t, err)
+	_, err = al.DB.Exec("DROP TABLE reconnect_test")
+	assert.Nil(t, err)
+
+	// only retry once
+	connector.fails = 2
+	assert.NotNil(t, al.DB.QueryRow("SELECT 1").Scan(&n))
+
+	assert.NotNil(t, SetReconnectOnError("test-reconnect-unknown", true))
+	assert.NotNil(t, SetReconnectWritesOnError("test-reconnect-unknown", true))
+}
+
+func TestIsConnError(t *testing.T) {
+	lost := fmt.Errorf("read: %w", syscall.ECONNRESET)
+	assert.True(t, new(dbBase).IsConnError(sqldriver.ErrBadConn))
+	assert.True(t, new(dbBase).IsConnError(lost))
+	assert.False(t, new(dbBase).IsConnError(sql.ErrNoRows))
+
+	assert.True(t, newdbBaseMysql().IsConnError(fmt.Errorf("invalid connection")))
+	assert.True(t, newdbBaseTidb().IsConnError(fmt.Errorf("MySQL server has gone away")))
+	assert.False(t, newdbBaseMysql().IsConnError(fmt.Errorf("Error 1062: Duplicate entry")))
+
+	assert.True(t, newdbBasePostgres().IsConnError(&testPgError{Code: "08006"}))
+	assert.True(t, newdbBasePostgres().IsConnError(&testPgError{Code: "57P01"}))
+	assert.False(t, newdbBasePostgres().IsConnError(&testPgError{Code: "23505"}))
+}
+
+// the error of lib/pq
+type testPgError struct {
+	Code string
+}
+
+func (e *testPgError) Error() string {
+	return "pq: " + e.Code
+}
+
 func TestDBCache(t *testing.T) {
 	dataBaseCache.add("test1", &alias{})
 	dataBaseCache.add("default", &alias{})
@@ -84,3 +327,56 @@ func TestDBCache(t *testing.T) {
 	assert.NotNil(t, al)
 	assert.True(t, ok)
 }
+
+func TestRegisterSQLRewriter(t *testing.T) {
+	defer sqlRewriters.Store([]SQLRewriter(nil))
+	ops := make([]string, 0, 2)
+	RegisterSQLRewriter(func(op, query string) string {
+		if query != "SELECT 1" {
+			return query
+		}
+		ops = append(ops, op)
+		return "SELECT 2"
+	})
+
+	al := getDbAlias("default")
+
+	var n int
+	err := al.DB.QueryRow("SELECT 1").Scan(&n)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+
+	stmt, err := al.DB.Prepare("SELECT 1")
+	assert.Nil(t, err)
+	defer stmt.Close()
+	err = stmt.QueryRow().Scan(&n)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+
+	assert.Equal(t, []string{"QueryRow", "Prepare"}, ops)
+}
+
+func TestSetIdentifierQuoting(t *testing.T) {
+	assert.False(t, isQuoteAlways(DRPostgres))
+
+	SetIdentifierQuoting(DRPostgres, true)
+	assert.True(t, isQuoteAlways(DRPostgres))
+	assert.False(t, isQuoteAlways(DRMySQL))
+
+	SetIdentifierQuoting(DRPostgres, false)
+	assert.False(t, isQuoteAlways(DRPostgres))
+}
+
+func TestSafeBulk(t *testing.T) {
+	assert.Equal(t, 999, getMaxPlaceholders(DRSqlite))
+	assert.Equal(t, 99, safeBulk(DRSqlite, 1000, 10))
+	assert.Equal(t, 50, safeBulk(DRSqlite, 50, 10))
+	assert.Equal(t, 1000, safeBulk(DRMySQL, 1000, 10))
+
+	SetMaxPlaceholders(DRMySQL, 100)
+	defer SetMaxPlaceholders(DRMySQL, 65535)
+	assert.Equal(t, 10, safeBulk(DRMySQL, 1000, 10))
+
+	SetMaxPlaceholders(DRMySQL, 0)
+	assert.Equal(t, 1000, safeBulk(DRMySQL, 1000, 10))
+}
@@ -0,0 +1,46 @@
+// Copyright 2020 beego
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// ResetAutoIncrement restart the auto increment pk of the model table in the database alias from 1,
+// e.g. between the test cases. delete the rows of the table first, mysql and sqlite continue
+// from the max id plus one if the table isn't empty, postgres doesn't check the existing ids.
+// mysql, tidb, sqlite and postgres are supported, the other drivers return ErrNotImplement.
+func ResetAutoIncrement(aliasName string, md interface{}) error {
+	al, ok := dataBaseCache.get(aliasName)
+	if !ok {
+		return fmt.Errorf("unknown DataBase alias name %s", aliasName)
+	}
+	mi, err := getTypeMiE(reflect.Indirect(reflect.ValueOf(md)).Type())
+	if err != nil {
+		return err
+	}
+	if mi.fields.pk == nil || !mi.fields.pk.auto {
+		return fmt.Errorf("<orm.ResetAutoIncrement> table `%s` has no auto increment pk", mi.table)
+	}
+
+	query, err := al.DbBaser.GenerateResetAutoIncrementSQL(mi.table, mi.fields.pk.column)
+	if err != nil {
+		return err
+	}
+	_, err = al.DB.ExecContext(context.Background(), query)
+	return err
+}
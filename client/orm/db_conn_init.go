@@ -0,0 +1,188 @@
+// Copyright 2020 beego
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orm
+
+import (
+	"context"
+	"database/sql"
+	sqldriver "database/sql/driver"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ConnInitializer is run on each new connection of the database before it's used,
+// e.g. to set the session settings like search_path.
+type ConnInitializer func(ctx context.Context, conn *sql.Conn) error
+
+// the connection initializers of the database aliases
+var connInitializers sync.Map
+
+// RegisterConnInitializer set the initializer which runs once on each new physical connection
+// of the database alias, the connection is dropped if it returns error.
+// it must be called before the alias is registered by RegisterDataBase or RegisterDataBaseWithConnector,
+// the *sql.DB added by AddAliasWthDB isn't initialized.
+//
+//	orm.RegisterConnInitializer("default", func(ctx context.Context, conn *sql.Conn) error {
+//		_, err := conn.ExecContext(ctx, "SET search_path TO tenant")
+//		return err
+//	})
+func RegisterConnInitializer(aliasName string, init ConnInitializer) error {
+	if init == nil {
+		return errors.New("<orm.RegisterConnInitializer> initializer cannot be nil")
+	}
+	if _, ok := dataBaseCache.get(aliasName); ok {
+		return fmt.Errorf("DataBase alias name `%s` already registered, register the initializer before it", aliasName)
+	}
+	connInitializers.Store(aliasName, init)
+	return nil
+}
+
+// get the connector which initializes the new connections of the alias, nil if there is no initializer
+func getConnInitConnector(aliasName string, connector sqldriver.Connector) sqldriver.Connector {
+	init, ok := connInitializers.Load(aliasName)
+	if !ok {
+		return nil
+	}
+	return &connInitConnector{Connector: connector, init: init.(ConnInitializer)}
+}
+
+// connector which runs the initializer on the new connections
+type connInitConnector struct {
+	sqldriver.Connector
+	init ConnInitializer
+}
+
+func (c *connInitConnector) Connect(ctx context.Context) (sqldriver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.initConn(ctx, conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// run the initializer on the driver connection by the *sql.Conn of a throwaway *sql.DB,
+// which doesn't close the connection.
+func (c *connInitConnector) initConn(ctx context.Context, conn sqldriver.Conn) error {
+	db := sql.OpenDB(&singleConnConnector{conn: &unclosableConn{Conn: conn}, driver: c.Driver()})
+	defer db.Close()
+	sc, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer sc.Close()
+	return c.init(ctx, sc)
+}
+
+// connector of the registered driver and data source
+type dsnConnector struct {
+	driver sqldriver.Driver
+	dsn    string
+}
+
+func (c *dsnConnector) Connect(context.Context) (sqldriver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c *dsnConnector) Driver() sqldriver.Driver {
+	return c.driver
+}
+
+// get the connector of the driver name and data source
+func newDsnConnector(driverName, dataSource string) (sqldriver.Connector, error) {
+	db, err := sql.Open(driverName, dataSource)
+	if err != nil {
+		return nil, err
+	}
+	drv := db.Driver()
+	db.Close()
+	if dc, ok := drv.(sqldriver.DriverContext); ok {
+		return dc.OpenConnector(dataSource)
+	}
+	return &dsnConnector{driver: drv, dsn: dataSource}, nil
+}
+
+// connector which returns the connection only once
+type singleConnConnector struct {
+	mux    sync.Mutex
+	conn   sqldriver.Conn
+	driver sqldriver.Driver
+}
+
+func (c *singleConnConnector) Connect(context.Context) (sqldriver.Conn, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if c.conn == nil {
+		return nil, errors.New("<orm.RegisterConnInitializer> the connection is used")
+	}
+	conn := c.conn
+	c.conn = nil
+	return conn, nil
+}
+
+func (c *singleConnConnector) Driver() sqldriver.Driver {
+	return c.driver
+}
+
+// driver connection which isn't closed, the optional interfaces are passed to the connection
+type unclosableConn struct {
+	sqldriver.Conn
+}
+
+func (c *unclosableConn) Close() error {
+	return nil
+}
+
+func (c *unclosableConn) PrepareContext(ctx context.Context, query string) (sqldriver.Stmt, error) {
+	if p, ok := c.Conn.(sqldriver.ConnPrepareContext); ok {
+		return p.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+func (c *unclosableConn) ExecContext(ctx context.Context, query string, args []sqldriver.NamedValue) (sqldriver.Result, error) {
+	if e, ok := c.Conn.(sqldriver.ExecerContext); ok {
+		return e.ExecContext(ctx, query, args)
+	}
+	return nil, sqldriver.ErrSkip
+}
+
+func (c *unclosableConn) QueryContext(ctx context.Context, query string, args []sqldriver.NamedValue) (sqldriver.Rows, error) {
+	if q, ok := c.Conn.(sqldriver.QueryerContext); ok {
+		return q.QueryContext(ctx, query, args)
+	}
+	return nil, sqldriver.ErrSkip
+}
+
+func (c *unclosableConn) BeginTx(ctx context.Context, opts sqldriver.TxOptions) (sqldriver.Tx, error) {
+	if b, ok := c.Conn.(sqldriver.ConnBeginTx); ok {
+		return b.BeginTx(ctx, opts)
+	}
+	if opts.Isolation != 0 || opts.ReadOnly {
+		return nil, errors.New("<orm.RegisterConnInitializer> the driver doesn't support the transaction options")
+	}
+	return c.Conn.Begin()
+}
+
+func (c *unclosableConn) CheckNamedValue(nv *sqldriver.NamedValue) error {
+	if ch, ok := c.Conn.(sqldriver.NamedValueChecker); ok {
+		return ch.CheckNamedValue(nv)
+	}
+	return sqldriver.ErrSkip
+}
@@ -0,0 +1,43 @@
+// Copyright 2020 beego
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orm
+
+import (
+	"time"
+)
+
+// the callback invoked with the time spent waiting for a connection
+var connWaitFn func(dur time.Duration)
+
+// OnConnWait Set the callback invoked with the time each query spent waiting for
+// a connection of the pool, it helps to find out the pool is too small for the load.
+// the time is the delta of sql.DBStats.WaitDuration during the query, so the waits of
+// the concurrent queries may be counted in. the queries in transaction never wait.
+// use nil to remove it.
+func OnConnWait(fn func(dur time.Duration)) {
+	connWaitFn = fn
+}
+
+// start measuring the connection wait of a query, the returned func reports it
+func (d *DB) measureConnWait() func() {
+	fn := connWaitFn
+	if fn == nil || d.DB == nil {
+		return func() {}
+	}
+	start := d.DB.Stats().WaitDuration
+	return func() {
+		fn(d.DB.Stats().WaitDuration - start)
+	}
+}
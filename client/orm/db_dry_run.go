@@ -0,0 +1,110 @@
+// Copyright 2020 beego
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+)
+
+// ErrDryRun is returned by the queries which need the database to return rows in dry run mode
+var ErrDryRun = errors.New("<Ormer> dry run, query is not executed")
+
+// database querier which records the sql instead of executing it.
+type dryRunQuerier struct {
+	mux   sync.RWMutex
+	query string
+	args  []interface{}
+}
+
+var (
+	_ dbQuerier = new(dryRunQuerier)
+	_ txer      = new(dryRunQuerier)
+)
+
+func (d *dryRunQuerier) record(query string, args []interface{}) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	d.query = query
+	d.args = args
+}
+
+// get the last recorded sql and args
+func (d *dryRunQuerier) last() (string, []interface{}) {
+	d.mux.RLock()
+	defer d.mux.RUnlock()
+	return d.query, d.args
+}
+
+func (d *dryRunQuerier) Prepare(query string) (*sql.Stmt, error) {
+	return d.PrepareContext(context.Background(), query)
+}
+
+func (d *dryRunQuerier) PrepareContext(_ context.Context, query string) (*sql.Stmt, error) {
+	d.record(query, nil)
+	return nil, ErrDryRun
+}
+
+func (d *dryRunQuerier) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return d.ExecContext(context.Background(), query, args...)
+}
+
+// the result of the statement is always zero
+func (d *dryRunQuerier) ExecContext(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+	d.record(query, args)
+	return dryRunResult{}, nil
+}
+
+func (d *dryRunQuerier) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return d.QueryContext(context.Background(), query, args...)
+}
+
+func (d *dryRunQuerier) QueryContext(_ context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	d.record(query, args)
+	return nil, ErrDryRun
+}
+
+func (d *dryRunQuerier) QueryRow(query string, args ...interface{}) *sql.Row {
+	return d.QueryRowContext(context.Background(), query, args...)
+}
+
+// sql.Row can't be created with an error, so the row is queried with a canceled context,
+// which fails before a connection is taken, and scanning it returns context.Canceled.
+func (d *dryRunQuerier) QueryRowContext(_ context.Context, query string, args ...interface{}) *sql.Row {
+	d.record(query, args)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return new(sql.DB).QueryRowContext(ctx, query, args...)
+}
+
+func (d *dryRunQuerier) Begin() (*sql.Tx, error) {
+	return d.BeginTx(context.Background(), nil)
+}
+
+func (d *dryRunQuerier) BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error) {
+	return nil, ErrDryRun
+}
+
+type dryRunResult struct{}
+
+func (dryRunResult) LastInsertId() (int64, error) {
+	return 0, nil
+}
+
+func (dryRunResult) RowsAffected() (int64, error) {
+	return 0, nil
+}
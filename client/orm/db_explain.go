@@ -0,0 +1,89 @@
+// Copyright 2020 beego
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+)
+
+// returned by explainQuerier to stop reading rows of the real query
+var errExplained = errors.New("<QuerySeter> query explained")
+
+// get the EXPLAIN prefix of the driver, return false if the driver doesn't support it.
+func explainPrefix(driver DriverType, analyze bool) (string, bool) {
+	switch driver {
+	case DRMySQL, DRTiDB, DRPostgres:
+		if analyze {
+			return "EXPLAIN ANALYZE ", true
+		}
+		return "EXPLAIN ", true
+	case DRSqlite:
+		if analyze {
+			return "", false
+		}
+		return "EXPLAIN QUERY PLAN ", true
+	}
+	return "", false
+}
+
+// database querier which runs EXPLAIN of the query instead of the query itself,
+// the plan rows are saved as text, one line per row and columns separated by tab.
+type explainQuerier struct {
+	dbQuerier
+	prefix string
+	plan   string
+}
+
+func (d *explainQuerier) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return d.QueryContext(context.Background(), query, args...)
+}
+
+func (d *explainQuerier) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	rows, err := d.dbQuerier.QueryContext(ctx, d.prefix+query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]string, 0, 8)
+	for rows.Next() {
+		values := make([]sql.NullString, len(columns))
+		refs := make([]interface{}, len(columns))
+		for i := range values {
+			refs[i] = &values[i]
+		}
+		if err := rows.Scan(refs...); err != nil {
+			return nil, err
+		}
+		line := make([]string, len(values))
+		for i, v := range values {
+			line[i] = v.String
+		}
+		lines = append(lines, strings.Join(line, "\t"))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	d.plan = strings.Join(lines, "\n")
+	return nil, errExplained
+}
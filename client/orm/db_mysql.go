@@ -16,9 +16,12 @@ package orm
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"reflect"
 	"strings"
+
+	"github.com/beego/beego/v2/client/orm/clauses/order_clause"
 )
 
 // mysql operators.
@@ -114,6 +117,9 @@ func (d *dbBaseMysql) InsertOrUpdate(ctx context.Context, q dbQuerier, mi *model
 
 	// Get on the key-value pairs
 	for _, v := range args {
+		if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(v)), "WHERE ") {
+			return 0, fmt.Errorf("`%s` nonsupport the update condition of InsertOrUpdate", a.DriverName)
+		}
 		kv := strings.Split(v, "=")
 		if len(kv) == 2 {
 			argsMap[strings.ToLower(kv[0])] = kv[1]
@@ -123,7 +129,7 @@ func (d *dbBaseMysql) InsertOrUpdate(ctx context.Context, q dbQuerier, mi *model
 	isMulti := false
 	names := make([]string, 0, len(mi.fields.dbcols)-1)
 	Q := d.ins.TableQuote()
-	values, _, err := d.collectValues(mi, ind, mi.fields.dbcols, true, true, &names, a.TZ)
+	values, _, err := d.collectValues(mi, ind, d.insertColumns(mi, ind), true, true, &names, a.TZ)
 	if err != nil {
 		return 0, err
 	}
@@ -131,6 +137,7 @@ func (d *dbBaseMysql) InsertOrUpdate(ctx context.Context, q dbQuerier, mi *model
 	marks := make([]string, len(names))
 	updateValues := make([]interface{}, 0)
 	updates := make([]string, len(names))
+	fields := columnFields(mi, names)
 
 	for i, v := range names {
 		marks[i] = "?"
@@ -140,8 +147,10 @@ func (d *dbBaseMysql) InsertOrUpdate(ctx context.Context, q dbQuerier, mi *model
 		} else {
 			updates[i] = "`" + v + "`" + "=?"
 			updateValues = append(updateValues, values[i])
+			fields = append(fields, fields[i])
 		}
 	}
+	ctx = withArgFields(ctx, fields)
 
 	values = append(values, updateValues...)
 
@@ -178,10 +187,76 @@ func (d *dbBaseMysql) InsertOrUpdate(ctx context.Context, q dbQuerier, mi *model
 		return 0, err
 	}
 
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 	row := q.QueryRowContext(ctx, query, values...)
-	var id int64
-	err = row.Scan(&id)
-	return id, err
+	id, err := scanReturningID(row, mi)
+	return id, wrapQueryError(ctx, query, values, err)
+}
+
+// check whether err means the connection to mysql is lost.
+func (d *dbBaseMysql) IsConnError(err error) bool {
+	if d.dbBase.IsConnError(err) {
+		return true
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "invalid connection") ||
+		strings.Contains(msg, "server has gone away") ||
+		strings.Contains(msg, "Lost connection to MySQL server")
+}
+
+// the last insert id of mysql is the id of the first row of a multi-row insert,
+// the ids of a simple insert are consecutive if auto_increment_increment is 1.
+func (d *dbBaseMysql) insertedIDs(res sql.Result, rows int) []int64 {
+	first, err := res.LastInsertId()
+	if err != nil {
+		return nil
+	}
+	ids := make([]int64, rows)
+	for i := range ids {
+		ids[i] = first + int64(i)
+	}
+	return ids
+}
+
+// GenerateOrderNulls emulate NULLS FIRST/LAST with ISNULL(column),
+// which is 1 for NULL values and 0 for others.
+func (d *dbBaseMysql) GenerateOrderNulls(column string, sort string, nulls order_clause.Nulls) string {
+	return generateISNULLOrder(column, sort, nulls)
+}
+
+// GenerateRandomOrder use RAND()
+func (d *dbBaseMysql) GenerateRandomOrder() string {
+	return "RAND()"
+}
+
+// GenerateFullTextSQL use MATCH AGAINST in boolean mode, the columns need a FULLTEXT index.
+func (d *dbBaseMysql) GenerateFullTextSQL(columns []string) (string, error) {
+	return fmt.Sprintf("MATCH(%s) AGAINST(? IN BOOLEAN MODE)", strings.Join(columns, ", ")), nil
+}
+
+// GenerateGroupConcatSQL use GROUP_CONCAT with the SEPARATOR which must be a string literal.
+func (d *dbBaseMysql) GenerateGroupConcatSQL(column string, sep string) (string, error) {
+	sep = quoteStringLiteral(strings.ReplaceAll(sep, `\`, `\\`))
+	return fmt.Sprintf("GROUP_CONCAT(%s SEPARATOR %s)", column, sep), nil
+}
+
+// GenerateResetAutoIncrementSQL set the AUTO_INCREMENT of the table,
+// mysql raises it to the max value of the column plus one if the table isn't empty.
+func (d *dbBaseMysql) GenerateResetAutoIncrementSQL(table string, column string) (string, error) {
+	Q := d.TableQuote()
+	return fmt.Sprintf("ALTER TABLE %s%s%s AUTO_INCREMENT = 1", Q, table, Q), nil
+}
+
+func generateISNULLOrder(column string, sort string, nulls order_clause.Nulls) string {
+	order := strings.TrimSpace(column + " " + sort)
+	switch nulls {
+	case order_clause.NullsFirst:
+		return fmt.Sprintf("ISNULL(%s) DESC, %s", column, order)
+	case order_clause.NullsLast:
+		return fmt.Sprintf("ISNULL(%s) ASC, %s", column, order)
+	}
+	return order
 }
 
 // create new mysql dbBaser.
@@ -16,6 +16,7 @@ package orm
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"strings"
 
@@ -25,11 +26,14 @@ import (
 // oracle operators.
 var oracleOperators = map[string]string{
 	"exact":       "= ?",
+	"iexact":      "LIKE UPPER(?) ESCAPE '\\'",
+	"icontains":   "LIKE UPPER(?) ESCAPE '\\'",
 	"gt":          "> ?",
 	"gte":         ">= ?",
 	"lt":          "< ?",
 	"lte":         "<= ?",
-	"//iendswith": "LIKE ?",
+	"istartswith": "LIKE UPPER(?) ESCAPE '\\'",
+	"iendswith":   "LIKE UPPER(?) ESCAPE '\\'",
 }
 
 // oracle column field types.
@@ -68,11 +72,28 @@ func newdbBaseOracle() dbBaser {
 	return b
 }
 
+// SupportIsolationLevel oracle only supports read committed and serializable.
+func (d *dbBaseOracle) SupportIsolationLevel(level sql.IsolationLevel) bool {
+	switch level {
+	case sql.LevelDefault, sql.LevelReadCommitted, sql.LevelSerializable:
+		return true
+	}
+	return false
+}
+
 // OperatorSQL get oracle operator.
 func (d *dbBaseOracle) OperatorSQL(operator string) string {
 	return oracleOperators[operator]
 }
 
+// generate functioned sql for oracle, the case-insensitive operators compare the upper values.
+func (d *dbBaseOracle) GenerateOperatorLeftCol(fi *fieldInfo, operator string, leftCol *string) {
+	switch operator {
+	case "iexact", "icontains", "istartswith", "iendswith":
+		*leftCol = fmt.Sprintf("UPPER(%s)", *leftCol)
+	}
+}
+
 // DbTypes get oracle table field types.
 func (d *dbBaseOracle) DbTypes() map[string]string {
 	return oracleTypes
@@ -100,6 +121,11 @@ func (d *dbBaseOracle) IndexExists(ctx context.Context, db dbQuerier, table stri
 	return cnt > 0
 }
 
+// GenerateRandomOrder use DBMS_RANDOM.VALUE
+func (d *dbBaseOracle) GenerateRandomOrder() string {
+	return "DBMS_RANDOM.VALUE"
+}
+
 func (d *dbBaseOracle) GenerateSpecifyIndex(tableName string, useIndex int, indexes []string) string {
 	var s []string
 	Q := d.TableQuote()
@@ -147,6 +173,7 @@ func (d *dbBaseOracle) InsertValue(ctx context.Context, q dbQuerier, mi *modelIn
 
 	d.ins.ReplaceMarks(&query)
 
+	ctx = withArgFields(ctx, insertArgFields(mi, names, len(values)))
 	if isMulti || !d.ins.HasReturningID(mi, &query) {
 		res, err := q.ExecContext(ctx, query, values...)
 		if err == nil {
@@ -164,8 +191,9 @@ func (d *dbBaseOracle) InsertValue(ctx context.Context, q dbQuerier, mi *modelIn
 		}
 		return 0, err
 	}
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 	row := q.QueryRowContext(ctx, query, values...)
-	var id int64
-	err := row.Scan(&id)
-	return id, err
+	id, err := scanReturningID(row, mi)
+	return id, wrapQueryError(ctx, query, values, err)
 }
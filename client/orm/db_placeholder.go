@@ -0,0 +1,115 @@
+// Copyright 2020 beego
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orm
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// PlaceholderStyle is the style of the value placeholders in sql
+type PlaceholderStyle int
+
+// Enum the placeholder styles
+const (
+	PlaceholderQuestion PlaceholderStyle = iota // ?
+	PlaceholderDollar                           // $1, $2
+	PlaceholderAtP                              // @p1, @p2
+	PlaceholderColon                            // :1, :2
+)
+
+var placeholderPrefixes = map[PlaceholderStyle]string{
+	PlaceholderDollar: "$",
+	PlaceholderAtP:    "@p",
+	PlaceholderColon:  ":",
+}
+
+// the placeholder styles of the drivers registered by RegisterDriverWithPlaceholder
+var driverPlaceholders = map[string]PlaceholderStyle{}
+
+// RegisterDriverWithPlaceholder Register a database driver like RegisterDriver,
+// and the `?` placeholders in the sql are replaced with the style of the driver,
+// e.g. @p1, @p2 for sql server drivers.
+func RegisterDriverWithPlaceholder(driverName string, typ DriverType, style PlaceholderStyle) error {
+	if style != PlaceholderQuestion && placeholderPrefixes[style] == "" {
+		return fmt.Errorf("unknown placeholder style `%d`", style)
+	}
+	if err := RegisterDriver(driverName, typ); err != nil {
+		return err
+	}
+	driverPlaceholders[driverName] = style
+	return nil
+}
+
+// replace `?` in query with the numbered placeholders, e.g. $1, $2
+func replacePlaceholders(query string, prefix string) string {
+	num := 0
+	for i := 0; i < len(query); i++ {
+		if query[i] == '?' {
+			num++
+		}
+	}
+	if num == 0 {
+		return query
+	}
+	data := make([]byte, 0, len(query)+num*(len(prefix)+1))
+	num = 1
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		if c == '?' {
+			data = append(data, prefix...)
+			data = append(data, strconv.Itoa(num)...)
+			num++
+		} else {
+			data = append(data, c)
+		}
+	}
+	return string(data)
+}
+
+// dbBaser of the driver type with the placeholder style of the driver
+type dbBasePlaceholder struct {
+	dbBaser
+	style PlaceholderStyle
+}
+
+func (d *dbBasePlaceholder) ReplaceMarks(query *string) {
+	if prefix := placeholderPrefixes[d.style]; prefix != "" {
+		*query = replacePlaceholders(*query, prefix)
+	}
+}
+
+// create a dbBaser of typ which replaces the placeholders with style
+func newdbBasePlaceholder(typ DriverType, style PlaceholderStyle) dbBaser {
+	var base dbBaser
+	switch typ {
+	case DRMySQL:
+		base = newdbBaseMysql()
+	case DRSqlite:
+		base = newdbBaseSqlite()
+	case DROracle:
+		base = newdbBaseOracle()
+	case DRPostgres:
+		base = newdbBasePostgres()
+	case DRTiDB:
+		base = newdbBaseTidb()
+	default:
+		return dbBasers[typ]
+	}
+	d := &dbBasePlaceholder{dbBaser: base, style: style}
+	// the sql built by the base uses the placeholders of d
+	base.setIns(d)
+	return d
+}
@@ -16,8 +16,11 @@ package orm
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strconv"
+	"reflect"
+	"strings"
+	"time"
 )
 
 // postgresql operators.
@@ -75,8 +78,19 @@ func (d *dbBasePostgres) OperatorSQL(operator string) string {
 	return postgresOperators[operator]
 }
 
+// array field contains the given elements with operator @>.
+func (d *dbBasePostgres) GenerateOperatorSQL(mi *modelInfo, fi *fieldInfo, operator string, args []interface{}, tz *time.Location) (string, []interface{}) {
+	if fi.array && operator == "contains" {
+		return "@> ?", []interface{}{getArrayArgsValue(args)}
+	}
+	return d.dbBase.GenerateOperatorSQL(mi, fi, operator, args, tz)
+}
+
 // generate functioned sql string, such as contains(text).
 func (d *dbBasePostgres) GenerateOperatorLeftCol(fi *fieldInfo, operator string, leftCol *string) {
+	if fi.array {
+		return
+	}
 	switch operator {
 	case "contains", "startswith", "endswith":
 		*leftCol = fmt.Sprintf("%s::text", *leftCol)
@@ -102,29 +116,7 @@ func (d *dbBasePostgres) TableQuote() string {
 // postgresql value placeholder is $n.
 // replace default ? to $n.
 func (d *dbBasePostgres) ReplaceMarks(query *string) {
-	q := *query
-	num := 0
-	for _, c := range q {
-		if c == '?' {
-			num++
-		}
-	}
-	if num == 0 {
-		return
-	}
-	data := make([]byte, 0, len(q)+num)
-	num = 1
-	for i := 0; i < len(q); i++ {
-		c := q[i]
-		if c == '?' {
-			data = append(data, '$')
-			data = append(data, []byte(strconv.Itoa(num))...)
-			num++
-		} else {
-			data = append(data, c)
-		}
-	}
-	*query = string(data)
+	*query = replacePlaceholders(*query, "$")
 }
 
 // make returning sql support for postgresql.
@@ -147,9 +139,14 @@ func (d *dbBasePostgres) setval(ctx context.Context, db dbQuerier, mi *modelInfo
 	}
 
 	Q := d.ins.TableQuote()
+	table := mi.table
+	if isQuoteAlways(DRPostgres) {
+		// the table name of pg_get_serial_sequence is lower cased unless it's quoted
+		table = Q + table + Q
+	}
 	for _, name := range autoFields {
 		query := fmt.Sprintf("SELECT setval(pg_get_serial_sequence('%s', '%s'), (SELECT MAX(%s%s%s) FROM %s%s%s));",
-			mi.table, name,
+			table, name,
 			Q, name, Q,
 			Q, mi.table, Q)
 		if _, err := db.ExecContext(ctx, query); err != nil {
@@ -183,6 +180,48 @@ func (d *dbBasePostgres) IndexExists(ctx context.Context, db dbQuerier, table st
 	return cnt > 0
 }
 
+// GenerateFullTextSQL match the text search vector of the columns with the plain query.
+func (d *dbBasePostgres) GenerateFullTextSQL(columns []string) (string, error) {
+	return fmt.Sprintf("to_tsvector(concat_ws(' ', %s)) @@ plainto_tsquery(?)", strings.Join(columns, ", ")), nil
+}
+
+// GenerateGroupConcatSQL use string_agg, the values are cast to text as it only accepts text.
+func (d *dbBasePostgres) GenerateGroupConcatSQL(column string, sep string) (string, error) {
+	return fmt.Sprintf("string_agg(CAST(%s AS TEXT), %s)", column, quoteStringLiteral(sep)), nil
+}
+
+// GenerateResetAutoIncrementSQL restart the serial sequence of the column,
+// setval with is_called false is the same as ALTER SEQUENCE RESTART without knowing the sequence name.
+func (d *dbBasePostgres) GenerateResetAutoIncrementSQL(table string, column string) (string, error) {
+	if isQuoteAlways(DRPostgres) {
+		Q := d.TableQuote()
+		table = Q + table + Q
+	}
+	return fmt.Sprintf("SELECT setval(pg_get_serial_sequence(%s, %s), 1, false)",
+		quoteStringLiteral(table), quoteStringLiteral(column)), nil
+}
+
+// check the connection exception class 08 and the shutdown errors of postgres.
+func (d *dbBasePostgres) IsConnError(err error) bool {
+	if d.dbBase.IsConnError(err) {
+		return true
+	}
+	code := ""
+	var stateErr interface{ SQLState() string }
+	if errors.As(err, &stateErr) {
+		code = stateErr.SQLState()
+	} else if v := reflect.Indirect(reflect.ValueOf(err)); v.Kind() == reflect.Struct {
+		// lib/pq reports the code in the field Code
+		if f := v.FieldByName("Code"); f.IsValid() && f.Kind() == reflect.String {
+			code = f.String()
+		}
+	}
+	if strings.HasPrefix(code, "08") || code == "57P01" || code == "57P02" || code == "57P03" {
+		return true
+	}
+	return strings.Contains(err.Error(), "conn closed")
+}
+
 // GenerateSpecifyIndex return a specifying index clause
 func (d *dbBasePostgres) GenerateSpecifyIndex(tableName string, useIndex int, indexes []string) string {
 	DebugLog.Println("[WARN] Not support any specifying index action, so that action is ignored")
@@ -0,0 +1,61 @@
+// Copyright 2020 beego
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// QueryError is returned when the database fails the query, with the sql and args of it.
+// the args of the redacted columns are replaced like the query log, see RegisterLogRedactor.
+// use errors.As to get it, and errors.Is or errors.As on it to check the error of the driver.
+type QueryError struct {
+	SQL  string
+	Args []interface{}
+	Err  error
+}
+
+func (e *QueryError) Error() string {
+	msg := fmt.Sprintf("%s - [%s]", e.Err.Error(), e.SQL)
+	if len(e.Args) > 0 {
+		args := make([]string, 0, len(e.Args))
+		for _, arg := range e.Args {
+			args = append(args, fmt.Sprintf("%v", arg))
+		}
+		msg += fmt.Sprintf(" - `%s`", strings.Join(args, "`, `"))
+	}
+	return msg
+}
+
+func (e *QueryError) Unwrap() error {
+	return e.Err
+}
+
+// wrap err of query into QueryError, sql.ErrNoRows and the errors of context
+// are not wrapped as the query doesn't fail in the database.
+func wrapQueryError(ctx context.Context, query string, args []interface{}, err error) error {
+	if err == nil || err == sql.ErrNoRows || err == context.Canceled || err == context.DeadlineExceeded {
+		return err
+	}
+	var qe *QueryError
+	if errors.As(err, &qe) {
+		return err
+	}
+	return &QueryError{SQL: query, Args: redactLogArgs(ctx, args), Err: err}
+}
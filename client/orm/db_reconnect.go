@@ -0,0 +1,75 @@
+// Copyright 2020 beego
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// SetReconnectOnError Change whether the query is retried once on a fresh connection
+// when it fails because the connection is lost, e.g. mysql closed the idle connection.
+// only the reads are retried, use SetReconnectWritesOnError to retry the writes too.
+// the queries in transaction are never retried.
+func SetReconnectOnError(aliasName string, reconnect bool) error {
+	al, ok := dataBaseCache.get(aliasName)
+	if !ok {
+		return fmt.Errorf("unknown DataBase alias name %s", aliasName)
+	}
+	al.DB.reconnect = reconnect
+	return nil
+}
+
+// SetReconnectWritesOnError Change whether the writes are retried like the reads
+// after SetReconnectOnError. the write may be applied twice if the connection is lost
+// after the database received it, only allow it if the writes are idempotent.
+func SetReconnectWritesOnError(aliasName string, allow bool) error {
+	al, ok := dataBaseCache.get(aliasName)
+	if !ok {
+		return fmt.Errorf("unknown DataBase alias name %s", aliasName)
+	}
+	al.DB.reconnectWrites = allow
+	return nil
+}
+
+// the statements which only read
+var readStatements = map[string]bool{
+	"SELECT": true, "SHOW": true, "EXPLAIN": true, "DESCRIBE": true, "DESC": true, "PRAGMA": true,
+}
+
+func isReadQuery(query string) bool {
+	query = strings.TrimLeft(query, " \t\r\n(")
+	end := strings.IndexAny(query, " \t\r\n(")
+	if end < 0 {
+		end = len(query)
+	}
+	return readStatements[strings.ToUpper(query[:end])]
+}
+
+// check whether the query failed with err should be retried on a fresh connection
+func (d *DB) shouldReconnect(ctx context.Context, query string, err error) bool {
+	if err == nil || !d.reconnect || d.isConnError == nil || ctx.Err() != nil {
+		return false
+	}
+	if !d.reconnectWrites && !isReadQuery(query) {
+		return false
+	}
+	if !d.isConnError(err) {
+		return false
+	}
+	DebugLog.Printf("connection lost, retry on a fresh connection: %s, %s\n", err.Error(), query)
+	return true
+}
@@ -0,0 +1,224 @@
+// Copyright 2020 beego
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrFullTableScan is returned when the full table scan guard rejects a query
+var ErrFullTableScan = errors.New("<Ormer> query rejected, full table scan")
+
+var pgSeqScanRows = regexp.MustCompile(`Seq Scan on .* rows=(\d+)`)
+
+// EnableFullScanGuard make the Ormers using the database alias check every SELECT with EXPLAIN,
+// and reject the query with ErrFullTableScan if it scans a whole table of more than maxRows rows.
+// sqlite doesn't estimate rows in its query plan, so every full table scan is rejected.
+// It's designed for tests, don't enable it in production.
+// Ormers created before this invocation will not be affected.
+// It's not safe for concurrent use, call it at init time before the Ormers of the alias are used.
+func EnableFullScanGuard(aliasName string, maxRows int64) {
+	al := getDbAlias(aliasName)
+	al.ScanGuard = true
+	al.ScanGuardRows = maxRows
+}
+
+// DisableFullScanGuard turn off the full table scan guard of the database alias.
+// Like EnableFullScanGuard, call it when no query is running on the alias.
+func DisableFullScanGuard(aliasName string) {
+	al := getDbAlias(aliasName)
+	al.ScanGuard = false
+}
+
+// database querier which explains SELECT before querying.
+type scanGuard struct {
+	alias *alias
+	db    dbQuerier
+}
+
+var (
+	_ dbQuerier = new(scanGuard)
+	_ txer      = new(scanGuard)
+	_ txEnder   = new(scanGuard)
+)
+
+// check the query plan of query, return ErrFullTableScan if it's rejected.
+func (d *scanGuard) check(ctx context.Context, query string, args ...interface{}) error {
+	if !strings.HasPrefix(strings.ToUpper(strings.TrimSpace(query)), "SELECT") {
+		return nil
+	}
+
+	prefix, ok := explainPrefix(d.alias.Driver, false)
+	if !ok {
+		return nil
+	}
+
+	rows, err := d.db.QueryContext(ctx, prefix+query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		values := make([]sql.NullString, len(columns))
+		refs := make([]interface{}, len(columns))
+		for i := range values {
+			refs[i] = &values[i]
+		}
+		if err := rows.Scan(refs...); err != nil {
+			return err
+		}
+		plan := make(map[string]string, len(columns))
+		for i, col := range columns {
+			plan[strings.ToLower(col)] = values[i].String
+		}
+		if table, ok := d.isFullScan(plan); ok {
+			return fmt.Errorf("%w: `%s` in `%s`", ErrFullTableScan, table, query)
+		}
+	}
+	return rows.Err()
+}
+
+// check one row of query plan
+func (d *scanGuard) isFullScan(plan map[string]string) (string, bool) {
+	switch d.alias.Driver {
+	case DRMySQL:
+		if plan["type"] != "ALL" {
+			return "", false
+		}
+		rows, _ := strconv.ParseFloat(plan["rows"], 64)
+		return plan["table"], rows > float64(d.alias.ScanGuardRows)
+	case DRTiDB:
+		if !strings.Contains(plan["id"], "TableFullScan") {
+			return "", false
+		}
+		rows, _ := strconv.ParseFloat(plan["estrows"], 64)
+		return plan["access object"], rows > float64(d.alias.ScanGuardRows)
+	case DRPostgres:
+		m := pgSeqScanRows.FindStringSubmatch(plan["query plan"])
+		if m == nil {
+			return "", false
+		}
+		rows, _ := strconv.ParseFloat(m[1], 64)
+		return strings.TrimSpace(m[0]), rows > float64(d.alias.ScanGuardRows)
+	case DRSqlite:
+		detail := plan["detail"]
+		if !strings.HasPrefix(detail, "SCAN ") || strings.Contains(detail, " USING ") ||
+			strings.Contains(detail, "CONSTANT ROW") || strings.Contains(detail, "SUBQUERY") || strings.Contains(detail, "(") {
+			return "", false
+		}
+		return strings.TrimPrefix(detail, "SCAN "), true
+	}
+	return "", false
+}
+
+func (d *scanGuard) Prepare(query string) (*sql.Stmt, error) {
+	return d.db.Prepare(query)
+}
+
+func (d *scanGuard) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return d.db.PrepareContext(ctx, query)
+}
+
+func (d *scanGuard) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return d.db.Exec(query, args...)
+}
+
+func (d *scanGuard) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return d.db.ExecContext(ctx, query, args...)
+}
+
+func (d *scanGuard) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return d.QueryContext(context.Background(), query, args...)
+}
+
+func (d *scanGuard) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if err := d.check(ctx, query, args...); err != nil {
+		return nil, err
+	}
+	return d.db.QueryContext(ctx, query, args...)
+}
+
+func (d *scanGuard) QueryRow(query string, args ...interface{}) *sql.Row {
+	return d.QueryRowContext(context.Background(), query, args...)
+}
+
+// sql.Row can't be created with an error, so a rejected query is queried with a context
+// which is done with the error, scanning the row returns it.
+func (d *scanGuard) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	if err := d.check(ctx, query, args...); err != nil {
+		return new(sql.DB).QueryRowContext(errContext{Context: ctx, err: err}, query, args...)
+	}
+	return d.db.QueryRowContext(ctx, query, args...)
+}
+
+// the done channel of errContext
+var closedDone = func() chan struct{} {
+	c := make(chan struct{})
+	close(c)
+	return c
+}()
+
+// context which is done with err, sql.DB fails with err before a connection is taken
+type errContext struct {
+	context.Context
+	err error
+}
+
+func (c errContext) Done() <-chan struct{} {
+	return closedDone
+}
+
+func (c errContext) Err() error {
+	return c.err
+}
+
+func (d *scanGuard) Begin() (*sql.Tx, error) {
+	return d.BeginTx(context.Background(), nil)
+}
+
+func (d *scanGuard) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return d.db.(txer).BeginTx(ctx, opts)
+}
+
+func (d *scanGuard) Commit() error {
+	return d.db.(txEnder).Commit()
+}
+
+func (d *scanGuard) Rollback() error {
+	return d.db.(txEnder).Rollback()
+}
+
+func (d *scanGuard) RollbackUnlessCommit() error {
+	return d.db.(txEnder).RollbackUnlessCommit()
+}
+
+func newScanGuard(alias *alias, db dbQuerier) dbQuerier {
+	d := new(scanGuard)
+	d.alias = alias
+	d.db = db
+	return d
+}
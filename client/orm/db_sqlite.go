@@ -22,6 +22,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/beego/beego/v2/client/orm/clauses/order_clause"
 	"github.com/beego/beego/v2/client/orm/hints"
 )
 
@@ -99,6 +100,29 @@ func (d *dbBaseSqlite) SupportUpdateJoin() bool {
 	return false
 }
 
+// transactions of sqlite are always serializable.
+func (d *dbBaseSqlite) SupportIsolationLevel(level sql.IsolationLevel) bool {
+	return level == sql.LevelDefault || level == sql.LevelSerializable
+}
+
+// GenerateOrderNulls ignore the position of NULL values,
+// NULL values are considered smaller than any other values in sqlite,
+// so they come first in ASC order and last in DESC order.
+func (d *dbBaseSqlite) GenerateOrderNulls(column string, sort string, nulls order_clause.Nulls) string {
+	return strings.TrimSpace(column + " " + sort)
+}
+
+// GenerateGroupConcatSQL use group_concat with the separator.
+func (d *dbBaseSqlite) GenerateGroupConcatSQL(column string, sep string) (string, error) {
+	return fmt.Sprintf("GROUP_CONCAT(%s, %s)", column, quoteStringLiteral(sep)), nil
+}
+
+// GenerateResetAutoIncrementSQL remove the sequence of the AUTOINCREMENT table,
+// the next id starts from the max id plus one, 1 if the table is empty.
+func (d *dbBaseSqlite) GenerateResetAutoIncrementSQL(table string, column string) (string, error) {
+	return fmt.Sprintf("DELETE FROM sqlite_sequence WHERE name = %s", quoteStringLiteral(table)), nil
+}
+
 // max int in sqlite.
 func (d *dbBaseSqlite) MaxLimit() uint64 {
 	return 9223372036854775807
@@ -159,23 +183,36 @@ func (d *dbBaseSqlite) IndexExists(ctx context.Context, db dbQuerier, table stri
 }
 
 // GenerateSpecifyIndex return a specifying index clause
+// sqlite only support one index in INDEXED BY, the others are ignored.
 func (d *dbBaseSqlite) GenerateSpecifyIndex(tableName string, useIndex int, indexes []string) string {
-	var s []string
 	Q := d.TableQuote()
-	for _, index := range indexes {
-		tmp := fmt.Sprintf(`%s%s%s`, Q, index, Q)
-		s = append(s, tmp)
-	}
 
 	switch useIndex {
 	case hints.KeyUseIndex, hints.KeyForceIndex:
-		return fmt.Sprintf(` INDEXED BY %s `, strings.Join(s, `,`))
+		if len(indexes) > 1 {
+			DebugLog.Println("[WARN] Only support one specifying index, so that the others are ignored")
+		}
+		return fmt.Sprintf(` INDEXED BY %s%s%s `, Q, indexes[0], Q)
 	default:
 		DebugLog.Println("[WARN] Not a valid specifying action, so that action is ignored")
 		return ``
 	}
 }
 
+// the last insert rowid of sqlite is the rowid of the last row,
+// the rows of one statement are inserted by the only writer, so the rowids are consecutive.
+func (d *dbBaseSqlite) insertedIDs(res sql.Result, rows int) []int64 {
+	last, err := res.LastInsertId()
+	if err != nil {
+		return nil
+	}
+	ids := make([]int64, rows)
+	for i := range ids {
+		ids[i] = last - int64(rows-1-i)
+	}
+	return ids
+}
+
 // create new sqlite dbBaser.
 func newdbBaseSqlite() dbBaser {
 	b := new(dbBaseSqlite)
@@ -43,6 +43,8 @@ type dbTables struct {
 	mi      *modelInfo
 	base    dbBaser
 	skipEnd bool
+	// the prefix of the table aliases, T0 is the table of mi
+	prefix string
 }
 
 // set table info to collection.
@@ -56,7 +58,7 @@ func (t *dbTables) set(names []string, mi *modelInfo, fi *fieldInfo, inner bool)
 		j.inner = inner
 	} else {
 		i := len(t.tables) + 1
-		jt := &dbTable{i, fmt.Sprintf("T%d", i), name, names, false, inner, mi, fi, nil}
+		jt := &dbTable{i, t.alias(i), name, names, false, inner, mi, fi, nil}
 		t.tablesM[name] = jt
 		t.tables = append(t.tables, jt)
 	}
@@ -68,7 +70,7 @@ func (t *dbTables) add(names []string, mi *modelInfo, fi *fieldInfo, inner bool)
 	name := strings.Join(names, ExprSep)
 	if _, ok := t.tablesM[name]; !ok {
 		i := len(t.tables) + 1
-		jt := &dbTable{i, fmt.Sprintf("T%d", i), name, names, false, inner, mi, fi, nil}
+		jt := &dbTable{i, t.alias(i), name, names, false, inner, mi, fi, nil}
 		t.tablesM[name] = jt
 		t.tables = append(t.tables, jt)
 		return jt, true
@@ -76,6 +78,11 @@ func (t *dbTables) add(names []string, mi *modelInfo, fi *fieldInfo, inner bool)
 	return t.tablesM[name], false
 }
 
+// get the alias of the i-th table, 0 is the table of mi.
+func (t *dbTables) alias(i int) string {
+	return fmt.Sprintf("%s%d", t.prefix, i)
+}
+
 // get table info in collection.
 func (t *dbTables) get(name string) (*dbTable, bool) {
 	j, ok := t.tablesM[name]
@@ -165,6 +172,54 @@ func (t *dbTables) parseRelated(rels []string, depth int) {
 	}
 }
 
+// get the columns of the model and the columns of the selected related tables by the name of table,
+// the columns of related tables are the paths like "user__profile__age".
+func (t *dbTables) getSelectColumns(exprs []string) (cols []string, rels map[string][]string, err error) {
+	for _, expr := range exprs {
+		exs := strings.Split(expr, ExprSep)
+		if len(exs) == 1 {
+			cols = append(cols, expr)
+			continue
+		}
+
+		mmi := t.mi
+		names := make([]string, 0, len(exs)-1)
+		for _, ex := range exs[:len(exs)-1] {
+			fi, ok := mmi.fields.GetByAny(ex)
+			if !ok || !fi.rel || fi.fieldType == RelManyToMany {
+				return nil, nil, fmt.Errorf("unknown relation field name `%s` of column `%s`", ex, expr)
+			}
+			names = append(names, fi.name)
+			mmi = fi.relModelInfo
+		}
+		fi, ok := mmi.fields.GetByAny(exs[len(exs)-1])
+		if !ok || !fi.dbcol {
+			return nil, nil, fmt.Errorf("wrong field/column name `%s`", expr)
+		}
+
+		name := strings.Join(names, ExprSep)
+		if tbl, ok := t.tablesM[name]; !ok || !tbl.sel {
+			return nil, nil, fmt.Errorf("the relation `%s` of column `%s` isn't selected by RelatedSel", name, expr)
+		}
+		if rels == nil {
+			rels = make(map[string][]string)
+		}
+		// the pk and the relation columns are needed to set the related models
+		if len(rels[name]) == 0 {
+			rels[name] = append(rels[name], mmi.fields.pk.column)
+			for _, rfi := range mmi.fields.fieldsDB {
+				if rfi.fieldType&IsRelField > 0 {
+					rels[name] = append(rels[name], rfi.column)
+				}
+			}
+		}
+		if !fi.pk && fi.fieldType&IsRelField == 0 {
+			rels[name] = append(rels[name], fi.column)
+		}
+	}
+	return cols, rels, nil
+}
+
 // generate join string.
 func (t *dbTables) getJoinSQL() (join string) {
 	Q := t.base.TableQuote()
@@ -180,7 +235,7 @@ func (t *dbTables) getJoinSQL() (join string) {
 			t1, t2 string
 			c1, c2 string
 		)
-		t1 = "T0"
+		t1 = t.alias(0)
 		if jt.jtl != nil {
 			t1 = jt.jtl.index
 		}
@@ -287,7 +342,7 @@ loopFor:
 		loopEnd:
 
 			if i == 0 || jtl == nil {
-				index = "T0"
+				index = t.alias(0)
 			} else {
 				index = jtl.index
 			}
@@ -328,7 +383,7 @@ loopFor:
 }
 
 // generate condition sql.
-func (t *dbTables) getCondSQL(cond *Condition, sub bool, tz *time.Location) (where string, params []interface{}) {
+func (t *dbTables) getCondSQL(cond *Condition, sub bool, tz *time.Location) (where string, params []interface{}, fields []*fieldInfo) {
 	if cond == nil || cond.IsEmpty() {
 		return
 	}
@@ -349,12 +404,22 @@ func (t *dbTables) getCondSQL(cond *Condition, sub bool, tz *time.Location) (whe
 			where += "NOT "
 		}
 		if p.isCond {
-			w, ps := t.getCondSQL(p.cond, true, tz)
+			w, ps, fs := t.getCondSQL(p.cond, true, tz)
 			if w != "" {
 				w = fmt.Sprintf("( %s) ", w)
 			}
 			where += w
 			params = append(params, ps...)
+			fields = append(fields, fs...)
+		} else if p.isExists {
+			w, ps, fs := t.getExistsSQL(p.exprs[0], p.cond, tz)
+			where += w
+			params = append(params, ps...)
+			fields = append(fields, fs...)
+		} else if p.isRaw && len(p.exprs) == 0 {
+			where += fmt.Sprintf("( %s) ", p.sql)
+			params = append(params, p.args...)
+			fields = append(fields, make([]*fieldInfo, len(p.args))...)
 		} else {
 			exprs := p.exprs
 
@@ -377,7 +442,7 @@ func (t *dbTables) getCondSQL(cond *Condition, sub bool, tz *time.Location) (whe
 			var operSQL string
 			var args []interface{}
 			if p.isRaw {
-				operSQL = p.sql
+				operSQL, args = p.sql, p.args
 			} else {
 				operSQL, args = t.base.GenerateOperatorSQL(mi, fi, operator, p.args, tz)
 			}
@@ -387,7 +452,9 @@ func (t *dbTables) getCondSQL(cond *Condition, sub bool, tz *time.Location) (whe
 
 			where += fmt.Sprintf("%s %s ", leftCol, operSQL)
 			params = append(params, args...)
-
+			for range args {
+				fields = append(fields, fi)
+			}
 		}
 	}
 
@@ -398,6 +465,123 @@ func (t *dbTables) getCondSQL(cond *Condition, sub bool, tz *time.Location) (whe
 	return
 }
 
+// generate the correlated EXISTS subquery on the related rows of rel.
+// rel is a foreign key, one to one or reverse relation field of the model,
+// cond is the condition of the related model.
+func (t *dbTables) getExistsSQL(rel string, cond *Condition, tz *time.Location) (string, []interface{}, []*fieldInfo) {
+	fi, ok := t.mi.fields.GetByAny(rel)
+	if !ok || !fi.rel && !fi.reverse {
+		panic(fmt.Errorf("unknown relation field name `%s`", rel))
+	}
+
+	var (
+		smi       *modelInfo
+		col, sCol string
+	)
+	switch {
+	case fi.reverse && (fi.reverseFieldInfo.fieldType == RelForeignKey || fi.reverseFieldInfo.fieldType == RelOneToOne):
+		smi = fi.reverseFieldInfo.mi
+		col, sCol = t.mi.fields.pk.column, fi.reverseFieldInfo.column
+	case fi.fieldType == RelForeignKey || fi.fieldType == RelOneToOne:
+		smi = fi.relModelInfo
+		col, sCol = fi.column, smi.fields.pk.column
+	default:
+		panic(fmt.Errorf("the many to many relation `%s` is not supported by the exists subquery", rel))
+	}
+
+	Q := t.base.TableQuote()
+
+	sub := newDbTables(smi, t.base)
+	sub.prefix = t.prefix + "S"
+	where, args, fields := sub.getCondSQL(cond, true, tz)
+	join := sub.getJoinSQL()
+
+	query := fmt.Sprintf("EXISTS (SELECT 1 FROM %s%s%s %s %sWHERE %s.%s%s%s = %s.%s%s%s ", Q, smi.table, Q, sub.alias(0),
+		join, sub.alias(0), Q, sCol, Q, t.alias(0), Q, col, Q)
+	if where != "" {
+		query += fmt.Sprintf("AND ( %s) ", where)
+	}
+	return query + ") ", args, fields
+}
+
+// HAVING condition on the alias of aggregate expression
+type havingCond struct {
+	expr string
+	args []interface{}
+}
+
+// get the expressions of aliases in aggregate, e.g. "dept_name,sum(salary) as total"
+func getAggregateAliases(aggregate string) map[string]string {
+	aliases := make(map[string]string)
+	depth, start := 0, 0
+	for i := 0; i <= len(aggregate); i++ {
+		if i < len(aggregate) {
+			switch aggregate[i] {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+			if aggregate[i] != ',' || depth > 0 {
+				continue
+			}
+		}
+		item := strings.TrimSpace(aggregate[start:i])
+		start = i + 1
+		if idx := strings.LastIndex(strings.ToLower(item), " as "); idx > 0 {
+			alias := strings.Trim(strings.TrimSpace(item[idx+4:]), "`\"")
+			aliases[alias] = strings.TrimSpace(item[:idx])
+		}
+	}
+	return aliases
+}
+
+// generate having sql.
+func (t *dbTables) getHavingSQL(aggregate string, havings []havingCond, tz *time.Location) (string, []interface{}, error) {
+	if len(havings) == 0 {
+		return "", nil, nil
+	}
+
+	aliases := getAggregateAliases(aggregate)
+	sqls := make([]string, 0, len(havings))
+	var args []interface{}
+	for _, h := range havings {
+		alias, operator := h.expr, "exact"
+		if i := strings.LastIndex(h.expr, ExprSep); i > 0 {
+			alias, operator = h.expr[:i], h.expr[i+len(ExprSep):]
+		}
+		expr, ok := aliases[alias]
+		if !ok {
+			return "", nil, fmt.Errorf("unknown aggregate alias `%s`", alias)
+		}
+
+		params := getFlatParams(nil, h.args, tz)
+		var sql string
+		switch operator {
+		case "exact", "gt", "gte", "lt", "lte":
+			if len(params) != 1 {
+				return "", nil, fmt.Errorf("operator `%s` need 1 args not %d", operator, len(params))
+			}
+			sql = t.base.OperatorSQL(operator)
+		case "in":
+			if len(params) == 0 {
+				return "", nil, fmt.Errorf("operator `%s` need at least one args", operator)
+			}
+			sql = fmt.Sprintf("IN (%s)", strings.TrimSuffix(strings.Repeat("?, ", len(params)), ", "))
+		case "between":
+			if len(params) != 2 {
+				return "", nil, fmt.Errorf("operator `%s` need 2 args not %d", operator, len(params))
+			}
+			sql = "BETWEEN ? AND ?"
+		default:
+			return "", nil, fmt.Errorf("unsupported having operator `%s`", operator)
+		}
+		sqls = append(sqls, fmt.Sprintf("%s %s", expr, sql))
+		args = append(args, params...)
+	}
+	return fmt.Sprintf("HAVING %s ", strings.Join(sqls, " AND ")), args, nil
+}
+
 // generate group sql.
 func (t *dbTables) getGroupSQL(groups []string) (groupSQL string) {
 	if len(groups) == 0 {
@@ -423,8 +607,8 @@ func (t *dbTables) getGroupSQL(groups []string) (groupSQL string) {
 }
 
 // generate order sql.
-func (t *dbTables) getOrderSQL(orders []*order_clause.Order) (orderSQL string) {
-	if len(orders) == 0 {
+func (t *dbTables) getOrderSQL(orders []*order_clause.Order, random bool) (orderSQL string) {
+	if len(orders) == 0 && !random {
 		return
 	}
 
@@ -437,9 +621,9 @@ func (t *dbTables) getOrderSQL(orders []*order_clause.Order) (orderSQL string) {
 
 		if order.IsRaw() {
 			if len(clause) == 2 {
-				orderSqls = append(orderSqls, fmt.Sprintf("%s.%s%s%s %s", clause[0], Q, clause[1], Q, order.SortString()))
+				column = fmt.Sprintf("%s.%s%s%s", clause[0], Q, clause[1], Q)
 			} else if len(clause) == 1 {
-				orderSqls = append(orderSqls, fmt.Sprintf("%s%s%s %s", Q, clause[0], Q, order.SortString()))
+				column = fmt.Sprintf("%s%s%s", Q, clause[0], Q)
 			} else {
 				panic(fmt.Errorf("unknown field/column name `%s`", strings.Join(clause, ExprSep)))
 			}
@@ -449,8 +633,18 @@ func (t *dbTables) getOrderSQL(orders []*order_clause.Order) (orderSQL string) {
 				panic(fmt.Errorf("unknown field/column name `%s`", strings.Join(clause, ExprSep)))
 			}
 
-			orderSqls = append(orderSqls, fmt.Sprintf("%s.%s%s%s %s", index, Q, fi.column, Q, order.SortString()))
+			column = fmt.Sprintf("%s.%s%s%s", index, Q, fi.column, Q)
 		}
+
+		if order.GetNulls() != order_clause.NullsDefault {
+			orderSqls = append(orderSqls, t.base.GenerateOrderNulls(column, order.SortString(), order.GetNulls()))
+		} else {
+			orderSqls = append(orderSqls, fmt.Sprintf("%s %s", column, order.SortString()))
+		}
+	}
+
+	if random {
+		orderSqls = append(orderSqls, t.base.GenerateRandomOrder())
 	}
 
 	orderSQL = fmt.Sprintf("ORDER BY %s ", strings.Join(orderSqls, ", "))
@@ -495,5 +689,6 @@ func newDbTables(mi *modelInfo, base dbBaser) *dbTables {
 	tables.tablesM = make(map[string]*dbTable)
 	tables.mi = mi
 	tables.base = base
+	tables.prefix = "T"
 	return tables
 }
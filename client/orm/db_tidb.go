@@ -16,7 +16,10 @@ package orm
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+
+	"github.com/beego/beego/v2/client/orm/clauses/order_clause"
 )
 
 // mysql dbBaser implementation.
@@ -36,6 +39,19 @@ func (d *dbBaseTidb) DbTypes() map[string]string {
 	return mysqlTypes
 }
 
+// check whether err means the connection is lost, tidb uses the mysql protocol.
+func (d *dbBaseTidb) IsConnError(err error) bool {
+	return (&dbBaseMysql{}).IsConnError(err)
+}
+
+func (d *dbBaseTidb) GenerateGroupConcatSQL(column string, sep string) (string, error) {
+	return (&dbBaseMysql{}).GenerateGroupConcatSQL(column, sep)
+}
+
+func (d *dbBaseTidb) GenerateResetAutoIncrementSQL(table string, column string) (string, error) {
+	return (&dbBaseMysql{}).GenerateResetAutoIncrementSQL(table, column)
+}
+
 // show table sql for mysql.
 func (d *dbBaseTidb) ShowTablesQuery() string {
 	return "SELECT table_name FROM information_schema.tables WHERE table_type = 'BASE TABLE' AND table_schema = DATABASE()"
@@ -56,6 +72,25 @@ func (d *dbBaseTidb) IndexExists(ctx context.Context, db dbQuerier, table string
 	return cnt > 0
 }
 
+// tidb only supports read committed and repeatable read(snapshot isolation).
+func (d *dbBaseTidb) SupportIsolationLevel(level sql.IsolationLevel) bool {
+	switch level {
+	case sql.LevelDefault, sql.LevelReadCommitted, sql.LevelRepeatableRead, sql.LevelSnapshot:
+		return true
+	}
+	return false
+}
+
+// GenerateOrderNulls emulate NULLS FIRST/LAST with ISNULL(column) like mysql.
+func (d *dbBaseTidb) GenerateOrderNulls(column string, sort string, nulls order_clause.Nulls) string {
+	return generateISNULLOrder(column, sort, nulls)
+}
+
+// GenerateRandomOrder use RAND() like mysql.
+func (d *dbBaseTidb) GenerateRandomOrder() string {
+	return (&dbBaseMysql{}).GenerateRandomOrder()
+}
+
 // create new mysql dbBaser.
 func newdbBaseTidb() dbBaser {
 	b := new(dbBaseTidb)
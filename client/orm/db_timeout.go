@@ -0,0 +1,43 @@
+// Copyright 2020 beego
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orm
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// the timeout of the queries which context has no deadline, 0 means no timeout
+var defaultQueryTimeout int64
+
+// SetDefaultQueryTimeout Change the timeout of each query which context has no deadline,
+// the context with a deadline is used as it is. use 0 to disable it, which is the default.
+// the rows of Query are read within the timeout too.
+func SetDefaultQueryTimeout(timeout time.Duration) {
+	atomic.StoreInt64(&defaultQueryTimeout, int64(timeout))
+}
+
+// return ctx with the default query timeout if ctx has no deadline
+func withQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := time.Duration(atomic.LoadInt64(&defaultQueryTimeout))
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
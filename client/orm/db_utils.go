@@ -17,9 +17,59 @@ package orm
 import (
 	"fmt"
 	"reflect"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
+// split the conflict target of InsertOrUpdate into the column and the WHERE clause of partial unique index,
+// e.g. "user_name WHERE deleted_at IS NULL" returns "user_name" and " WHERE deleted_at IS NULL".
+func splitConflictTarget(target string) (string, string) {
+	if i := strings.Index(strings.ToUpper(target), " WHERE "); i > 0 {
+		return strings.TrimSpace(target[:i]), " WHERE " + strings.TrimSpace(target[i+7:])
+	}
+	return strings.TrimSpace(target), ""
+}
+
+// check the driver supports FOR UPDATE NOWAIT, mysql supports it since 8.0.
+func isNoWaitSupported(driver DriverType) bool {
+	switch driver {
+	case DRMySQL, DRPostgres:
+		return true
+	}
+	return false
+}
+
+// check the database of alias supports window function, e.g. COUNT(*) OVER(),
+// mysql supports it since 8.0 and sqlite since 3.25, the older ones are marked on the first failure.
+func isWindowFuncSupported(al *alias) bool {
+	if atomic.LoadInt32(&al.noWindowFunc) != 0 {
+		return false
+	}
+	switch al.Driver {
+	case DRMySQL, DRPostgres, DRSqlite, DRTiDB, DROracle:
+		return true
+	}
+	return false
+}
+
+// check whether err is the syntax error of sql, e.g. mysql error 1064 or sqlite `near "(": syntax error`
+func isSyntaxError(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "syntax")
+}
+
+// convert the error of FOR UPDATE NOWAIT to ErrLockNotAvailable when the rows are locked.
+// postgres returns lock_not_available(55P03), mysql returns ER_LOCK_NOWAIT(3572).
+func convertLockError(err error) error {
+	msg := err.Error()
+	if strings.Contains(msg, "could not obtain lock") ||
+		strings.Contains(msg, "55P03") ||
+		strings.Contains(msg, "Error 3572") {
+		return fmt.Errorf("%w: %s", ErrLockNotAvailable, msg)
+	}
+	return err
+}
+
 // get table alias.
 func getDbAlias(name string) *alias {
 	if al, ok := dataBaseCache.get(name); ok {
@@ -53,6 +103,30 @@ func getExistPk(mi *modelInfo, ind reflect.Value) (column string, value interfac
 	return
 }
 
+// get the comparable kind of field, relation fields use the kind of related pk.
+func getFieldKind(fi *fieldInfo) int {
+	ft := fi.fieldType
+	if ft&IsRelField > 0 && fi.relModelInfo != nil {
+		return getFieldKind(fi.relModelInfo.fields.pk)
+	}
+	switch {
+	case ft&(IsIntegerField|TypeFloatField|TypeDecimalField) > 0:
+		return TypeIntegerField
+	case ft&(TypeVarCharField|TypeCharField|TypeTextField) > 0:
+		return TypeVarCharField
+	case ft&(TypeDateField|TypeDateTimeField) > 0:
+		return TypeDateTimeField
+	case ft&(TypeJSONField|TypeJsonbField) > 0:
+		return TypeJSONField
+	}
+	return ft
+}
+
+// check whether the value of column src can be assigned to column dst.
+func isCompatibleColumn(dst, src *fieldInfo) bool {
+	return getFieldKind(dst) == getFieldKind(src)
+}
+
 // get fields description as flatted string.
 func getFlatParams(fi *fieldInfo, args []interface{}, tz *time.Location) (params []interface{}) {
 outFor:
@@ -64,6 +138,16 @@ outFor:
 
 		val := reflect.ValueOf(arg)
 		kind := val.Kind()
+		if fi != nil && fi.isScanner {
+			v, ok, err := getValuerArg(arg)
+			if err != nil {
+				panic(fmt.Errorf("the value of field `%s`: %w", fi.fullName, err))
+			}
+			if ok {
+				params = append(params, v)
+				continue
+			}
+		}
 		if kind == reflect.Ptr {
 			val = val.Elem()
 			kind = val.Kind()
@@ -17,6 +17,7 @@ package orm
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/beego/beego/v2/core/utils"
 )
@@ -37,6 +38,14 @@ func (d *DoNothingOrm) ReadWithCtx(ctx context.Context, md interface{}, cols ...
 	return nil
 }
 
+func (d *DoNothingOrm) ReadInLocation(md interface{}, loc *time.Location, cols ...string) error {
+	return nil
+}
+
+func (d *DoNothingOrm) ReadInLocationWithCtx(ctx context.Context, md interface{}, loc *time.Location, cols ...string) error {
+	return nil
+}
+
 func (d *DoNothingOrm) ReadForUpdate(md interface{}, cols ...string) error {
 	return nil
 }
@@ -53,6 +62,14 @@ func (d *DoNothingOrm) ReadOrCreateWithCtx(ctx context.Context, md interface{},
 	return false, 0, nil
 }
 
+func (d *DoNothingOrm) ReadOrCreateMulti(mds interface{}, lookupCols []string) ([]bool, error) {
+	return nil, nil
+}
+
+func (d *DoNothingOrm) ReadOrCreateMultiWithCtx(ctx context.Context, mds interface{}, lookupCols []string) ([]bool, error) {
+	return nil, nil
+}
+
 func (d *DoNothingOrm) LoadRelated(md interface{}, name string, args ...utils.KV) (int64, error) {
 	return 0, nil
 }
@@ -61,6 +78,14 @@ func (d *DoNothingOrm) LoadRelatedWithCtx(ctx context.Context, md interface{}, n
 	return 0, nil
 }
 
+func (d *DoNothingOrm) LoadRelatedBatch(mds interface{}, name string, args ...utils.KV) (int64, error) {
+	return 0, nil
+}
+
+func (d *DoNothingOrm) LoadRelatedBatchWithCtx(ctx context.Context, mds interface{}, name string, args ...utils.KV) (int64, error) {
+	return 0, nil
+}
+
 func (d *DoNothingOrm) QueryM2M(md interface{}, name string) QueryM2Mer {
 	return nil
 }
@@ -74,6 +99,10 @@ func (d *DoNothingOrm) QueryTable(ptrStructOrTableName interface{}) QuerySeter {
 	return nil
 }
 
+func (d *DoNothingOrm) QueryTableE(ptrStructOrTableName interface{}) (QuerySeter, error) {
+	return nil, nil
+}
+
 // NOTE: this method is deprecated, context parameter will not take effect.
 func (d *DoNothingOrm) QueryTableWithCtx(ctx context.Context, ptrStructOrTableName interface{}) QuerySeter {
 	return nil
@@ -83,6 +112,26 @@ func (d *DoNothingOrm) DBStats() *sql.DBStats {
 	return nil
 }
 
+func (d *DoNothingOrm) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (d *DoNothingOrm) DryRun() Ormer {
+	return d
+}
+
+func (d *DoNothingOrm) LastSQL() (string, []interface{}) {
+	return "", nil
+}
+
+func (d *DoNothingOrm) WithSQLRecorder(recorder *SQLRecorder) Ormer {
+	return d
+}
+
+func (d *DoNothingOrm) RawDB() *sql.DB {
+	return nil
+}
+
 func (d *DoNothingOrm) Insert(md interface{}) (int64, error) {
 	return 0, nil
 }
@@ -91,6 +140,22 @@ func (d *DoNothingOrm) InsertWithCtx(ctx context.Context, md interface{}) (int64
 	return 0, nil
 }
 
+func (d *DoNothingOrm) InsertOrIgnore(md interface{}, conflictCols ...string) (int64, error) {
+	return 0, nil
+}
+
+func (d *DoNothingOrm) InsertOrIgnoreWithCtx(ctx context.Context, md interface{}, conflictCols ...string) (int64, error) {
+	return 0, nil
+}
+
+func (d *DoNothingOrm) InsertWithPK(md interface{}) (int64, error) {
+	return 0, nil
+}
+
+func (d *DoNothingOrm) InsertWithPKWithCtx(ctx context.Context, md interface{}) (int64, error) {
+	return 0, nil
+}
+
 func (d *DoNothingOrm) InsertOrUpdate(md interface{}, colConflitAndArgs ...string) (int64, error) {
 	return 0, nil
 }
@@ -107,6 +172,14 @@ func (d *DoNothingOrm) InsertMultiWithCtx(ctx context.Context, bulk int, mds int
 	return 0, nil
 }
 
+func (d *DoNothingOrm) InsertMultiResult(bulk int, mds interface{}) ([]sql.Result, error) {
+	return nil, nil
+}
+
+func (d *DoNothingOrm) InsertMultiResultWithCtx(ctx context.Context, bulk int, mds interface{}) ([]sql.Result, error) {
+	return nil, nil
+}
+
 func (d *DoNothingOrm) Update(md interface{}, cols ...string) (int64, error) {
 	return 0, nil
 }
@@ -123,6 +196,14 @@ func (d *DoNothingOrm) DeleteWithCtx(ctx context.Context, md interface{}, cols .
 	return 0, nil
 }
 
+func (d *DoNothingOrm) DeleteWhere(md interface{}, cond *Condition) (int64, error) {
+	return 0, nil
+}
+
+func (d *DoNothingOrm) DeleteWhereWithCtx(ctx context.Context, md interface{}, cond *Condition) (int64, error) {
+	return 0, nil
+}
+
 func (d *DoNothingOrm) Raw(query string, args ...interface{}) RawSeter {
 	return nil
 }
@@ -151,6 +232,18 @@ func (d *DoNothingOrm) BeginWithCtxAndOpts(ctx context.Context, opts *sql.TxOpti
 	return nil, nil
 }
 
+func (d *DoNothingOrm) BeginReadCommitted(ctx context.Context) (TxOrmer, error) {
+	return nil, nil
+}
+
+func (d *DoNothingOrm) BeginRepeatableRead(ctx context.Context) (TxOrmer, error) {
+	return nil, nil
+}
+
+func (d *DoNothingOrm) BeginSerializable(ctx context.Context) (TxOrmer, error) {
+	return nil, nil
+}
+
 func (d *DoNothingOrm) DoTx(task func(ctx context.Context, txOrm TxOrmer) error) error {
 	return nil
 }
@@ -179,3 +272,23 @@ func (d *DoNothingTxOrm) Commit() error {
 func (d *DoNothingTxOrm) Rollback() error {
 	return nil
 }
+
+func (d *DoNothingTxOrm) DB() *sql.Tx {
+	return nil
+}
+
+func (d *DoNothingTxOrm) Savepoint(name string) error {
+	return nil
+}
+
+func (d *DoNothingTxOrm) RollbackTo(name string) error {
+	return nil
+}
+
+func (d *DoNothingTxOrm) ReleaseSavepoint(name string) error {
+	return nil
+}
+
+func (d *DoNothingTxOrm) ExecBatch(stmts []RawStmt) ([]sql.Result, error) {
+	return nil, nil
+}
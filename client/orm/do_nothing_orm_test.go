@@ -15,6 +15,7 @@
 package orm
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -39,6 +40,8 @@ func TestDoNothingOrm(t *testing.T) {
 	assert.Nil(t, o.QueryM2M(nil, ""))
 	assert.Nil(t, o.ReadWithCtx(nil, nil))
 	assert.Nil(t, o.Read(nil))
+	assert.Nil(t, o.ReadInLocation(nil, nil))
+	assert.Nil(t, o.ReadInLocationWithCtx(nil, nil, nil))
 
 	txOrm, err := o.BeginWithCtxAndOpts(nil, nil)
 	assert.Nil(t, err)
@@ -56,6 +59,18 @@ func TestDoNothingOrm(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Nil(t, txOrm)
 
+	txOrm, err = o.BeginReadCommitted(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, txOrm)
+
+	txOrm, err = o.BeginRepeatableRead(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, txOrm)
+
+	txOrm, err = o.BeginSerializable(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, txOrm)
+
 	assert.Nil(t, o.RawWithCtx(nil, ""))
 	assert.Nil(t, o.Raw(""))
 
@@ -63,6 +78,14 @@ func TestDoNothingOrm(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, int64(0), i)
 
+	results, err := o.InsertMultiResult(0, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, results)
+
+	results, err = o.InsertMultiResultWithCtx(nil, 0, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, results)
+
 	i, err = o.Insert(nil)
 	assert.Nil(t, err)
 	assert.Equal(t, int64(0), i)
@@ -79,6 +102,22 @@ func TestDoNothingOrm(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, int64(0), i)
 
+	i, err = o.InsertOrIgnoreWithCtx(nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), i)
+
+	i, err = o.InsertOrIgnore(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), i)
+
+	i, err = o.InsertWithPKWithCtx(nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), i)
+
+	i, err = o.InsertWithPK(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), i)
+
 	i, err = o.InsertMultiWithCtx(nil, 0, nil)
 	assert.Nil(t, err)
 	assert.Equal(t, int64(0), i)
@@ -88,11 +127,19 @@ func TestDoNothingOrm(t *testing.T) {
 	assert.Equal(t, int64(0), i)
 
 	i, err = o.LoadRelated(nil, "")
+	assert.Equal(t, int64(0), i)
+	assert.Nil(t, err)
+
+	i, err = o.LoadRelatedBatch(nil, "")
 	assert.Nil(t, err)
 	assert.Equal(t, int64(0), i)
 
 	assert.Nil(t, o.QueryTable(nil))
 
+	qs, err := o.QueryTableE(nil)
+	assert.Nil(t, qs)
+	assert.Nil(t, err)
+
 	assert.Nil(t, o.Read(nil))
 	assert.Nil(t, o.ReadWithCtx(nil, nil))
 	assert.Nil(t, o.ReadForUpdateWithCtx(nil, nil))
@@ -108,6 +155,14 @@ func TestDoNothingOrm(t *testing.T) {
 	assert.Equal(t, int64(0), i)
 	assert.False(t, ok)
 
+	created, err := o.ReadOrCreateMulti(nil, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, created)
+
+	created, err = o.ReadOrCreateMultiWithCtx(nil, nil, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, created)
+
 	i, err = o.Delete(nil)
 	assert.Nil(t, err)
 	assert.Equal(t, int64(0), i)
@@ -116,6 +171,14 @@ func TestDoNothingOrm(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, int64(0), i)
 
+	i, err = o.DeleteWhere(nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), i)
+
+	i, err = o.DeleteWhereWithCtx(nil, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), i)
+
 	i, err = o.Update(nil)
 	assert.Nil(t, err)
 	assert.Equal(t, int64(0), i)
@@ -125,8 +188,23 @@ func TestDoNothingOrm(t *testing.T) {
 	assert.Equal(t, int64(0), i)
 
 	assert.Nil(t, o.DBStats())
+	assert.Nil(t, o.Ping(context.Background()))
+
+	assert.Equal(t, o, o.DryRun())
+	query, args := o.LastSQL()
+	assert.Equal(t, "", query)
+	assert.Nil(t, args)
+	assert.Equal(t, o, o.WithSQLRecorder(NewSQLRecorder()))
+	assert.Nil(t, o.RawDB())
 
 	to := &DoNothingTxOrm{}
 	assert.Nil(t, to.Commit())
 	assert.Nil(t, to.Rollback())
+	assert.Nil(t, to.DB())
+	assert.Nil(t, to.Savepoint("sp"))
+	assert.Nil(t, to.RollbackTo("sp"))
+	assert.Nil(t, to.ReleaseSavepoint("sp"))
+	res, err := to.ExecBatch([]RawStmt{{SQL: "SELECT 1"}})
+	assert.Nil(t, res)
+	assert.Nil(t, err)
 }
@@ -43,6 +43,8 @@ type filterOrmDecorator struct {
 	insideTx    bool
 	txStartTime time.Time
 	txName      string
+	// the begin context of transaction
+	txCtx context.Context
 }
 
 func NewFilterOrmDecorator(delegate Ormer, filterChains ...FilterChain) Ormer {
@@ -70,11 +72,22 @@ func NewFilterTxOrmDecorator(delegate TxOrmer, root Filter, txName string) TxOrm
 		txStartTime: time.Now(),
 		txName:      txName,
 	}
+	if d, ok := delegate.(interface{ defaultCtx() context.Context }); ok {
+		res.txCtx = d.defaultCtx()
+	}
 	return res
 }
 
+// the context used by the methods without ctx
+func (f *filterOrmDecorator) defaultCtx() context.Context {
+	if f.txCtx != nil {
+		return f.txCtx
+	}
+	return context.Background()
+}
+
 func (f *filterOrmDecorator) Read(md interface{}, cols ...string) error {
-	return f.ReadWithCtx(context.Background(), md, cols...)
+	return f.ReadWithCtx(f.defaultCtx(), md, cols...)
 }
 
 func (f *filterOrmDecorator) ReadWithCtx(ctx context.Context, md interface{}, cols ...string) error {
@@ -95,8 +108,30 @@ func (f *filterOrmDecorator) ReadWithCtx(ctx context.Context, md interface{}, co
 	return f.convertError(res[0])
 }
 
+func (f *filterOrmDecorator) ReadInLocation(md interface{}, loc *time.Location, cols ...string) error {
+	return f.ReadInLocationWithCtx(f.defaultCtx(), md, loc, cols...)
+}
+
+func (f *filterOrmDecorator) ReadInLocationWithCtx(ctx context.Context, md interface{}, loc *time.Location, cols ...string) error {
+	mi, _ := modelCache.getByMd(md)
+	inv := &Invocation{
+		Method:      "ReadInLocationWithCtx",
+		Args:        []interface{}{md, loc, cols},
+		Md:          md,
+		mi:          mi,
+		InsideTx:    f.insideTx,
+		TxStartTime: f.txStartTime,
+		f: func(c context.Context) []interface{} {
+			err := f.ormer.ReadInLocationWithCtx(c, md, loc, cols...)
+			return []interface{}{err}
+		},
+	}
+	res := f.root(ctx, inv)
+	return f.convertError(res[0])
+}
+
 func (f *filterOrmDecorator) ReadForUpdate(md interface{}, cols ...string) error {
-	return f.ReadForUpdateWithCtx(context.Background(), md, cols...)
+	return f.ReadForUpdateWithCtx(f.defaultCtx(), md, cols...)
 }
 
 func (f *filterOrmDecorator) ReadForUpdateWithCtx(ctx context.Context, md interface{}, cols ...string) error {
@@ -118,7 +153,7 @@ func (f *filterOrmDecorator) ReadForUpdateWithCtx(ctx context.Context, md interf
 }
 
 func (f *filterOrmDecorator) ReadOrCreate(md interface{}, col1 string, cols ...string) (bool, int64, error) {
-	return f.ReadOrCreateWithCtx(context.Background(), md, col1, cols...)
+	return f.ReadOrCreateWithCtx(f.defaultCtx(), md, col1, cols...)
 }
 
 func (f *filterOrmDecorator) ReadOrCreateWithCtx(ctx context.Context, md interface{}, col1 string, cols ...string) (bool, int64, error) {
@@ -139,8 +174,32 @@ func (f *filterOrmDecorator) ReadOrCreateWithCtx(ctx context.Context, md interfa
 	return res[0].(bool), res[1].(int64), f.convertError(res[2])
 }
 
+func (f *filterOrmDecorator) ReadOrCreateMulti(mds interface{}, lookupCols []string) ([]bool, error) {
+	return f.ReadOrCreateMultiWithCtx(f.defaultCtx(), mds, lookupCols)
+}
+
+// ReadOrCreateMultiWithCtx uses the first element's model info
+func (f *filterOrmDecorator) ReadOrCreateMultiWithCtx(ctx context.Context, mds interface{}, lookupCols []string) ([]bool, error) {
+	md, mi := firstMdOf(mds)
+	inv := &Invocation{
+		Method:      "ReadOrCreateMultiWithCtx",
+		Args:        []interface{}{mds, lookupCols},
+		Md:          md,
+		mi:          mi,
+		InsideTx:    f.insideTx,
+		TxStartTime: f.txStartTime,
+		f: func(c context.Context) []interface{} {
+			created, err := f.ormer.ReadOrCreateMultiWithCtx(c, mds, lookupCols)
+			return []interface{}{created, err}
+		},
+	}
+	res := f.root(ctx, inv)
+	created, _ := res[0].([]bool)
+	return created, f.convertError(res[1])
+}
+
 func (f *filterOrmDecorator) LoadRelated(md interface{}, name string, args ...utils.KV) (int64, error) {
-	return f.LoadRelatedWithCtx(context.Background(), md, name, args...)
+	return f.LoadRelatedWithCtx(f.defaultCtx(), md, name, args...)
 }
 
 func (f *filterOrmDecorator) LoadRelatedWithCtx(ctx context.Context, md interface{}, name string, args ...utils.KV) (int64, error) {
@@ -161,6 +220,28 @@ func (f *filterOrmDecorator) LoadRelatedWithCtx(ctx context.Context, md interfac
 	return res[0].(int64), f.convertError(res[1])
 }
 
+func (f *filterOrmDecorator) LoadRelatedBatch(mds interface{}, name string, args ...utils.KV) (int64, error) {
+	return f.LoadRelatedBatchWithCtx(f.defaultCtx(), mds, name, args...)
+}
+
+func (f *filterOrmDecorator) LoadRelatedBatchWithCtx(ctx context.Context, mds interface{}, name string, args ...utils.KV) (int64, error) {
+	md, mi := firstMdOf(mds)
+	inv := &Invocation{
+		Method:      "LoadRelatedBatchWithCtx",
+		Args:        []interface{}{mds, name, args},
+		Md:          md,
+		mi:          mi,
+		InsideTx:    f.insideTx,
+		TxStartTime: f.txStartTime,
+		f: func(c context.Context) []interface{} {
+			res, err := f.ormer.LoadRelatedBatchWithCtx(c, mds, name, args...)
+			return []interface{}{res, err}
+		},
+	}
+	res := f.root(ctx, inv)
+	return res[0].(int64), f.convertError(res[1])
+}
+
 func (f *filterOrmDecorator) QueryM2M(md interface{}, name string) QueryM2Mer {
 	mi, _ := modelCache.getByMd(md)
 	inv := &Invocation{
@@ -175,7 +256,7 @@ func (f *filterOrmDecorator) QueryM2M(md interface{}, name string) QueryM2Mer {
 			return []interface{}{res}
 		},
 	}
-	res := f.root(context.Background(), inv)
+	res := f.root(f.defaultCtx(), inv)
 	if res[0] == nil {
 		return nil
 	}
@@ -218,7 +299,7 @@ func (f *filterOrmDecorator) QueryTable(ptrStructOrTableName interface{}) QueryS
 			return []interface{}{res}
 		},
 	}
-	res := f.root(context.Background(), inv)
+	res := f.root(f.defaultCtx(), inv)
 
 	if res[0] == nil {
 		return nil
@@ -226,6 +307,44 @@ func (f *filterOrmDecorator) QueryTable(ptrStructOrTableName interface{}) QueryS
 	return res[0].(QuerySeter)
 }
 
+func (f *filterOrmDecorator) QueryTableE(ptrStructOrTableName interface{}) (QuerySeter, error) {
+	var (
+		name string
+		md   interface{}
+		mi   *modelInfo
+	)
+
+	if table, ok := ptrStructOrTableName.(string); ok {
+		name = table
+	} else {
+		name = getFullName(indirectType(reflect.TypeOf(ptrStructOrTableName)))
+		md = ptrStructOrTableName
+	}
+
+	if m, ok := modelCache.getByFullName(name); ok {
+		mi = m
+	}
+
+	inv := &Invocation{
+		Method:      "QueryTableE",
+		Args:        []interface{}{ptrStructOrTableName},
+		InsideTx:    f.insideTx,
+		TxStartTime: f.txStartTime,
+		Md:          md,
+		mi:          mi,
+		f: func(c context.Context) []interface{} {
+			res, err := f.ormer.QueryTableE(ptrStructOrTableName)
+			return []interface{}{res, err}
+		},
+	}
+	res := f.root(f.defaultCtx(), inv)
+
+	if res[0] == nil {
+		return nil, f.convertError(res[1])
+	}
+	return res[0].(QuerySeter), f.convertError(res[1])
+}
+
 // NOTE: this method is deprecated, context parameter will not take effect.
 func (f *filterOrmDecorator) QueryTableWithCtx(_ context.Context, ptrStructOrTableName interface{}) QuerySeter {
 	logs.Warn("QueryTableWithCtx is DEPRECATED. Use methods with `WithCtx`on QuerySeter suffix as replacement.")
@@ -242,7 +361,7 @@ func (f *filterOrmDecorator) DBStats() *sql.DBStats {
 			return []interface{}{res}
 		},
 	}
-	res := f.root(context.Background(), inv)
+	res := f.root(f.defaultCtx(), inv)
 
 	if res[0] == nil {
 		return nil
@@ -251,8 +370,22 @@ func (f *filterOrmDecorator) DBStats() *sql.DBStats {
 	return res[0].(*sql.DBStats)
 }
 
+func (f *filterOrmDecorator) Ping(ctx context.Context) error {
+	inv := &Invocation{
+		Method:      "Ping",
+		InsideTx:    f.insideTx,
+		TxStartTime: f.txStartTime,
+		f: func(c context.Context) []interface{} {
+			err := f.ormer.Ping(c)
+			return []interface{}{err}
+		},
+	}
+	res := f.root(ctx, inv)
+	return f.convertError(res[0])
+}
+
 func (f *filterOrmDecorator) Insert(md interface{}) (int64, error) {
-	return f.InsertWithCtx(context.Background(), md)
+	return f.InsertWithCtx(f.defaultCtx(), md)
 }
 
 func (f *filterOrmDecorator) InsertWithCtx(ctx context.Context, md interface{}) (int64, error) {
@@ -274,7 +407,7 @@ func (f *filterOrmDecorator) InsertWithCtx(ctx context.Context, md interface{})
 }
 
 func (f *filterOrmDecorator) InsertOrUpdate(md interface{}, colConflitAndArgs ...string) (int64, error) {
-	return f.InsertOrUpdateWithCtx(context.Background(), md, colConflitAndArgs...)
+	return f.InsertOrUpdateWithCtx(f.defaultCtx(), md, colConflitAndArgs...)
 }
 
 func (f *filterOrmDecorator) InsertOrUpdateWithCtx(ctx context.Context, md interface{}, colConflitAndArgs ...string) (int64, error) {
@@ -295,25 +428,57 @@ func (f *filterOrmDecorator) InsertOrUpdateWithCtx(ctx context.Context, md inter
 	return res[0].(int64), f.convertError(res[1])
 }
 
-func (f *filterOrmDecorator) InsertMulti(bulk int, mds interface{}) (int64, error) {
-	return f.InsertMultiWithCtx(context.Background(), bulk, mds)
+func (f *filterOrmDecorator) InsertOrIgnore(md interface{}, conflictCols ...string) (int64, error) {
+	return f.InsertOrIgnoreWithCtx(f.defaultCtx(), md, conflictCols...)
 }
 
-// InsertMultiWithCtx uses the first element's model info
-func (f *filterOrmDecorator) InsertMultiWithCtx(ctx context.Context, bulk int, mds interface{}) (int64, error) {
-	var (
-		md interface{}
-		mi *modelInfo
-	)
+func (f *filterOrmDecorator) InsertOrIgnoreWithCtx(ctx context.Context, md interface{}, conflictCols ...string) (int64, error) {
+	mi, _ := modelCache.getByMd(md)
+	inv := &Invocation{
+		Method:      "InsertOrIgnoreWithCtx",
+		Args:        []interface{}{md, conflictCols},
+		Md:          md,
+		mi:          mi,
+		InsideTx:    f.insideTx,
+		TxStartTime: f.txStartTime,
+		f: func(c context.Context) []interface{} {
+			res, err := f.ormer.InsertOrIgnoreWithCtx(c, md, conflictCols...)
+			return []interface{}{res, err}
+		},
+	}
+	res := f.root(ctx, inv)
+	return res[0].(int64), f.convertError(res[1])
+}
 
-	sind := reflect.Indirect(reflect.ValueOf(mds))
+func (f *filterOrmDecorator) InsertWithPK(md interface{}) (int64, error) {
+	return f.InsertWithPKWithCtx(f.defaultCtx(), md)
+}
 
-	if (sind.Kind() == reflect.Array || sind.Kind() == reflect.Slice) && sind.Len() > 0 {
-		ind := reflect.Indirect(sind.Index(0))
-		md = ind.Interface()
-		mi, _ = modelCache.getByMd(md)
+func (f *filterOrmDecorator) InsertWithPKWithCtx(ctx context.Context, md interface{}) (int64, error) {
+	mi, _ := modelCache.getByMd(md)
+	inv := &Invocation{
+		Method:      "InsertWithPKWithCtx",
+		Args:        []interface{}{md},
+		Md:          md,
+		mi:          mi,
+		InsideTx:    f.insideTx,
+		TxStartTime: f.txStartTime,
+		f: func(c context.Context) []interface{} {
+			res, err := f.ormer.InsertWithPKWithCtx(c, md)
+			return []interface{}{res, err}
+		},
 	}
+	res := f.root(ctx, inv)
+	return res[0].(int64), f.convertError(res[1])
+}
 
+func (f *filterOrmDecorator) InsertMulti(bulk int, mds interface{}) (int64, error) {
+	return f.InsertMultiWithCtx(f.defaultCtx(), bulk, mds)
+}
+
+// InsertMultiWithCtx uses the first element's model info
+func (f *filterOrmDecorator) InsertMultiWithCtx(ctx context.Context, bulk int, mds interface{}) (int64, error) {
+	md, mi := firstMdOf(mds)
 	inv := &Invocation{
 		Method:      "InsertMultiWithCtx",
 		Args:        []interface{}{bulk, mds},
@@ -330,8 +495,43 @@ func (f *filterOrmDecorator) InsertMultiWithCtx(ctx context.Context, bulk int, m
 	return res[0].(int64), f.convertError(res[1])
 }
 
+func (f *filterOrmDecorator) InsertMultiResult(bulk int, mds interface{}) ([]sql.Result, error) {
+	return f.InsertMultiResultWithCtx(f.defaultCtx(), bulk, mds)
+}
+
+// InsertMultiResultWithCtx uses the first element's model info
+func (f *filterOrmDecorator) InsertMultiResultWithCtx(ctx context.Context, bulk int, mds interface{}) ([]sql.Result, error) {
+	md, mi := firstMdOf(mds)
+	inv := &Invocation{
+		Method:      "InsertMultiResultWithCtx",
+		Args:        []interface{}{bulk, mds},
+		Md:          md,
+		mi:          mi,
+		InsideTx:    f.insideTx,
+		TxStartTime: f.txStartTime,
+		f: func(c context.Context) []interface{} {
+			res, err := f.ormer.InsertMultiResultWithCtx(c, bulk, mds)
+			return []interface{}{res, err}
+		},
+	}
+	res := f.root(ctx, inv)
+	results, _ := res[0].([]sql.Result)
+	return results, f.convertError(res[1])
+}
+
+// get the first element of the models and its model info
+func firstMdOf(mds interface{}) (interface{}, *modelInfo) {
+	sind := reflect.Indirect(reflect.ValueOf(mds))
+	if (sind.Kind() == reflect.Array || sind.Kind() == reflect.Slice) && sind.Len() > 0 {
+		md := reflect.Indirect(sind.Index(0)).Interface()
+		mi, _ := modelCache.getByMd(md)
+		return md, mi
+	}
+	return nil, nil
+}
+
 func (f *filterOrmDecorator) Update(md interface{}, cols ...string) (int64, error) {
-	return f.UpdateWithCtx(context.Background(), md, cols...)
+	return f.UpdateWithCtx(f.defaultCtx(), md, cols...)
 }
 
 func (f *filterOrmDecorator) UpdateWithCtx(ctx context.Context, md interface{}, cols ...string) (int64, error) {
@@ -353,7 +553,7 @@ func (f *filterOrmDecorator) UpdateWithCtx(ctx context.Context, md interface{},
 }
 
 func (f *filterOrmDecorator) Delete(md interface{}, cols ...string) (int64, error) {
-	return f.DeleteWithCtx(context.Background(), md, cols...)
+	return f.DeleteWithCtx(f.defaultCtx(), md, cols...)
 }
 
 func (f *filterOrmDecorator) DeleteWithCtx(ctx context.Context, md interface{}, cols ...string) (int64, error) {
@@ -374,8 +574,30 @@ func (f *filterOrmDecorator) DeleteWithCtx(ctx context.Context, md interface{},
 	return res[0].(int64), f.convertError(res[1])
 }
 
+func (f *filterOrmDecorator) DeleteWhere(md interface{}, cond *Condition) (int64, error) {
+	return f.DeleteWhereWithCtx(f.defaultCtx(), md, cond)
+}
+
+func (f *filterOrmDecorator) DeleteWhereWithCtx(ctx context.Context, md interface{}, cond *Condition) (int64, error) {
+	mi, _ := modelCache.getByMd(md)
+	inv := &Invocation{
+		Method:      "DeleteWhereWithCtx",
+		Args:        []interface{}{md, cond},
+		Md:          md,
+		mi:          mi,
+		InsideTx:    f.insideTx,
+		TxStartTime: f.txStartTime,
+		f: func(c context.Context) []interface{} {
+			res, err := f.ormer.DeleteWhereWithCtx(c, md, cond)
+			return []interface{}{res, err}
+		},
+	}
+	res := f.root(ctx, inv)
+	return res[0].(int64), f.convertError(res[1])
+}
+
 func (f *filterOrmDecorator) Raw(query string, args ...interface{}) RawSeter {
-	return f.RawWithCtx(context.Background(), query, args...)
+	return f.RawWithCtx(f.defaultCtx(), query, args...)
 }
 
 func (f *filterOrmDecorator) RawWithCtx(ctx context.Context, query string, args ...interface{}) RawSeter {
@@ -407,15 +629,156 @@ func (f *filterOrmDecorator) Driver() Driver {
 			return []interface{}{res}
 		},
 	}
-	res := f.root(context.Background(), inv)
+	res := f.root(f.defaultCtx(), inv)
 	if res[0] == nil {
 		return nil
 	}
 	return res[0].(Driver)
 }
 
+func (f *filterOrmDecorator) DryRun() Ormer {
+	inv := &Invocation{
+		Method:      "DryRun",
+		InsideTx:    f.insideTx,
+		TxStartTime: f.txStartTime,
+		f: func(c context.Context) []interface{} {
+			res := f.TxBeginner.(Ormer).DryRun()
+			return []interface{}{res}
+		},
+	}
+	res := f.root(f.defaultCtx(), inv)
+	if res[0] == nil {
+		return nil
+	}
+	delegate := res[0].(Ormer)
+	return &filterOrmDecorator{
+		ormer:       delegate,
+		TxBeginner:  delegate,
+		root:        f.root,
+		insideTx:    f.insideTx,
+		txStartTime: f.txStartTime,
+		txName:      f.txName,
+	}
+}
+
+func (f *filterOrmDecorator) WithSQLRecorder(recorder *SQLRecorder) Ormer {
+	inv := &Invocation{
+		Method:      "WithSQLRecorder",
+		Args:        []interface{}{recorder},
+		InsideTx:    f.insideTx,
+		TxStartTime: f.txStartTime,
+		f: func(c context.Context) []interface{} {
+			res := f.TxBeginner.(Ormer).WithSQLRecorder(recorder)
+			return []interface{}{res}
+		},
+	}
+	res := f.root(f.defaultCtx(), inv)
+	if res[0] == nil {
+		return nil
+	}
+	delegate := res[0].(Ormer)
+	return &filterOrmDecorator{
+		ormer:       delegate,
+		TxBeginner:  delegate,
+		root:        f.root,
+		insideTx:    f.insideTx,
+		txStartTime: f.txStartTime,
+		txName:      f.txName,
+	}
+}
+
+func (f *filterOrmDecorator) LastSQL() (string, []interface{}) {
+	inv := &Invocation{
+		Method:      "LastSQL",
+		InsideTx:    f.insideTx,
+		TxStartTime: f.txStartTime,
+		f: func(c context.Context) []interface{} {
+			query, args := f.TxBeginner.(Ormer).LastSQL()
+			return []interface{}{query, args}
+		},
+	}
+	res := f.root(f.defaultCtx(), inv)
+	return res[0].(string), res[1].([]interface{})
+}
+
+func (f *filterOrmDecorator) RawDB() *sql.DB {
+	inv := &Invocation{
+		Method:      "RawDB",
+		InsideTx:    f.insideTx,
+		TxStartTime: f.txStartTime,
+		f: func(c context.Context) []interface{} {
+			res := f.TxBeginner.(Ormer).RawDB()
+			return []interface{}{res}
+		},
+	}
+	res := f.root(f.defaultCtx(), inv)
+	db, _ := res[0].(*sql.DB)
+	return db
+}
+
+func (f *filterOrmDecorator) DB() *sql.Tx {
+	inv := &Invocation{
+		Method:      "DB",
+		InsideTx:    f.insideTx,
+		TxStartTime: f.txStartTime,
+		TxName:      f.txName,
+		f: func(c context.Context) []interface{} {
+			res := f.TxCommitter.(TxOrmer).DB()
+			return []interface{}{res}
+		},
+	}
+	res := f.root(f.defaultCtx(), inv)
+	tx, _ := res[0].(*sql.Tx)
+	return tx
+}
+
+func (f *filterOrmDecorator) Savepoint(name string) error {
+	return f.savepoint("Savepoint", name, f.TxCommitter.(TxOrmer).Savepoint)
+}
+
+func (f *filterOrmDecorator) RollbackTo(name string) error {
+	return f.savepoint("RollbackTo", name, f.TxCommitter.(TxOrmer).RollbackTo)
+}
+
+func (f *filterOrmDecorator) ReleaseSavepoint(name string) error {
+	return f.savepoint("ReleaseSavepoint", name, f.TxCommitter.(TxOrmer).ReleaseSavepoint)
+}
+
+func (f *filterOrmDecorator) ExecBatch(stmts []RawStmt) ([]sql.Result, error) {
+	inv := &Invocation{
+		Method:      "ExecBatch",
+		Args:        []interface{}{stmts},
+		InsideTx:    f.insideTx,
+		TxStartTime: f.txStartTime,
+		TxName:      f.txName,
+		f: func(c context.Context) []interface{} {
+			results, err := f.TxCommitter.(TxOrmer).ExecBatch(stmts)
+			return []interface{}{results, err}
+		},
+	}
+	res := f.root(f.defaultCtx(), inv)
+	results, _ := res[0].([]sql.Result)
+	return results, f.convertError(res[1])
+}
+
+func (f *filterOrmDecorator) savepoint(method string, name string, fn func(string) error) error {
+	inv := &Invocation{
+		Method:      method,
+		Args:        []interface{}{name},
+		InsideTx:    f.insideTx,
+		TxStartTime: f.txStartTime,
+		TxName:      f.txName,
+		f: func(c context.Context) []interface{} {
+			err := fn(name)
+			return []interface{}{err}
+		},
+	}
+	res := f.root(f.defaultCtx(), inv)
+	return f.convertError(res[0])
+}
+
 func (f *filterOrmDecorator) Begin() (TxOrmer, error) {
-	return f.BeginWithCtxAndOpts(context.Background(), nil)
+	return f.BeginWithCtxAndOpts(f.defaultCtx(), nil)
 }
 
 func (f *filterOrmDecorator) BeginWithCtx(ctx context.Context) (TxOrmer, error) {
@@ -423,7 +786,7 @@ func (f *filterOrmDecorator) BeginWithCtx(ctx context.Context) (TxOrmer, error)
 }
 
 func (f *filterOrmDecorator) BeginWithOpts(opts *sql.TxOptions) (TxOrmer, error) {
-	return f.BeginWithCtxAndOpts(context.Background(), opts)
+	return f.BeginWithCtxAndOpts(f.defaultCtx(), opts)
 }
 
 func (f *filterOrmDecorator) BeginWithCtxAndOpts(ctx context.Context, opts *sql.TxOptions) (TxOrmer, error) {
@@ -442,8 +805,20 @@ func (f *filterOrmDecorator) BeginWithCtxAndOpts(ctx context.Context, opts *sql.
 	return res[0].(TxOrmer), f.convertError(res[1])
 }
 
+func (f *filterOrmDecorator) BeginReadCommitted(ctx context.Context) (TxOrmer, error) {
+	return f.BeginWithCtxAndOpts(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+}
+
+func (f *filterOrmDecorator) BeginRepeatableRead(ctx context.Context) (TxOrmer, error) {
+	return f.BeginWithCtxAndOpts(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+}
+
+func (f *filterOrmDecorator) BeginSerializable(ctx context.Context) (TxOrmer, error) {
+	return f.BeginWithCtxAndOpts(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+}
+
 func (f *filterOrmDecorator) DoTx(task func(ctx context.Context, txOrm TxOrmer) error) error {
-	return f.DoTxWithCtxAndOpts(context.Background(), nil, task)
+	return f.DoTxWithCtxAndOpts(f.defaultCtx(), nil, task)
 }
 
 func (f *filterOrmDecorator) DoTxWithCtx(ctx context.Context, task func(ctx context.Context, txOrm TxOrmer) error) error {
@@ -451,7 +826,7 @@ func (f *filterOrmDecorator) DoTxWithCtx(ctx context.Context, task func(ctx cont
 }
 
 func (f *filterOrmDecorator) DoTxWithOpts(opts *sql.TxOptions, task func(ctx context.Context, txOrm TxOrmer) error) error {
-	return f.DoTxWithCtxAndOpts(context.Background(), opts, task)
+	return f.DoTxWithCtxAndOpts(f.defaultCtx(), opts, task)
 }
 
 func (f *filterOrmDecorator) DoTxWithCtxAndOpts(ctx context.Context, opts *sql.TxOptions, task func(ctx context.Context, txOrm TxOrmer) error) error {
@@ -482,7 +857,7 @@ func (f *filterOrmDecorator) Commit() error {
 			return []interface{}{err}
 		},
 	}
-	res := f.root(context.Background(), inv)
+	res := f.root(f.defaultCtx(), inv)
 	return f.convertError(res[0])
 }
 
@@ -498,7 +873,7 @@ func (f *filterOrmDecorator) Rollback() error {
 			return []interface{}{err}
 		},
 	}
-	res := f.root(context.Background(), inv)
+	res := f.root(f.defaultCtx(), inv)
 	return f.convertError(res[0])
 }
 
@@ -514,7 +889,7 @@ func (f *filterOrmDecorator) RollbackUnlessCommit() error {
 			return []interface{}{err}
 		},
 	}
-	res := f.root(context.Background(), inv)
+	res := f.root(f.defaultCtx(), inv)
 	return f.convertError(res[0])
 }
 
@@ -17,9 +17,11 @@ package orm
 import (
 	"context"
 	"database/sql"
+	sqldriver "database/sql/driver"
 	"errors"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -45,6 +47,24 @@ func TestFilterOrmDecoratorRead(t *testing.T) {
 	assert.Equal(t, "read error", err.Error())
 }
 
+func TestFilterOrmDecoratorReadInLocation(t *testing.T) {
+	register()
+
+	o := &filterMockOrm{}
+	od := NewFilterOrmDecorator(o, func(next Filter) Filter {
+		return func(ctx context.Context, inv *Invocation) []interface{} {
+			assert.Equal(t, "ReadInLocationWithCtx", inv.Method)
+			assert.Equal(t, 3, len(inv.Args))
+			assert.Equal(t, time.UTC, inv.Args[1])
+			assert.Equal(t, "FILTER_TEST", inv.GetTableName())
+			return next(ctx, inv)
+		}
+	})
+
+	err := od.ReadInLocation(&FilterTestEntity{}, time.UTC)
+	assert.Nil(t, err)
+}
+
 func TestFilterOrmDecoratorBeginTx(t *testing.T) {
 	register()
 
@@ -78,6 +98,9 @@ func TestFilterOrmDecoratorBeginTx(t *testing.T) {
 	to, err = od.BeginWithOpts(nil)
 	assert.True(t, validateBeginResult(t, to, err))
 
+	to, err = od.BeginSerializable(context.Background())
+	assert.True(t, validateBeginResult(t, to, err))
+
 	ctx := context.WithValue(context.Background(), TxNameKey, "Commit_tx")
 	to, err = od.BeginWithCtx(ctx)
 	assert.True(t, validateBeginResult(t, to, err))
@@ -110,6 +133,71 @@ func TestFilterOrmDecoratorDBStats(t *testing.T) {
 	assert.Equal(t, -1, res.MaxOpenConnections)
 }
 
+func TestFilterOrmDecoratorRawDB(t *testing.T) {
+	o := &filterMockOrm{}
+	var methods []string
+	od := NewFilterOrmDecorator(o, func(next Filter) Filter {
+		return func(ctx context.Context, inv *Invocation) []interface{} {
+			methods = append(methods, inv.Method)
+			return next(ctx, inv)
+		}
+	})
+	assert.NotNil(t, od.RawDB())
+
+	to := NewFilterTxOrmDecorator(o, od.(*filterOrmDecorator).root, "raw_tx")
+	assert.NotNil(t, to.DB())
+	assert.Equal(t, []string{"RawDB", "DB"}, methods)
+}
+
+func TestFilterOrmDecoratorSavepoint(t *testing.T) {
+	o := &filterMockOrm{}
+	var methods []string
+	od := NewFilterOrmDecorator(o, func(next Filter) Filter {
+		return func(ctx context.Context, inv *Invocation) []interface{} {
+			methods = append(methods, inv.Method)
+			assert.Equal(t, "sp_tx", inv.TxName)
+			assert.Equal(t, []interface{}{"sp"}, inv.Args)
+			return next(ctx, inv)
+		}
+	})
+	to := NewFilterTxOrmDecorator(o, od.(*filterOrmDecorator).root, "sp_tx")
+	assert.Equal(t, "savepoint sp", to.Savepoint("sp").Error())
+	assert.Equal(t, "rollback to sp", to.RollbackTo("sp").Error())
+	assert.Equal(t, "release savepoint sp", to.ReleaseSavepoint("sp").Error())
+	assert.Equal(t, []string{"Savepoint", "RollbackTo", "ReleaseSavepoint"}, methods)
+}
+
+func TestFilterOrmDecoratorExecBatch(t *testing.T) {
+	o := &filterMockOrm{}
+	stmts := []RawStmt{{SQL: "DELETE FROM user"}, {SQL: "DELETE FROM post WHERE id = ?", Args: []interface{}{1}}}
+	od := NewFilterOrmDecorator(o, func(next Filter) Filter {
+		return func(ctx context.Context, inv *Invocation) []interface{} {
+			assert.Equal(t, "ExecBatch", inv.Method)
+			assert.Equal(t, "batch_tx", inv.TxName)
+			assert.Equal(t, []interface{}{stmts}, inv.Args)
+			return next(ctx, inv)
+		}
+	})
+	to := NewFilterTxOrmDecorator(o, od.(*filterOrmDecorator).root, "batch_tx")
+	res, err := to.ExecBatch(stmts)
+	assert.Nil(t, res)
+	assert.Equal(t, "exec batch", err.Error())
+}
+
+func TestFilterOrmDecoratorPing(t *testing.T) {
+	o := &filterMockOrm{}
+	od := NewFilterOrmDecorator(o, func(next Filter) Filter {
+		return func(ctx context.Context, inv *Invocation) []interface{} {
+			assert.Equal(t, "Ping", inv.Method)
+			assert.Equal(t, 0, len(inv.Args))
+			return next(ctx, inv)
+		}
+	})
+	err := od.Ping(context.Background())
+	assert.NotNil(t, err)
+	assert.Equal(t, "ping error", err.Error())
+}
+
 func TestFilterOrmDecoratorDelete(t *testing.T) {
 	register()
 	o := &filterMockOrm{}
@@ -127,6 +215,23 @@ func TestFilterOrmDecoratorDelete(t *testing.T) {
 	assert.Equal(t, int64(-2), res)
 }
 
+func TestFilterOrmDecoratorDeleteWhere(t *testing.T) {
+	register()
+	o := &filterMockOrm{}
+	od := NewFilterOrmDecorator(o, func(next Filter) Filter {
+		return func(ctx context.Context, inv *Invocation) []interface{} {
+			assert.Equal(t, "DeleteWhereWithCtx", inv.Method)
+			assert.Equal(t, 2, len(inv.Args))
+			assert.Equal(t, "FILTER_TEST", inv.GetTableName())
+			return next(ctx, inv)
+		}
+	})
+	res, err := od.DeleteWhere(&FilterTestEntity{}, NewCondition().And("name", "a"))
+	assert.NotNil(t, err)
+	assert.Equal(t, "delete where error", err.Error())
+	assert.Equal(t, int64(-3), res)
+}
+
 func TestFilterOrmDecoratorDoTx(t *testing.T) {
 	o := &filterMockOrm{}
 	od := NewFilterOrmDecorator(o, func(next Filter) Filter {
@@ -174,6 +279,17 @@ func TestFilterOrmDecoratorDoTx(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestFilterTxOrmDecoratorContext(t *testing.T) {
+	register()
+	ctx := context.WithValue(context.Background(), TxNameKey, "ctx_tx")
+	to := NewFilterTxOrmDecorator(&txOrm{ormBase: ormBase{ctx: ctx}}, func(c context.Context, inv *Invocation) []interface{} {
+		assert.Equal(t, "ReadWithCtx", inv.Method)
+		assert.Equal(t, "ctx_tx", c.Value(TxNameKey))
+		return []interface{}{nil}
+	}, "ctx_tx")
+	assert.Nil(t, to.Read(&FilterTestEntity{}))
+}
+
 func TestFilterOrmDecoratorDriver(t *testing.T) {
 	o := &filterMockOrm{}
 	od := NewFilterOrmDecorator(o, func(next Filter) Filter {
@@ -189,6 +305,64 @@ func TestFilterOrmDecoratorDriver(t *testing.T) {
 	assert.Nil(t, res)
 }
 
+func TestFilterOrmDecoratorDryRun(t *testing.T) {
+	o := &filterMockOrm{}
+	methods := make([]string, 0, 2)
+	od := NewFilterOrmDecorator(o, func(next Filter) Filter {
+		return func(ctx context.Context, inv *Invocation) []interface{} {
+			methods = append(methods, inv.Method)
+			assert.Equal(t, 0, len(inv.Args))
+			assert.False(t, inv.InsideTx)
+			return next(ctx, inv)
+		}
+	})
+	dry := od.DryRun()
+	assert.NotNil(t, dry)
+	query, args := dry.LastSQL()
+	assert.Equal(t, "", query)
+	assert.Nil(t, args)
+	assert.Equal(t, []string{"DryRun", "LastSQL"}, methods)
+
+	// the dry run inside transaction keeps the state of transaction
+	start := time.Now()
+	od = &filterOrmDecorator{ormer: o, TxBeginner: o, insideTx: true, txStartTime: start, txName: "dry_tx",
+		root: func(ctx context.Context, inv *Invocation) []interface{} {
+			assert.True(t, inv.InsideTx)
+			assert.Equal(t, start, inv.TxStartTime)
+			return inv.execute(ctx)
+		}}
+	dry = od.DryRun()
+	assert.Equal(t, "dry_tx", dry.(*filterOrmDecorator).txName)
+	dry.LastSQL()
+}
+
+func TestFilterOrmDecoratorWithSQLRecorder(t *testing.T) {
+	o := &filterMockOrm{}
+	recorder := NewSQLRecorder()
+	od := NewFilterOrmDecorator(o, func(next Filter) Filter {
+		return func(ctx context.Context, inv *Invocation) []interface{} {
+			assert.Equal(t, "WithSQLRecorder", inv.Method)
+			assert.Equal(t, []interface{}{recorder}, inv.Args)
+			assert.False(t, inv.InsideTx)
+			return next(ctx, inv)
+		}
+	})
+	assert.NotNil(t, od.WithSQLRecorder(recorder))
+
+	// the recorder inside transaction keeps the state of transaction
+	start := time.Now()
+	od = &filterOrmDecorator{ormer: o, TxBeginner: o, insideTx: true, txStartTime: start, txName: "recorder_tx",
+		root: func(ctx context.Context, inv *Invocation) []interface{} {
+			assert.True(t, inv.InsideTx)
+			assert.Equal(t, start, inv.TxStartTime)
+			return inv.execute(ctx)
+		}}
+	rod := od.WithSQLRecorder(recorder).(*filterOrmDecorator)
+	assert.True(t, rod.insideTx)
+	assert.Equal(t, start, rod.txStartTime)
+	assert.Equal(t, "recorder_tx", rod.txName)
+}
+
 func TestFilterOrmDecoratorInsert(t *testing.T) {
 	register()
 	o := &filterMockOrm{}
@@ -228,6 +402,25 @@ func TestFilterOrmDecoratorInsertMulti(t *testing.T) {
 	assert.Equal(t, int64(2), i)
 }
 
+func TestFilterOrmDecoratorInsertMultiResult(t *testing.T) {
+	register()
+	o := &filterMockOrm{}
+	od := NewFilterOrmDecorator(o, func(next Filter) Filter {
+		return func(ctx context.Context, inv *Invocation) []interface{} {
+			assert.Equal(t, "InsertMultiResultWithCtx", inv.Method)
+			assert.Equal(t, 2, len(inv.Args))
+			assert.Equal(t, "FILTER_TEST", inv.GetTableName())
+			assert.False(t, inv.InsideTx)
+			return next(ctx, inv)
+		}
+	})
+
+	bulk := []*FilterTestEntity{{}, {}}
+	results, err := od.InsertMultiResult(2, bulk)
+	assert.Equal(t, "insert multi result error", err.Error())
+	assert.Equal(t, 1, len(results))
+}
+
 func TestFilterOrmDecoratorInsertOrUpdate(t *testing.T) {
 	register()
 	o := &filterMockOrm{}
@@ -246,6 +439,40 @@ func TestFilterOrmDecoratorInsertOrUpdate(t *testing.T) {
 	assert.Equal(t, int64(1), i)
 }
 
+func TestFilterOrmDecoratorInsertOrIgnore(t *testing.T) {
+	register()
+	o := &filterMockOrm{}
+	od := NewFilterOrmDecorator(o, func(next Filter) Filter {
+		return func(ctx context.Context, inv *Invocation) []interface{} {
+			assert.Equal(t, "InsertOrIgnoreWithCtx", inv.Method)
+			assert.Equal(t, 2, len(inv.Args))
+			assert.Equal(t, "FILTER_TEST", inv.GetTableName())
+			assert.False(t, inv.InsideTx)
+			return next(ctx, inv)
+		}
+	})
+	i, err := od.InsertOrIgnore(&FilterTestEntity{}, "Name")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), i)
+}
+
+func TestFilterOrmDecoratorInsertWithPK(t *testing.T) {
+	register()
+	o := &filterMockOrm{}
+	od := NewFilterOrmDecorator(o, func(next Filter) Filter {
+		return func(ctx context.Context, inv *Invocation) []interface{} {
+			assert.Equal(t, "InsertWithPKWithCtx", inv.Method)
+			assert.Equal(t, 1, len(inv.Args))
+			assert.Equal(t, "FILTER_TEST", inv.GetTableName())
+			assert.False(t, inv.InsideTx)
+			return next(ctx, inv)
+		}
+	})
+	i, err := od.InsertWithPK(&FilterTestEntity{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), i)
+}
+
 func TestFilterOrmDecoratorLoadRelated(t *testing.T) {
 	o := &filterMockOrm{}
 	od := NewFilterOrmDecorator(o, func(next Filter) Filter {
@@ -263,6 +490,23 @@ func TestFilterOrmDecoratorLoadRelated(t *testing.T) {
 	assert.Equal(t, int64(99), i)
 }
 
+func TestFilterOrmDecoratorLoadRelatedBatch(t *testing.T) {
+	o := &filterMockOrm{}
+	od := NewFilterOrmDecorator(o, func(next Filter) Filter {
+		return func(ctx context.Context, inv *Invocation) []interface{} {
+			assert.Equal(t, "LoadRelatedBatchWithCtx", inv.Method)
+			assert.Equal(t, 3, len(inv.Args))
+			assert.Equal(t, "FILTER_TEST", inv.GetTableName())
+			assert.False(t, inv.InsideTx)
+			return next(ctx, inv)
+		}
+	})
+	i, err := od.LoadRelatedBatch([]*FilterTestEntity{{}}, "hello")
+	assert.NotNil(t, err)
+	assert.Equal(t, "load related batch error", err.Error())
+	assert.Equal(t, int64(99), i)
+}
+
 func TestFilterOrmDecoratorQueryM2M(t *testing.T) {
 	o := &filterMockOrm{}
 	od := NewFilterOrmDecorator(o, func(next Filter) Filter {
@@ -294,6 +538,23 @@ func TestFilterOrmDecoratorQueryTable(t *testing.T) {
 	assert.Nil(t, res)
 }
 
+func TestFilterOrmDecoratorQueryTableE(t *testing.T) {
+	register()
+	o := &filterMockOrm{}
+	od := NewFilterOrmDecorator(o, func(next Filter) Filter {
+		return func(ctx context.Context, inv *Invocation) []interface{} {
+			assert.Equal(t, "QueryTableE", inv.Method)
+			assert.Equal(t, 1, len(inv.Args))
+			assert.Equal(t, "FILTER_TEST", inv.GetTableName())
+			assert.False(t, inv.InsideTx)
+			return next(ctx, inv)
+		}
+	})
+	res, err := od.QueryTableE(&FilterTestEntity{})
+	assert.Nil(t, res)
+	assert.Nil(t, err)
+}
+
 func TestFilterOrmDecoratorRaw(t *testing.T) {
 	register()
 	o := &filterMockOrm{}
@@ -346,6 +607,24 @@ func TestFilterOrmDecoratorReadOrCreate(t *testing.T) {
 	assert.Equal(t, int64(13), i)
 }
 
+func TestFilterOrmDecoratorReadOrCreateMulti(t *testing.T) {
+	register()
+	o := &filterMockOrm{}
+	od := NewFilterOrmDecorator(o, func(next Filter) Filter {
+		return func(ctx context.Context, inv *Invocation) []interface{} {
+			assert.Equal(t, "ReadOrCreateMultiWithCtx", inv.Method)
+			assert.Equal(t, 2, len(inv.Args))
+			assert.Equal(t, "FILTER_TEST", inv.GetTableName())
+			assert.False(t, inv.InsideTx)
+			return next(ctx, inv)
+		}
+	})
+	created, err := od.ReadOrCreateMulti([]*FilterTestEntity{{}, {}}, []string{"name"})
+	assert.NotNil(t, err)
+	assert.Equal(t, "read or create multi error", err.Error())
+	assert.Equal(t, []bool{true, false}, created)
+}
+
 var _ Ormer = new(filterMockOrm)
 
 // filterMockOrm is only used in this test file
@@ -357,6 +636,10 @@ func (f *filterMockOrm) ReadOrCreateWithCtx(ctx context.Context, md interface{},
 	return true, 13, errors.New("read or create error")
 }
 
+func (f *filterMockOrm) ReadOrCreateMultiWithCtx(ctx context.Context, mds interface{}, lookupCols []string) ([]bool, error) {
+	return []bool{true, false}, errors.New("read or create multi error")
+}
+
 func (f *filterMockOrm) ReadForUpdateWithCtx(ctx context.Context, md interface{}, cols ...string) error {
 	return errors.New("read for update error")
 }
@@ -365,6 +648,10 @@ func (f *filterMockOrm) LoadRelatedWithCtx(ctx context.Context, md interface{},
 	return 99, errors.New("load related error")
 }
 
+func (f *filterMockOrm) LoadRelatedBatchWithCtx(ctx context.Context, mds interface{}, name string, args ...utils.KV) (int64, error) {
+	return 99, errors.New("load related batch error")
+}
+
 func (f *filterMockOrm) InsertOrUpdateWithCtx(ctx context.Context, md interface{}, colConflitAndArgs ...string) (int64, error) {
 	return 1, errors.New("insert or update error")
 }
@@ -373,6 +660,10 @@ func (f *filterMockOrm) InsertMultiWithCtx(ctx context.Context, bulk int, mds in
 	return 2, errors.New("insert multi error")
 }
 
+func (f *filterMockOrm) InsertMultiResultWithCtx(ctx context.Context, bulk int, mds interface{}) ([]sql.Result, error) {
+	return []sql.Result{sqldriver.RowsAffected(2)}, errors.New("insert multi result error")
+}
+
 func (f *filterMockOrm) InsertWithCtx(ctx context.Context, md interface{}) (int64, error) {
 	return 100, errors.New("insert error")
 }
@@ -385,6 +676,10 @@ func (f *filterMockOrm) DeleteWithCtx(ctx context.Context, md interface{}, cols
 	return -2, errors.New("delete error")
 }
 
+func (f *filterMockOrm) DeleteWhereWithCtx(ctx context.Context, md interface{}, cond *Condition) (int64, error) {
+	return -3, errors.New("delete where error")
+}
+
 func (f *filterMockOrm) BeginWithCtxAndOpts(ctx context.Context, opts *sql.TxOptions) (TxOrmer, error) {
 	return &filterMockOrm{}, errors.New("begin tx")
 }
@@ -405,12 +700,40 @@ func (f *filterMockOrm) RollbackUnlessCommit() error {
 	return errors.New("rollback unless commit")
 }
 
+func (f *filterMockOrm) DB() *sql.Tx {
+	return &sql.Tx{}
+}
+
+func (f *filterMockOrm) Savepoint(name string) error {
+	return errors.New("savepoint " + name)
+}
+
+func (f *filterMockOrm) RollbackTo(name string) error {
+	return errors.New("rollback to " + name)
+}
+
+func (f *filterMockOrm) ReleaseSavepoint(name string) error {
+	return errors.New("release savepoint " + name)
+}
+
+func (f *filterMockOrm) ExecBatch(stmts []RawStmt) ([]sql.Result, error) {
+	return nil, errors.New("exec batch")
+}
+
+func (f *filterMockOrm) RawDB() *sql.DB {
+	return &sql.DB{}
+}
+
 func (f *filterMockOrm) DBStats() *sql.DBStats {
 	return &sql.DBStats{
 		MaxOpenConnections: -1,
 	}
 }
 
+func (f *filterMockOrm) Ping(ctx context.Context) error {
+	return errors.New("ping error")
+}
+
 func validateBeginResult(t *testing.T, to TxOrmer, err error) bool {
 	assert.NotNil(t, err)
 	assert.Equal(t, "begin tx", err.Error())
@@ -61,3 +61,13 @@ func (s *SimpleCondition) Match(ctx context.Context, inv *orm.Invocation) bool {
 	}
 	return res
 }
+
+// the condition which matches the method too
+type methodCondition struct {
+	Condition
+	method string
+}
+
+func (m *methodCondition) Match(ctx context.Context, inv *orm.Invocation) bool {
+	return m.method == inv.Method && m.Condition.Match(ctx, inv)
+}
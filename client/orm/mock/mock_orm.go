@@ -55,6 +55,16 @@ func MockRead(tableName string, cb func(data interface{}), err error) *Mock {
 	})
 }
 
+// MockReadInLocation support orm.ReadInLocation and orm.ReadInLocationWithCtx
+// cb is used to mock read data from DB
+func MockReadInLocation(tableName string, cb func(data interface{}), err error) *Mock {
+	return NewMock(NewSimpleCondition(tableName, "ReadInLocationWithCtx"), []interface{}{err}, func(inv *orm.Invocation) {
+		if cb != nil {
+			cb(inv.Args[0])
+		}
+	})
+}
+
 // MockReadForUpdateWithCtx support ReadForUpdate and ReadForUpdateWithCtx
 // cb is used to mock read data from DB
 func MockReadForUpdateWithCtx(tableName string, cb func(data interface{}), err error) *Mock {
@@ -77,6 +87,11 @@ func MockReadOrCreateWithCtx(tableName string,
 		})
 }
 
+// MockReadOrCreateMulti support ReadOrCreateMulti and ReadOrCreateMultiWithCtx
+func MockReadOrCreateMulti(tableName string, created []bool, err error) *Mock {
+	return NewMock(NewSimpleCondition(tableName, "ReadOrCreateMultiWithCtx"), []interface{}{created, err}, nil)
+}
+
 // MockInsertWithCtx support Insert and InsertWithCtx
 func MockInsertWithCtx(tableName string, id int64, err error) *Mock {
 	return NewMock(NewSimpleCondition(tableName, "InsertWithCtx"), []interface{}{id, err}, nil)
@@ -87,6 +102,21 @@ func MockInsertMultiWithCtx(tableName string, cnt int64, err error) *Mock {
 	return NewMock(NewSimpleCondition(tableName, "InsertMultiWithCtx"), []interface{}{cnt, err}, nil)
 }
 
+// MockInsertMultiResult support InsertMultiResult and InsertMultiResultWithCtx
+func MockInsertMultiResult(tableName string, results []sql.Result, err error) *Mock {
+	return NewMock(NewSimpleCondition(tableName, "InsertMultiResultWithCtx"), []interface{}{results, err}, nil)
+}
+
+// MockInsertOrIgnore support InsertOrIgnore and InsertOrIgnoreWithCtx
+func MockInsertOrIgnore(tableName string, affected int64, err error) *Mock {
+	return NewMock(NewSimpleCondition(tableName, "InsertOrIgnoreWithCtx"), []interface{}{affected, err}, nil)
+}
+
+// MockInsertWithPK support InsertWithPK and InsertWithPKWithCtx
+func MockInsertWithPK(tableName string, affected int64, err error) *Mock {
+	return NewMock(NewSimpleCondition(tableName, "InsertWithPKWithCtx"), []interface{}{affected, err}, nil)
+}
+
 // MockInsertOrUpdateWithCtx support InsertOrUpdate and InsertOrUpdateWithCtx
 func MockInsertOrUpdateWithCtx(tableName string, id int64, err error) *Mock {
 	return NewMock(NewSimpleCondition(tableName, "InsertOrUpdateWithCtx"), []interface{}{id, err}, nil)
@@ -102,6 +132,11 @@ func MockDeleteWithCtx(tableName string, affectedRow int64, err error) *Mock {
 	return NewMock(NewSimpleCondition(tableName, "DeleteWithCtx"), []interface{}{affectedRow, err}, nil)
 }
 
+// MockDeleteWhere support DeleteWhere and DeleteWhereWithCtx
+func MockDeleteWhere(tableName string, affectedRow int64, err error) *Mock {
+	return NewMock(NewSimpleCondition(tableName, "DeleteWhereWithCtx"), []interface{}{affectedRow, err}, nil)
+}
+
 // MockQueryM2MWithCtx support QueryM2MWithCtx and QueryM2M
 // Now you may be need to use golang/mock to generate QueryM2M mock instance
 // Or use DoNothingQueryM2Mer
@@ -118,7 +153,14 @@ func MockQueryM2MWithCtx(tableName string, name string, res orm.QueryM2Mer) *Moc
 
 // MockLoadRelatedWithCtx support LoadRelatedWithCtx and LoadRelated
 func MockLoadRelatedWithCtx(tableName string, name string, rows int64, err error) *Mock {
-	return NewMock(NewQueryM2MerCondition(tableName, name), []interface{}{rows, err}, nil)
+	return NewMock(&methodCondition{Condition: NewQueryM2MerCondition(tableName, name), method: "LoadRelatedWithCtx"},
+		[]interface{}{rows, err}, nil)
+}
+
+// MockLoadRelatedBatchWithCtx support LoadRelatedBatchWithCtx and LoadRelatedBatch
+func MockLoadRelatedBatchWithCtx(tableName string, name string, rows int64, err error) *Mock {
+	return NewMock(&methodCondition{Condition: NewQueryM2MerCondition(tableName, name), method: "LoadRelatedBatchWithCtx"},
+		[]interface{}{rows, err}, nil)
 }
 
 // MockQueryTableWithCtx support QueryTableWithCtx and QueryTable
@@ -126,6 +168,11 @@ func MockQueryTableWithCtx(tableName string, qs orm.QuerySeter) *Mock {
 	return NewMock(NewSimpleCondition(tableName, "QueryTable"), []interface{}{qs}, nil)
 }
 
+// MockQueryTableE support QueryTableE
+func MockQueryTableE(tableName string, qs orm.QuerySeter, err error) *Mock {
+	return NewMock(NewSimpleCondition(tableName, "QueryTableE"), []interface{}{qs, err}, nil)
+}
+
 // MockRawWithCtx support RawWithCtx and Raw
 func MockRawWithCtx(rs orm.RawSeter) *Mock {
 	return NewMock(NewSimpleCondition("", "RawWithCtx"), []interface{}{rs}, nil)
@@ -141,6 +188,16 @@ func MockDBStats(stats *sql.DBStats) *Mock {
 	return NewMock(NewSimpleCondition("", "DBStats"), []interface{}{stats}, nil)
 }
 
+// MockRawDB support RawDB
+func MockRawDB(db *sql.DB) *Mock {
+	return NewMock(NewSimpleCondition("", "RawDB"), []interface{}{db}, nil)
+}
+
+// MockPing support Ping
+func MockPing(err error) *Mock {
+	return NewMock(NewSimpleCondition("", "Ping"), []interface{}{err}, nil)
+}
+
 // MockBeginWithCtxAndOpts support Begin, BeginWithCtx, BeginWithOpts, BeginWithCtxAndOpts
 // func MockBeginWithCtxAndOpts(txOrm *orm.TxOrmer, err error) *Mock {
 // 	return NewMock(NewSimpleCondition("", "BeginWithCtxAndOpts"), []interface{}{txOrm, err})
@@ -17,8 +17,10 @@ package mock
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -44,6 +46,29 @@ func TestMockDBStats(t *testing.T) {
 	assert.Equal(t, stats, res)
 }
 
+func TestMockRawDB(t *testing.T) {
+	s := StartMock()
+	defer s.Clear()
+	db := &sql.DB{}
+	s.Mock(MockRawDB(db))
+
+	o := orm.NewOrm()
+	assert.Equal(t, db, o.RawDB())
+}
+
+func TestMockPing(t *testing.T) {
+	s := StartMock()
+	defer s.Clear()
+	mockErr := errors.New("ping error")
+	s.Mock(MockPing(mockErr))
+
+	o := orm.NewOrm()
+
+	err := o.Ping(context.Background())
+
+	assert.Equal(t, mockErr, err)
+}
+
 func TestMockDeleteWithCtx(t *testing.T) {
 	s := StartMock()
 	defer s.Clear()
@@ -54,6 +79,16 @@ func TestMockDeleteWithCtx(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestMockDeleteWhere(t *testing.T) {
+	s := StartMock()
+	defer s.Clear()
+	s.Mock(MockDeleteWhere((&User{}).TableName(), 3, nil))
+	o := orm.NewOrm()
+	rows, err := o.DeleteWhere(&User{}, orm.NewCondition().And("name", "Tom"))
+	assert.Equal(t, int64(3), rows)
+	assert.Nil(t, err)
+}
+
 func TestMockInsertOrUpdateWithCtx(t *testing.T) {
 	s := StartMock()
 	defer s.Clear()
@@ -64,6 +99,26 @@ func TestMockInsertOrUpdateWithCtx(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestMockInsertOrIgnore(t *testing.T) {
+	s := StartMock()
+	defer s.Clear()
+	s.Mock(MockInsertOrIgnore((&User{}).TableName(), 1, nil))
+	o := orm.NewOrm()
+	num, err := o.InsertOrIgnore(&User{}, "Name")
+	assert.Equal(t, int64(1), num)
+	assert.Nil(t, err)
+}
+
+func TestMockInsertWithPK(t *testing.T) {
+	s := StartMock()
+	defer s.Clear()
+	s.Mock(MockInsertWithPK((&User{}).TableName(), 1, nil))
+	o := orm.NewOrm()
+	num, err := o.InsertWithPK(&User{Id: 10})
+	assert.Equal(t, int64(1), num)
+	assert.Nil(t, err)
+}
+
 func TestMockRead(t *testing.T) {
 	s := StartMock()
 	defer s.Clear()
@@ -79,6 +134,21 @@ func TestMockRead(t *testing.T) {
 	assert.Equal(t, "Tom", u.Name)
 }
 
+func TestMockReadInLocation(t *testing.T) {
+	s := StartMock()
+	defer s.Clear()
+	err := errors.New(mockErrorMsg)
+	s.Mock(MockReadInLocation((&User{}).TableName(), func(data interface{}) {
+		u := data.(*User)
+		u.Name = "Tom"
+	}, err))
+	o := orm.NewOrm()
+	u := &User{}
+	e := o.ReadInLocation(u, time.UTC)
+	assert.Equal(t, err, e)
+	assert.Equal(t, "Tom", u.Name)
+}
+
 func TestMockQueryM2MWithCtx(t *testing.T) {
 	s := StartMock()
 	defer s.Clear()
@@ -99,6 +169,17 @@ func TestMockQueryTableWithCtx(t *testing.T) {
 	assert.Equal(t, mock, res)
 }
 
+func TestMockQueryTableE(t *testing.T) {
+	s := StartMock()
+	defer s.Clear()
+	mock := &DoNothingQuerySetter{}
+	s.Mock(MockQueryTableE((&User{}).TableName(), mock, nil))
+	o := orm.NewOrm()
+	res, err := o.QueryTableE(&User{})
+	assert.Nil(t, err)
+	assert.Equal(t, mock, res)
+}
+
 func TestMockTable(t *testing.T) {
 	s := StartMock()
 	defer s.Clear()
@@ -120,6 +201,17 @@ func TestMockInsertMultiWithCtx(t *testing.T) {
 	assert.Equal(t, mock, err)
 }
 
+func TestMockInsertMultiResult(t *testing.T) {
+	s := StartMock()
+	defer s.Clear()
+	results := []sql.Result{driver.RowsAffected(2)}
+	s.Mock(MockInsertMultiResult((&User{}).TableName(), results, nil))
+	o := orm.NewOrm()
+	res, err := o.InsertMultiResult(2, []interface{}{&User{}, &User{}})
+	assert.Equal(t, results, res)
+	assert.Nil(t, err)
+}
+
 func TestMockInsertWithCtx(t *testing.T) {
 	s := StartMock()
 	defer s.Clear()
@@ -153,6 +245,23 @@ func TestMockLoadRelatedWithCtx(t *testing.T) {
 	assert.Equal(t, mock, err)
 }
 
+func TestMockLoadRelatedBatchWithCtx(t *testing.T) {
+	s := StartMock()
+	defer s.Clear()
+	mock := errors.New(mockErrorMsg)
+	s.Mock(MockLoadRelatedBatchWithCtx((&User{}).TableName(), "T", 12, mock))
+	o := orm.NewOrm()
+	res, err := o.LoadRelatedBatch([]*User{{}}, "T")
+	assert.Equal(t, int64(12), res)
+	assert.Equal(t, mock, err)
+
+	// the mock of LoadRelatedBatch doesn't match LoadRelated
+	s.Mock(MockLoadRelatedWithCtx((&User{}).TableName(), "T", 3, nil))
+	res, err = o.LoadRelated(&User{}, "T")
+	assert.Equal(t, int64(3), res)
+	assert.Nil(t, err)
+}
+
 func TestMockMethod(t *testing.T) {
 	s := StartMock()
 	defer s.Clear()
@@ -205,6 +314,16 @@ func TestMockReadOrCreateWithCtx(t *testing.T) {
 	assert.Equal(t, "Tom", u.Name)
 }
 
+func TestMockReadOrCreateMulti(t *testing.T) {
+	s := StartMock()
+	defer s.Clear()
+	s.Mock(MockReadOrCreateMulti((&User{}).TableName(), []bool{true, false}, nil))
+	o := orm.NewOrm()
+	created, err := o.ReadOrCreateMulti([]*User{{}, {}}, []string{"Name"})
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{true, false}, created)
+}
+
 func TestTransactionClosure(t *testing.T) {
 	s := StartMock()
 	defer s.Clear()
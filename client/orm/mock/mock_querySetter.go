@@ -16,6 +16,7 @@ package mock
 
 import (
 	"context"
+	"time"
 
 	"github.com/beego/beego/v2/client/orm"
 	"github.com/beego/beego/v2/client/orm/clauses/order_clause"
@@ -57,10 +58,46 @@ func (d *DoNothingQuerySetter) OneWithCtx(ctx context.Context, container interfa
 	return nil
 }
 
+func (d *DoNothingQuerySetter) InLocation(loc *time.Location) orm.QuerySeter {
+	return d
+}
+
+func (d *DoNothingQuerySetter) Union(other orm.QuerySeter) orm.UnionSeter {
+	return nil
+}
+
+func (d *DoNothingQuerySetter) UnionAll(other orm.QuerySeter) orm.UnionSeter {
+	return nil
+}
+
+func (d *DoNothingQuerySetter) Explain(ctx context.Context) (string, error) {
+	return "", nil
+}
+
+func (d *DoNothingQuerySetter) ExplainAnalyze(ctx context.Context) (string, error) {
+	return "", nil
+}
+
+func (d *DoNothingQuerySetter) Paginate(container interface{}, col string, after interface{}, limit int) (interface{}, error) {
+	return nil, nil
+}
+
+func (d *DoNothingQuerySetter) PaginateWithCtx(ctx context.Context, container interface{}, col string, after interface{}, limit int) (interface{}, error) {
+	return nil, nil
+}
+
 func (d *DoNothingQuerySetter) ValuesWithCtx(ctx context.Context, results *[]orm.Params, exprs ...string) (int64, error) {
 	return 0, nil
 }
 
+func (d *DoNothingQuerySetter) ValuesOne(result *orm.Params, exprs ...string) error {
+	return nil
+}
+
+func (d *DoNothingQuerySetter) ValuesOneWithCtx(ctx context.Context, result *orm.Params, exprs ...string) error {
+	return nil
+}
+
 func (d *DoNothingQuerySetter) ValuesListWithCtx(ctx context.Context, results *[]orm.ParamsList, exprs ...string) (int64, error) {
 	return 0, nil
 }
@@ -77,7 +114,15 @@ func (d *DoNothingQuerySetter) Filter(s string, i ...interface{}) orm.QuerySeter
 	return d
 }
 
-func (d *DoNothingQuerySetter) FilterRaw(s string, s2 string) orm.QuerySeter {
+func (d *DoNothingQuerySetter) FilterRaw(s string, s2 string, args ...interface{}) orm.QuerySeter {
+	return d
+}
+
+func (d *DoNothingQuerySetter) FilterIsNull(field string) orm.QuerySeter {
+	return d
+}
+
+func (d *DoNothingQuerySetter) FilterIsNotNull(field string) orm.QuerySeter {
 	return d
 }
 
@@ -85,6 +130,62 @@ func (d *DoNothingQuerySetter) Exclude(s string, i ...interface{}) orm.QuerySete
 	return d
 }
 
+func (d *DoNothingQuerySetter) Having(expr string, args ...interface{}) orm.QuerySeter {
+	return d
+}
+
+func (d *DoNothingQuerySetter) Scan(container interface{}, exprs ...string) (int64, error) {
+	return 0, nil
+}
+
+func (d *DoNothingQuerySetter) ScanWithCtx(ctx context.Context, container interface{}, exprs ...string) (int64, error) {
+	return 0, nil
+}
+
+func (d *DoNothingQuerySetter) FilterOr(expr string, args ...interface{}) orm.QuerySeter {
+	return d
+}
+
+func (d *DoNothingQuerySetter) FilterOrCond(cond *orm.Condition) orm.QuerySeter {
+	return d
+}
+
+func (d *DoNothingQuerySetter) GroupConcat(col, alias, sep string) orm.QuerySeter {
+	return d
+}
+
+func (d *DoNothingQuerySetter) FilterFullText(cols []string, query string) orm.QuerySeter {
+	return d
+}
+
+func (d *DoNothingQuerySetter) NoStmtCache() orm.QuerySeter {
+	return d
+}
+
+func (d *DoNothingQuerySetter) Unlimited() orm.QuerySeter {
+	return d
+}
+
+func (d *DoNothingQuerySetter) Set(col string, value interface{}) orm.QuerySeter {
+	return d
+}
+
+func (d *DoNothingQuerySetter) FilterCond(cond *orm.Condition) orm.QuerySeter {
+	return d
+}
+
+func (d *DoNothingQuerySetter) ExcludeCond(cond *orm.Condition) orm.QuerySeter {
+	return d
+}
+
+func (d *DoNothingQuerySetter) SelectColumns(cols ...string) orm.QuerySeter {
+	return d
+}
+
+func (d *DoNothingQuerySetter) ExcludeExists(rel string, cond *orm.Condition) orm.QuerySeter {
+	return d
+}
+
 func (d *DoNothingQuerySetter) SetCond(condition *orm.Condition) orm.QuerySeter {
 	return d
 }
@@ -101,6 +202,18 @@ func (d *DoNothingQuerySetter) Offset(offset interface{}) orm.QuerySeter {
 	return d
 }
 
+func (d *DoNothingQuerySetter) OrderRandom() orm.QuerySeter {
+	return d
+}
+
+func (d *DoNothingQuerySetter) OrderByNulls(col string, desc bool, nulls orm.NullsFirstOrLast) orm.QuerySeter {
+	return d
+}
+
+func (d *DoNothingQuerySetter) SeekGt(col string, value interface{}) orm.QuerySeter {
+	return d
+}
+
 func (d *DoNothingQuerySetter) GroupBy(exprs ...string) orm.QuerySeter {
 	return d
 }
@@ -121,6 +234,14 @@ func (d *DoNothingQuerySetter) IgnoreIndex(indexes ...string) orm.QuerySeter {
 	return d
 }
 
+func (d *DoNothingQuerySetter) UseTable(table string) orm.QuerySeter {
+	return d
+}
+
+func (d *DoNothingQuerySetter) Clone() orm.QuerySeter {
+	return d
+}
+
 func (d *DoNothingQuerySetter) RelatedSel(params ...interface{}) orm.QuerySeter {
 	return d
 }
@@ -133,10 +254,54 @@ func (d *DoNothingQuerySetter) ForUpdate() orm.QuerySeter {
 	return d
 }
 
+func (d *DoNothingQuerySetter) ForUpdateNoWait() orm.QuerySeter {
+	return d
+}
+
+func (d *DoNothingQuerySetter) Sum(expr string, result interface{}) error {
+	return nil
+}
+
+func (d *DoNothingQuerySetter) SumWithCtx(ctx context.Context, expr string, result interface{}) error {
+	return nil
+}
+
+func (d *DoNothingQuerySetter) Max(expr string, result interface{}) error {
+	return nil
+}
+
+func (d *DoNothingQuerySetter) MaxWithCtx(ctx context.Context, expr string, result interface{}) error {
+	return nil
+}
+
+func (d *DoNothingQuerySetter) Min(expr string, result interface{}) error {
+	return nil
+}
+
+func (d *DoNothingQuerySetter) MinWithCtx(ctx context.Context, expr string, result interface{}) error {
+	return nil
+}
+
+func (d *DoNothingQuerySetter) Avg(expr string, result interface{}) error {
+	return nil
+}
+
+func (d *DoNothingQuerySetter) AvgWithCtx(ctx context.Context, expr string, result interface{}) error {
+	return nil
+}
+
 func (d *DoNothingQuerySetter) Count() (int64, error) {
 	return 0, nil
 }
 
+func (d *DoNothingQuerySetter) CountDistinct(col string) (int64, error) {
+	return 0, nil
+}
+
+func (d *DoNothingQuerySetter) CountDistinctWithCtx(ctx context.Context, col string) (int64, error) {
+	return 0, nil
+}
+
 func (d *DoNothingQuerySetter) Exist() bool {
 	return true
 }
@@ -153,14 +318,38 @@ func (d *DoNothingQuerySetter) PrepareInsert() (orm.Inserter, error) {
 	return nil, nil
 }
 
+func (d *DoNothingQuerySetter) Prepare() (orm.PreparedQuerySeter, error) {
+	return nil, nil
+}
+
+func (d *DoNothingQuerySetter) PrepareWithCtx(ctx context.Context) (orm.PreparedQuerySeter, error) {
+	return nil, nil
+}
+
 func (d *DoNothingQuerySetter) All(container interface{}, cols ...string) (int64, error) {
 	return 0, nil
 }
 
+func (d *DoNothingQuerySetter) AllWithTotalCount(container interface{}, cols ...string) (int64, error) {
+	return 0, nil
+}
+
+func (d *DoNothingQuerySetter) AllWithTotalCountWithCtx(ctx context.Context, container interface{}, cols ...string) (int64, error) {
+	return 0, nil
+}
+
 func (d *DoNothingQuerySetter) One(container interface{}, cols ...string) error {
 	return nil
 }
 
+func (d *DoNothingQuerySetter) First(container interface{}, cols ...string) error {
+	return nil
+}
+
+func (d *DoNothingQuerySetter) FirstWithCtx(ctx context.Context, container interface{}, cols ...string) error {
+	return nil
+}
+
 func (d *DoNothingQuerySetter) Values(results *[]orm.Params, exprs ...string) (int64, error) {
 	return 0, nil
 }
@@ -169,6 +358,14 @@ func (d *DoNothingQuerySetter) ValuesList(results *[]orm.ParamsList, exprs ...st
 	return 0, nil
 }
 
+func (d *DoNothingQuerySetter) ValuesListTyped(results *[]orm.ParamsList, exprs ...string) (int64, error) {
+	return 0, nil
+}
+
+func (d *DoNothingQuerySetter) ValuesListTypedWithCtx(ctx context.Context, results *[]orm.ParamsList, exprs ...string) (int64, error) {
+	return 0, nil
+}
+
 func (d *DoNothingQuerySetter) ValuesFlat(result *orm.ParamsList, expr string) (int64, error) {
 	return 0, nil
 }
@@ -180,3 +377,15 @@ func (d *DoNothingQuerySetter) RowsToMap(result *orm.Params, keyCol, valueCol st
 func (d *DoNothingQuerySetter) RowsToStruct(ptrStruct interface{}, keyCol, valueCol string) (int64, error) {
 	return 0, nil
 }
+
+func (d *DoNothingQuerySetter) Reduce(ctx context.Context, initial interface{}, fn func(acc, md interface{}) interface{}) (interface{}, error) {
+	return initial, nil
+}
+
+func (d *DoNothingQuerySetter) Channel(ctx context.Context, bufSize int) (<-chan interface{}, <-chan error) {
+	rows := make(chan interface{})
+	errs := make(chan error)
+	close(rows)
+	close(errs)
+	return rows, errs
+}
@@ -15,9 +15,12 @@
 package mock
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/beego/beego/v2/client/orm"
 )
 
 func TestDoNothingQuerySetter(t *testing.T) {
@@ -25,14 +28,24 @@ func TestDoNothingQuerySetter(t *testing.T) {
 	setter.GroupBy().Filter("").Limit(10).
 		Distinct().Exclude("a").FilterRaw("", "").
 		ForceIndex().ForUpdate().IgnoreIndex().
-		Offset(11).OrderBy().RelatedSel().SetCond(nil).UseIndex()
+		Offset(11).OrderBy().RelatedSel().SetCond(nil).UseIndex().UseTable("").Clone().SeekGt("", nil).OrderByNulls("", false, orm.NullsLast).InLocation(nil).ForUpdateNoWait().FilterCond(nil).ExcludeCond(nil).Having("").Set("", nil).Unlimited().NoStmtCache().FilterFullText(nil, "").GroupConcat("", "", "").FilterOr("").FilterOrCond(nil).OrderRandom().ExcludeExists("", nil).SelectColumns().FilterIsNull("").FilterIsNotNull("")
 
 	assert.True(t, setter.Exist())
 	err := setter.One(nil)
 	assert.Nil(t, err)
+	err = setter.First(nil)
+	assert.Nil(t, err)
 	i, err := setter.Count()
 	assert.Equal(t, int64(0), i)
 	assert.Nil(t, err)
+	i, err = setter.CountDistinct("")
+	assert.Equal(t, int64(0), i)
+	assert.Nil(t, err)
+
+	assert.Nil(t, setter.Sum("", nil))
+	assert.Nil(t, setter.Max("", nil))
+	assert.Nil(t, setter.Min("", nil))
+	assert.Nil(t, setter.Avg("", nil))
 
 	i, err = setter.Delete()
 	assert.Equal(t, int64(0), i)
@@ -42,6 +55,14 @@ func TestDoNothingQuerySetter(t *testing.T) {
 	assert.Equal(t, int64(0), i)
 	assert.Nil(t, err)
 
+	i, err = setter.AllWithTotalCount(nil)
+	assert.Equal(t, int64(0), i)
+	assert.Nil(t, err)
+
+	i, err = setter.AllWithTotalCountWithCtx(context.Background(), nil)
+	assert.Equal(t, int64(0), i)
+	assert.Nil(t, err)
+
 	i, err = setter.Update(nil)
 	assert.Equal(t, int64(0), i)
 	assert.Nil(t, err)
@@ -54,10 +75,33 @@ func TestDoNothingQuerySetter(t *testing.T) {
 	assert.Equal(t, int64(0), i)
 	assert.Nil(t, err)
 
+	acc, err := setter.Reduce(context.Background(), 1, nil)
+	assert.Equal(t, 1, acc)
+	assert.Nil(t, err)
+
+	rows, errs := setter.Channel(context.Background(), 1)
+	_, ok := <-rows
+	assert.False(t, ok)
+	assert.Nil(t, <-errs)
+
+	assert.Nil(t, setter.Union(nil))
+	assert.Nil(t, setter.UnionAll(nil))
+
+	plan, err := setter.Explain(context.Background())
+	assert.Equal(t, "", plan)
+	assert.Nil(t, err)
+
+	cursor, err := setter.Paginate(nil, "", nil, 10)
+	assert.Nil(t, cursor)
+	assert.Nil(t, err)
+
 	i, err = setter.Values(nil)
 	assert.Equal(t, int64(0), i)
 	assert.Nil(t, err)
 
+	err = setter.ValuesOne(nil)
+	assert.Nil(t, err)
+
 	i, err = setter.ValuesFlat(nil, "")
 	assert.Equal(t, int64(0), i)
 	assert.Nil(t, err)
@@ -66,9 +110,21 @@ func TestDoNothingQuerySetter(t *testing.T) {
 	assert.Equal(t, int64(0), i)
 	assert.Nil(t, err)
 
+	i, err = setter.ValuesListTyped(nil)
+	assert.Equal(t, int64(0), i)
+	assert.Nil(t, err)
+
+	i, err = setter.Scan(nil)
+	assert.Equal(t, int64(0), i)
+	assert.Nil(t, err)
+
 	ins, err := setter.PrepareInsert()
 	assert.Nil(t, err)
 	assert.Nil(t, ins)
 
+	p, err := setter.Prepare()
+	assert.Nil(t, err)
+	assert.Nil(t, p)
+
 	assert.NotNil(t, setter.GetCond())
 }
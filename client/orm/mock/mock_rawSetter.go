@@ -15,6 +15,7 @@
 package mock
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/beego/beego/v2/client/orm"
@@ -26,18 +27,42 @@ func (d *DoNothingRawSetter) Exec() (sql.Result, error) {
 	return nil, nil
 }
 
+func (d *DoNothingRawSetter) ExecWithCtx(ctx context.Context) (sql.Result, error) {
+	return nil, nil
+}
+
 func (d *DoNothingRawSetter) QueryRow(containers ...interface{}) error {
 	return nil
 }
 
+func (d *DoNothingRawSetter) QueryRowWithCtx(ctx context.Context, containers ...interface{}) error {
+	return nil
+}
+
+func (d *DoNothingRawSetter) QueryMulti(containers ...interface{}) (int64, error) {
+	return 0, nil
+}
+
+func (d *DoNothingRawSetter) QueryMultiWithCtx(ctx context.Context, containers ...interface{}) (int64, error) {
+	return 0, nil
+}
+
 func (d *DoNothingRawSetter) QueryRows(containers ...interface{}) (int64, error) {
 	return 0, nil
 }
 
+func (d *DoNothingRawSetter) QueryRowsWithCtx(ctx context.Context, containers ...interface{}) (int64, error) {
+	return 0, nil
+}
+
 func (d *DoNothingRawSetter) SetArgs(i ...interface{}) orm.RawSeter {
 	return d
 }
 
+func (d *DoNothingRawSetter) NoStmtCache() orm.RawSeter {
+	return d
+}
+
 func (d *DoNothingRawSetter) Values(container *[]orm.Params, cols ...string) (int64, error) {
 	return 0, nil
 }
@@ -15,6 +15,7 @@
 package mock
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -46,18 +47,36 @@ func TestDoNothingRawSetter(t *testing.T) {
 	assert.Equal(t, int64(0), i)
 	assert.Nil(t, err)
 
+	i, err = rs.QueryMulti()
+	assert.Equal(t, int64(0), i)
+	assert.Nil(t, err)
+
+	i, err = rs.QueryRowsWithCtx(context.Background())
+	assert.Equal(t, int64(0), i)
+	assert.Nil(t, err)
+
 	err = rs.QueryRow()
 	// assert.Equal(t, int64(0), i)
 	assert.Nil(t, err)
 
+	err = rs.QueryRowWithCtx(context.Background())
+	assert.Nil(t, err)
+
 	s, err := rs.Exec()
 	assert.Nil(t, err)
 	assert.Nil(t, s)
 
+	s, err = rs.ExecWithCtx(context.Background())
+	assert.Nil(t, err)
+	assert.Nil(t, s)
+
 	p, err := rs.Prepare()
 	assert.Nil(t, err)
 	assert.Nil(t, p)
 
 	rrs := rs.SetArgs()
 	assert.Equal(t, rrs, rs)
+
+	rrs = rs.NoStmtCache()
+	assert.Equal(t, rrs, rs)
 }
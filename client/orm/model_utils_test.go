@@ -15,7 +15,12 @@
 package orm
 
 import (
+	"context"
+	"database/sql"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -47,6 +52,10 @@ func (i *Interface) TableEngine() string {
 	return "innodb"
 }
 
+func (i *Interface) TableCharset() string {
+	return "utf8mb4"
+}
+
 func TestDbBase_GetTables(t *testing.T) {
 	RegisterModel(&Interface{})
 	mi, ok := modelCache.get("INTERFACE_")
@@ -55,8 +64,217 @@ func TestDbBase_GetTables(t *testing.T) {
 
 	engine := getTableEngine(mi.addrField)
 	assert.Equal(t, "innodb", engine)
+	charset := getTableCharset(mi.addrField)
+	assert.Equal(t, "utf8mb4", charset)
 	uniques := getTableUnique(mi.addrField)
 	assert.Equal(t, [][]string{{"unique1"}, {"unique2"}}, uniques)
 	indexes := getTableIndex(mi.addrField)
 	assert.Equal(t, [][]string{{"index1"}, {"index2"}}, indexes)
 }
+
+func TestTableCharsetDDL(t *testing.T) {
+	mc := NewModelCacheHandler()
+	mc.register("", true, nil, &Interface{})
+	al := &alias{Driver: DRMySQL, DbBaser: newdbBaseMysql(), Engine: "INNODB"}
+	queries, _, err := mc.getDbCreateSQL(al)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(queries))
+	assert.True(t, strings.HasSuffix(queries[0], ") ENGINE=innodb DEFAULT CHARSET=utf8mb4;"), queries[0])
+
+	al = &alias{Driver: DRSqlite, DbBaser: newdbBaseSqlite()}
+	queries, _, err = mc.getDbCreateSQL(al)
+	assert.Nil(t, err)
+	assert.False(t, strings.Contains(queries[0], "CHARSET"), queries[0])
+}
+
+type Audit struct {
+	CreatedBy string
+	UpdatedBy string `orm:"size(20)"`
+	Ignored   string `orm:"-"`
+}
+
+type AuditSkipped struct {
+	SkippedBy string
+}
+
+type EmbeddedAudit struct {
+	Id           int
+	Audit        `orm:"null;size(50);column(audit)"`
+	AuditSkipped `orm:"-"`
+	time.Time
+}
+
+func TestEmbeddedStructFields(t *testing.T) {
+	mi, err := newModelInfo(reflect.ValueOf(&EmbeddedAudit{}), nil)
+	assert.Nil(t, err)
+
+	fi := mi.fields.GetByColumn("created_by")
+	assert.NotNil(t, fi)
+	assert.True(t, fi.null)
+	assert.Equal(t, 50, fi.size)
+	assert.Equal(t, []int{1, 0}, fi.fieldIndex)
+
+	// the tag of field overrides the inherited tag
+	fi = mi.fields.GetByColumn("updated_by")
+	assert.NotNil(t, fi)
+	assert.True(t, fi.null)
+	assert.Equal(t, 20, fi.size)
+
+	assert.Nil(t, mi.fields.GetByColumn("audit"))
+	assert.Nil(t, mi.fields.GetByColumn("ignored"))
+	assert.Nil(t, mi.fields.GetByColumn("skipped_by"))
+
+	// time.Time is a column instead of the embedded struct
+	fi = mi.fields.GetByColumn("time")
+	assert.NotNil(t, fi)
+	assert.Equal(t, TypeDateTimeField, fi.fieldType)
+
+	// so is the Scanner
+	mi, err = newModelInfo(reflect.ValueOf(&EmbeddedScanner{}), nil)
+	assert.Nil(t, err)
+	fi = mi.fields.GetByColumn("null_int32")
+	assert.NotNil(t, fi)
+	assert.True(t, fi.isScanner)
+	assert.Nil(t, mi.fields.GetByColumn("int32"))
+
+	_, err = newModelInfo(reflect.ValueOf(&EmbeddedAuditPtr{}), nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "embedded struct pointer is not supported")
+}
+
+type EmbeddedScanner struct {
+	Id int
+	sql.NullInt32
+}
+
+type EmbeddedAuditPtr struct {
+	Id int
+	*Audit
+}
+
+type MissingRel struct {
+	Id     int
+	Parent *EmbeddedAudit `orm:"rel(fk)"`
+}
+
+func TestBootStrapError(t *testing.T) {
+	mc := NewModelCacheHandler()
+	assert.Nil(t, mc.register("", true, nil, &MissingRel{}))
+
+	err := mc.bootstrap()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "may be miss register")
+	// the later calls return the same error
+	assert.Equal(t, err, mc.bootstrap())
+
+	mc.clean()
+	assert.Nil(t, mc.bootstrap())
+}
+
+type LegacyAccount struct {
+	Id        int
+	FirstName string
+	LastLogin string         `orm:"column(last_login)"`
+	Owner     *LegacyAccount `orm:"null;rel(fk)"`
+}
+
+func TestRegisterModelWithNameStrategy(t *testing.T) {
+	camel := func(s string) string {
+		return strings.ToLower(s[:1]) + s[1:]
+	}
+	mc := NewModelCacheHandler()
+	assert.Nil(t, mc.register("", true, camel, &LegacyAccount{}))
+
+	mi, ok := mc.getByFullName(getFullName(reflect.TypeOf(LegacyAccount{})))
+	assert.True(t, ok)
+	assert.NotNil(t, mi.fields.GetByColumn("firstName"))
+	assert.NotNil(t, mi.fields.GetByColumn("last_login"))
+	assert.NotNil(t, mi.fields.GetByColumn("owner_id"))
+
+	// the other models use the global name strategy
+	mi, err := newModelInfo(reflect.ValueOf(&EmbeddedAudit{}), nil)
+	assert.Nil(t, err)
+	assert.NotNil(t, mi.fields.GetByColumn("created_by"))
+}
+
+type DbDefaultTask struct {
+	ID     int
+	Title  string
+	Status string `orm:"size(20);use_db_default"`
+}
+
+func TestInsertUseDbDefault(t *testing.T) {
+	mc := NewModelCacheHandler()
+	assert.Nil(t, mc.register("", true, nil, &DbDefaultTask{}))
+	mi, _ := mc.getByFullName(getFullName(reflect.TypeOf(DbDefaultTask{})))
+	assert.True(t, mi.fields.GetByName("Status").useDbDefault)
+
+	al := *getDbAlias("default")
+	q := new(dryRunQuerier)
+
+	// the zero value is omitted, so the database uses the column default
+	task := &DbDefaultTask{Title: "first"}
+	_, err := al.DbBaser.Insert(context.Background(), q, mi, reflect.ValueOf(task).Elem(), al.TZ)
+	assert.Nil(t, err)
+	query, args := q.last()
+	assert.NotContains(t, query, "status")
+	assert.Equal(t, []interface{}{"first"}, args)
+
+	task.Status = "done"
+	_, err = al.DbBaser.Insert(context.Background(), q, mi, reflect.ValueOf(task).Elem(), al.TZ)
+	assert.Nil(t, err)
+	query, args = q.last()
+	assert.Contains(t, query, "status")
+	assert.Equal(t, []interface{}{"first", "done"}, args)
+}
+
+type ScopedNote struct {
+	ID       int
+	TenantID int `orm:"query_scope"`
+	Text     string
+}
+
+func TestQueryScopeTag(t *testing.T) {
+	mc := NewModelCacheHandler()
+	assert.Nil(t, mc.register("", true, nil, &ScopedNote{}, &DbDefaultTask{}))
+	mi, _ := mc.getByFullName(getFullName(reflect.TypeOf(ScopedNote{})))
+	assert.True(t, mi.queryScoped)
+	mi, _ = mc.getByFullName(getFullName(reflect.TypeOf(DbDefaultTask{})))
+	assert.False(t, mi.queryScoped)
+}
+
+type GeneratedOrder struct {
+	ID       int
+	Price    float64
+	Quantity int
+	Total    float64 `orm:"generated"`
+}
+
+func TestGeneratedColumn(t *testing.T) {
+	mc := NewModelCacheHandler()
+	assert.Nil(t, mc.register("", true, nil, &GeneratedOrder{}))
+	mi, _ := mc.getByFullName(getFullName(reflect.TypeOf(GeneratedOrder{})))
+	assert.True(t, mi.fields.GetByName("Total").generated)
+	// the generated column is still read
+	assert.Contains(t, mi.fields.dbcols, "total")
+
+	al := *getDbAlias("default")
+	q := new(dryRunQuerier)
+	order := &GeneratedOrder{ID: 1, Price: 2.5, Quantity: 4, Total: 10}
+
+	_, err := al.DbBaser.Insert(context.Background(), q, mi, reflect.ValueOf(order).Elem(), al.TZ)
+	assert.Nil(t, err)
+	query, args := q.last()
+	assert.NotContains(t, query, "total")
+	assert.Len(t, args, 3)
+
+	_, err = al.DbBaser.InsertMulti(context.Background(), q, mi, reflect.ValueOf([]*GeneratedOrder{order, order}), 2, al.TZ)
+	assert.Nil(t, err)
+	query, _ = q.last()
+	assert.NotContains(t, query, "total")
+
+	_, err = al.DbBaser.Update(context.Background(), q, mi, reflect.ValueOf(order).Elem(), al.TZ, nil)
+	assert.Nil(t, err)
+	query, _ = q.last()
+	assert.NotContains(t, query, "total")
+}
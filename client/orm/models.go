@@ -18,7 +18,6 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
-	"runtime/debug"
 	"strings"
 	"sync"
 )
@@ -41,6 +40,7 @@ type _modelCache struct {
 	cache           map[string]*modelInfo
 	cacheByFullName map[string]*modelInfo
 	done            bool
+	err             error // the error of bootstrap
 }
 
 // NewModelCacheHandler generator of _modelCache
@@ -109,14 +109,15 @@ func (mc *_modelCache) clean() {
 	mc.cache = make(map[string]*modelInfo)
 	mc.cacheByFullName = make(map[string]*modelInfo)
 	mc.done = false
+	mc.err = nil
 }
 
 // bootstrap bootstrap for models
-func (mc *_modelCache) bootstrap() {
+func (mc *_modelCache) bootstrap() error {
 	mc.Lock()
 	defer mc.Unlock()
 	if mc.done {
-		return
+		return mc.err
 	}
 	var (
 		err    error
@@ -220,7 +221,8 @@ func (mc *_modelCache) bootstrap() {
 							}
 						}
 						if !added {
-							panic(fmt.Errorf("cannot generate auto reverse field info `%s` to `%s`", fi.fullName, ffi.fullName))
+							err = fmt.Errorf("cannot generate auto reverse field info `%s` to `%s`", fi.fullName, ffi.fullName)
+							goto end
 						}
 					}
 				}
@@ -320,15 +322,13 @@ func (mc *_modelCache) bootstrap() {
 	}
 
 end:
-	if err != nil {
-		fmt.Println(err)
-		debug.PrintStack()
-	}
+	mc.err = err
 	mc.done = true
+	return err
 }
 
 // register register models to model cache
-func (mc *_modelCache) register(prefixOrSuffixStr string, prefixOrSuffix bool, models ...interface{}) (err error) {
+func (mc *_modelCache) register(prefixOrSuffixStr string, prefixOrSuffix bool, strategy fn, models ...interface{}) (err error) {
 	for _, model := range models {
 		val := reflect.ValueOf(model)
 		typ := reflect.Indirect(val).Type()
@@ -368,7 +368,11 @@ func (mc *_modelCache) register(prefixOrSuffixStr string, prefixOrSuffix bool, m
 			return nil
 		}
 
-		mi := newModelInfo(val)
+		var mi *modelInfo
+		if mi, err = newModelInfo(val, strategy); err != nil {
+			err = fmt.Errorf("<orm.RegisterModel> %w", err)
+			return
+		}
 		if mi.fields.pk == nil {
 		outFor:
 			for _, fi := range mi.fields.fieldsDB {
@@ -433,12 +437,16 @@ func (mc *_modelCache) getDbCreateSQL(al *alias) (queries []string, tableIndexes
 
 		sqlIndexes := [][]string{}
 
+		// multi-column indexes named by index(name) tag
+		var indexNames []string
+		namedIndexes := make(map[string][]string)
+
 		for _, fi := range mi.fields.fieldsDB {
 
 			column := fmt.Sprintf("    %s%s%s ", Q, fi.column, Q)
 			col := getColumnTyp(al, fi)
 
-			if fi.auto {
+			if fi.auto || fi.sequence {
 				switch al.Driver {
 				case DRSqlite, DRPostgres:
 					column += T["auto"]
@@ -470,6 +478,13 @@ func (mc *_modelCache) getDbCreateSQL(al *alias) (queries []string, tableIndexes
 				}
 			}
 
+			if fi.indexName != "" {
+				if _, ok := namedIndexes[fi.indexName]; !ok {
+					indexNames = append(indexNames, fi.indexName)
+				}
+				namedIndexes[fi.indexName] = append(namedIndexes[fi.indexName], fi.column)
+			}
+
 			if strings.Contains(column, "%COL%") {
 				column = strings.Replace(column, "%COL%", fi.column, -1)
 			}
@@ -512,6 +527,11 @@ func (mc *_modelCache) getDbCreateSQL(al *alias) (queries []string, tableIndexes
 				engine = al.Engine
 			}
 			sql += " ENGINE=" + engine
+			if mi.model != nil {
+				if charset := getTableCharset(mi.addrField); charset != "" {
+					sql += " DEFAULT CHARSET=" + charset
+				}
+			}
 		}
 
 		sql += ";"
@@ -544,6 +564,17 @@ func (mc *_modelCache) getDbCreateSQL(al *alias) (queries []string, tableIndexes
 			tableIndexes[mi.table] = append(tableIndexes[mi.table], index)
 		}
 
+		for _, name := range indexNames {
+			cols := strings.Join(namedIndexes[name], sep)
+			sql := fmt.Sprintf("CREATE INDEX %s%s%s ON %s%s%s (%s%s%s);", Q, name, Q, Q, mi.table, Q, Q, cols, Q)
+
+			tableIndexes[mi.table] = append(tableIndexes[mi.table], dbIndex{
+				Table: mi.table,
+				Name:  name,
+				SQL:   sql,
+			})
+		}
+
 	}
 
 	return
@@ -0,0 +1,186 @@
+// Copyright 2020 beego
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orm
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// check the type can be used by field with tag `orm:"type(array)"`
+func isArrayFieldType(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Slice {
+		return false
+	}
+	switch typ.Elem().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Bool, reflect.String:
+		return true
+	}
+	return false
+}
+
+// get the column type of array field, only postgres has native array types.
+func getArrayColumnTyp(al *alias, fi *fieldInfo) string {
+	T := al.DbBaser.DbTypes()
+	if al.Driver != DRPostgres {
+		return T["string-text"]
+	}
+	switch fi.sf.Type.Elem().Kind() {
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return T["int64"] + "[]"
+	case reflect.Float32, reflect.Float64:
+		return T["float64"] + "[]"
+	case reflect.Bool:
+		return T["bool"] + "[]"
+	case reflect.String:
+		return T["string-text"] + "[]"
+	}
+	return T["int32"] + "[]"
+}
+
+// format the values as array literal, e.g. {1,2,3} or {"a","b"}.
+// it's the same text format as pq.Array and is accepted by array columns of postgres.
+func formatArray(values []interface{}) string {
+	elems := make([]string, len(values))
+	for i, v := range values {
+		if s, ok := v.(string); ok {
+			s = strings.Replace(s, `\`, `\\`, -1)
+			s = strings.Replace(s, `"`, `\"`, -1)
+			elems[i] = `"` + s + `"`
+		} else {
+			elems[i] = ToStr(v)
+		}
+	}
+	return "{" + strings.Join(elems, ",") + "}"
+}
+
+// get the array literal of a slice field, nil slice is NULL.
+func getArrayFieldValue(field reflect.Value) interface{} {
+	if field.IsNil() {
+		return nil
+	}
+	values := make([]interface{}, field.Len())
+	for i := range values {
+		values[i] = field.Index(i).Interface()
+	}
+	return formatArray(values)
+}
+
+// convert a value of Params to array literal
+func getArrayParamValue(value interface{}) interface{} {
+	val := reflect.ValueOf(value)
+	if value == nil || val.Kind() != reflect.Slice {
+		return value
+	}
+	return getArrayFieldValue(val)
+}
+
+// convert the filter args to array literal,
+// e.g. Filter("tags", []string{"a", "b"}) or Filter("tags", "a", "b")
+func getArrayArgsValue(args []interface{}) interface{} {
+	if len(args) == 1 {
+		if val := reflect.ValueOf(args[0]); val.Kind() == reflect.Slice {
+			return getArrayFieldValue(val)
+		}
+	}
+	return formatArray(args)
+}
+
+// parse the one-dimensional array literal returned by database.
+func parseArray(s string) ([]string, error) {
+	if len(s) < 2 || s[0] != '{' || s[len(s)-1] != '}' {
+		return nil, fmt.Errorf("invalid array literal `%s`", s)
+	}
+	s = s[1 : len(s)-1]
+	elems := make([]string, 0, strings.Count(s, ",")+1)
+	if s == "" {
+		return elems, nil
+	}
+
+	var b strings.Builder
+	quoted, escaped := false, false
+	for _, c := range s {
+		switch {
+		case escaped:
+			b.WriteRune(c)
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == '"':
+			quoted = !quoted
+		case c == ',' && !quoted:
+			elems = append(elems, b.String())
+			b.Reset()
+		default:
+			b.WriteRune(c)
+		}
+	}
+	if quoted || escaped {
+		return nil, fmt.Errorf("invalid array literal `{%s}`", s)
+	}
+	return append(elems, b.String()), nil
+}
+
+// set the array literal value read from database to the slice field
+func setArrayFieldValue(field reflect.Value, value interface{}) error {
+	if value == nil {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+	elems, err := parseArray(ToStr(value))
+	if err != nil {
+		return err
+	}
+
+	typ := field.Type().Elem()
+	slice := reflect.MakeSlice(field.Type(), len(elems), len(elems))
+	for i, e := range elems {
+		v := slice.Index(i)
+		switch typ.Kind() {
+		case reflect.String:
+			v.SetString(e)
+		case reflect.Bool:
+			b, err := strconv.ParseBool(e)
+			if err != nil {
+				return err
+			}
+			v.SetBool(b)
+		case reflect.Float32, reflect.Float64:
+			f, err := strconv.ParseFloat(e, typ.Bits())
+			if err != nil {
+				return err
+			}
+			v.SetFloat(f)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			n, err := strconv.ParseUint(e, 10, typ.Bits())
+			if err != nil {
+				return err
+			}
+			v.SetUint(n)
+		default:
+			n, err := strconv.ParseInt(e, 10, typ.Bits())
+			if err != nil {
+				return err
+			}
+			v.SetInt(n)
+		}
+	}
+	field.Set(slice)
+	return nil
+}
@@ -14,6 +14,10 @@
 
 package orm
 
+import (
+	"fmt"
+)
+
 // RegisterModel register models
 func RegisterModel(models ...interface{}) {
 	RegisterModelWithPrefix("", models...)
@@ -21,20 +25,47 @@ func RegisterModel(models ...interface{}) {
 
 // RegisterModelWithPrefix register models with a prefix
 func RegisterModelWithPrefix(prefix string, models ...interface{}) {
-	if err := modelCache.register(prefix, true, models...); err != nil {
+	if err := modelCache.register(prefix, true, nil, models...); err != nil {
 		panic(err)
 	}
 }
 
 // RegisterModelWithSuffix register models with a suffix
 func RegisterModelWithSuffix(suffix string, models ...interface{}) {
-	if err := modelCache.register(suffix, false, models...); err != nil {
+	if err := modelCache.register(suffix, false, nil, models...); err != nil {
+		panic(err)
+	}
+}
+
+// RegisterModelWithNameStrategy register models which field names are mapped to the column names
+// by strategy instead of the global name strategy, e.g. the camelCase columns of legacy tables.
+// the column tag still takes precedence.
+func RegisterModelWithNameStrategy(strategy func(string) string, models ...interface{}) {
+	if strategy == nil {
+		panic(fmt.Errorf("<orm.RegisterModelWithNameStrategy> strategy cannot be nil"))
+	}
+	if err := modelCache.register("", true, strategy, models...); err != nil {
 		panic(err)
 	}
 }
 
 // BootStrap bootstrap models.
 // make all model parsed and can not add more models
+// it panics if the models are invalid, use BootStrapE to handle the error.
 func BootStrap() {
-	modelCache.bootstrap()
+	if err := BootStrapE(); err != nil {
+		panic(err)
+	}
+}
+
+// BootStrapE bootstrap models like BootStrap, and return the error of models,
+// e.g. the rel model is not registered. it only bootstraps once,
+// the later calls return the same error.
+//
+//	orm.RegisterModel(new(User), new(Post))
+//	if err := orm.BootStrapE(); err != nil {
+//		log.Fatalf("invalid models: %v", err)
+//	}
+func BootStrapE() error {
+	return modelCache.bootstrap()
 }
@@ -0,0 +1,117 @@
+// Copyright 2020 beego
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orm
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"reflect"
+)
+
+// codec of the field with codec tag
+type fieldCodec struct {
+	name      string
+	marshal   func(v interface{}) ([]byte, error)
+	unmarshal func(data []byte, v interface{}) error
+}
+
+var fieldCodecs = map[string]*fieldCodec{
+	"gob": {name: "gob", marshal: gobMarshal, unmarshal: gobUnmarshal},
+}
+
+// RegisterFieldCodec register a codec for fields with tag `orm:"codec(name)"`,
+// e.g. to store a struct as msgpack:
+//
+//	orm.RegisterFieldCodec("msgpack", msgpack.Marshal, msgpack.Unmarshal)
+//
+//	type Job struct {
+//		Id      int
+//		Payload *Payload `orm:"codec(msgpack);null"`
+//	}
+//
+// The field is encoded by marshal before writing to database and decoded by unmarshal
+// into a pointer to the field after reading, the bytes are stored in a binary column.
+// The nil pointer, map and slice are saved as NULL. The codec "gob" is registered by default.
+// The encoded values can't be compared, so filter the field with isnull only.
+// It must be called before RegisterModel.
+func RegisterFieldCodec(name string, marshal func(v interface{}) ([]byte, error), unmarshal func(data []byte, v interface{}) error) {
+	if marshal == nil || unmarshal == nil {
+		panic(fmt.Errorf("<orm.RegisterFieldCodec> codec `%s` need both marshal and unmarshal", name))
+	}
+	fieldCodecs[name] = &fieldCodec{name: name, marshal: marshal, unmarshal: unmarshal}
+}
+
+// get the column type of codec field
+func getCodecColumnTyp(al *alias) string {
+	switch al.Driver {
+	case DRMySQL, DRTiDB:
+		return "longblob"
+	case DRPostgres:
+		return "bytea"
+	default:
+		return "blob"
+	}
+}
+
+// encode the value to write to database
+func (c *fieldCodec) encode(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	switch val := reflect.ValueOf(value); val.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		if val.IsNil() {
+			return nil, nil
+		}
+	}
+	b, err := c.marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("codec `%s` encode failed: %w", c.name, err)
+	}
+	return b, nil
+}
+
+// decode the value read from database to the field, NULL is the zero value
+func (c *fieldCodec) decode(field reflect.Value, value interface{}) error {
+	var b []byte
+	switch v := value.(type) {
+	case nil:
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	case []byte:
+		b = v
+	default:
+		b = []byte(ToStr(v))
+	}
+	ptr := reflect.New(field.Type())
+	if err := c.unmarshal(b, ptr.Interface()); err != nil {
+		return fmt.Errorf("codec `%s` decode failed: %w", c.name, err)
+	}
+	field.Set(ptr.Elem())
+	return nil
+}
+
+func gobMarshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func gobUnmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
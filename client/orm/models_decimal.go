@@ -0,0 +1,111 @@
+// Copyright 2020 beego
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orm
+
+import (
+	sqldriver "database/sql/driver"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal is the exact decimal number of the field with tag `orm:"digits(19);decimals(4)"`,
+// which is saved as DECIMAL(19,4) column, e.g. the money. the value is bound and scanned
+// as the string, so it doesn't lose precision like float64.
+// sqlite has no exact decimal type, the values of more than 15 significant digits lose precision in sqlite.
+//
+//	type Order struct {
+//		Id     int
+//		Amount orm.Decimal `orm:"digits(19);decimals(4)"`
+//	}
+//
+// the other types implementing sql.Scanner and driver.Valuer can use the tag too.
+type Decimal struct {
+	s string
+}
+
+// NewDecimal parse the decimal number, e.g. "-12.3400", the fraction like "1/3" isn't allowed.
+func NewDecimal(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	if strings.Contains(s, "/") {
+		return Decimal{}, fmt.Errorf("<orm.NewDecimal> invalid decimal `%s`", s)
+	}
+	if _, ok := new(big.Rat).SetString(s); !ok {
+		return Decimal{}, fmt.Errorf("<orm.NewDecimal> invalid decimal `%s`", s)
+	}
+	return Decimal{s: s}, nil
+}
+
+// NewDecimalFromRat return the decimal of r rounded to scale digits after the point
+func NewDecimalFromRat(r *big.Rat, scale int) Decimal {
+	return Decimal{s: r.FloatString(scale)}
+}
+
+// String return the decimal as the string, the digits are kept as they are, e.g. "12.3400"
+func (d Decimal) String() string {
+	if d.s == "" {
+		return "0"
+	}
+	return d.s
+}
+
+// Rat return the decimal as big.Rat for the exact arithmetic
+func (d Decimal) Rat() *big.Rat {
+	r, _ := new(big.Rat).SetString(d.String())
+	return r
+}
+
+// Float64 return the nearest float64 of the decimal
+func (d Decimal) Float64() float64 {
+	f, _ := d.Rat().Float64()
+	return f
+}
+
+// Cmp compare the decimals by value, -1 if d < o, 0 if d == o and 1 if d > o
+func (d Decimal) Cmp(o Decimal) int {
+	return d.Rat().Cmp(o.Rat())
+}
+
+// Value implement driver.Valuer, the decimal is bound as the string
+func (d Decimal) Value() (sqldriver.Value, error) {
+	return d.String(), nil
+}
+
+// Scan implement sql.Scanner, NULL is 0
+func (d *Decimal) Scan(value interface{}) error {
+	var s string
+	switch v := value.(type) {
+	case nil:
+		*d = Decimal{}
+		return nil
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Errorf("<Decimal.Scan> unsupported value `%v` of type %T", value, value)
+	}
+	dec, err := NewDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = dec
+	return nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2020 beego
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orm
+
+// ModelDesc describes a registered model
+type ModelDesc struct {
+	// struct name with package path, e.g. github.com/beego/beego/v2/client/orm.User
+	Name   string
+	Table  string
+	Fields []FieldDesc
+}
+
+// FieldDesc describes a field of registered model
+type FieldDesc struct {
+	Name string
+	// empty if the field is not a column of the table, e.g. m2m and reverse fields
+	Column string
+	// column type of the default database, e.g. varchar(255)
+	DbType string
+	Null   bool
+	Pk     bool
+	Auto   bool
+	Unique bool
+	Index  bool
+	// nil if the field is not a relation
+	Rel *RelDesc
+}
+
+// RelDesc describes the relation of a field
+type RelDesc struct {
+	// one of fk, one, m2m, reverse_one and reverse_many
+	Type string
+	// table of the related model
+	Table string
+	// table of the m2m through model
+	Through  string
+	OnDelete string
+}
+
+var relDescTypes = map[int]string{
+	RelForeignKey:  "fk",
+	RelOneToOne:    "one",
+	RelManyToMany:  "m2m",
+	RelReverseOne:  "reverse_one",
+	RelReverseMany: "reverse_many",
+}
+
+// RegisteredModels return the registered models in registering order,
+// the models are bootstrapped if they are not.
+// it's read only, changing the result does not affect the models.
+func RegisteredModels() []ModelDesc {
+	BootStrap()
+
+	modelCache.RLock()
+	defer modelCache.RUnlock()
+
+	al, _ := dataBaseCache.get("default")
+	mis := modelCache.allOrdered()
+	models := make([]ModelDesc, 0, len(mis))
+	for _, mi := range mis {
+		models = append(models, newModelDesc(al, mi))
+	}
+	return models
+}
+
+func newModelDesc(al *alias, mi *modelInfo) ModelDesc {
+	md := ModelDesc{
+		Name:   mi.fullName,
+		Table:  mi.table,
+		Fields: make([]FieldDesc, 0, len(mi.fields.orders)),
+	}
+	for _, col := range mi.fields.orders {
+		fi := mi.fields.columns[col]
+		fd := FieldDesc{
+			Name:   fi.name,
+			Null:   fi.null,
+			Pk:     fi.pk,
+			Auto:   fi.auto,
+			Unique: fi.unique,
+			Index:  fi.index || fi.indexName != "",
+		}
+		if fi.dbcol {
+			fd.Column = fi.column
+			if al != nil {
+				fd.DbType = getColumnTyp(al, fi)
+			}
+		}
+		if fi.rel || fi.reverse {
+			rd := &RelDesc{
+				Type:     relDescTypes[fi.fieldType],
+				OnDelete: fi.onDelete,
+			}
+			if fi.relModelInfo != nil {
+				rd.Table = fi.relModelInfo.table
+			}
+			if fi.relThroughModelInfo != nil {
+				rd.Through = fi.relThroughModelInfo.table
+			}
+			fd.Rel = rd
+		}
+		md.Fields = append(md.Fields, fd)
+	}
+	return md
+}
@@ -104,6 +104,7 @@ type fieldInfo struct {
 	dbcol               bool // table column fk and onetoone
 	inModel             bool
 	auto                bool
+	sequence            bool
 	pk                  bool
 	null                bool
 	index               bool
@@ -115,6 +116,9 @@ type fieldInfo struct {
 	rel                 bool // if type equal to RelForeignKey, RelOneToOne, RelManyToMany then true
 	reverse             bool
 	isFielder           bool // implement Fielder interface
+	logRedact           bool // mask the value in the query log
+	useDbDefault        bool // omit the zero value on insert, let database use the column default
+	generated           bool // computed by database, not inserted or updated
 	mi                  *modelInfo
 	fieldIndex          []int
 	fieldType           int
@@ -131,6 +135,7 @@ type fieldInfo struct {
 	reverseFieldInfoM2M *fieldInfo
 	relTable            string
 	relThrough          string
+	dbTypes             map[DriverType]string // the column types of drivers, e.g. type(mysql:longtext;sqlite:text)
 	relThroughModelInfo *modelInfo
 	relModelInfo        *modelInfo
 	digits              int
@@ -138,10 +143,18 @@ type fieldInfo struct {
 	onDelete            string
 	description         string
 	timePrecision       *int
+	enum                []string
+	enumNative          bool // type(enum), use ENUM column on MySQL
+	transformer         *fieldTransformer
+	codec               *fieldCodec
+	array               bool   // type(array), slice saved as array literal
+	point               bool   // type(point), [2]float64 saved as geography point
+	indexName           string // index(name), fields with the same name make a multi-column index
+	isScanner           bool   // implement sql.Scanner and driver.Valuer
 }
 
 // new field info
-func newFieldInfo(mi *modelInfo, field reflect.Value, sf reflect.StructField, mName string) (fi *fieldInfo, err error) {
+func newFieldInfo(mi *modelInfo, field reflect.Value, sf reflect.StructField, mName string, inherit string) (fi *fieldInfo, err error) {
 	var (
 		tag       string
 		tagValue  string
@@ -168,7 +181,12 @@ func newFieldInfo(mi *modelInfo, field reflect.Value, sf reflect.StructField, mN
 		}
 	}
 
-	attrs, tags = parseStructTag(sf.Tag.Get(defaultStructTagName))
+	// the tag of field overrides the inherited tag of embedded struct
+	tag = sf.Tag.Get(defaultStructTagName)
+	if inherit != "" {
+		tag = inherit + defaultStructTagDelim + tag
+	}
+	attrs, tags = parseStructTag(tag)
 
 	if _, ok := attrs["-"]; ok {
 		return nil, errSkipField
@@ -184,6 +202,14 @@ func newFieldInfo(mi *modelInfo, field reflect.Value, sf reflect.StructField, mN
 		initial.Set(v)
 	}
 
+	if v := tags["type"]; strings.Contains(v, ":") {
+		if fi.dbTypes, err = parseDriverColumnTypes(v); err != nil {
+			tag, tagValue = "type", v
+			goto wrongTag
+		}
+		delete(tags, "type")
+	}
+
 checkType:
 	switch f := addrField.Interface().(type) {
 	case Fielder:
@@ -242,9 +268,50 @@ checkType:
 			}
 		}
 
-		fieldType, err = getFieldType(addrField)
-		if err != nil {
-			goto end
+		if tags["type"] == "array" {
+			if !isArrayFieldType(field.Type()) {
+				err = fmt.Errorf("type(array) only support slice of numbers, bool and string")
+				goto end
+			}
+			fi.array = true
+			fieldType = TypeTextField
+			break checkType
+		}
+
+		if tags["type"] == "point" {
+			if !isPointFieldType(field.Type()) {
+				err = fmt.Errorf("type(point) only support [2]float64 and orm.Point")
+				goto end
+			}
+			fi.point = true
+			fieldType = TypeTextField
+			break checkType
+		}
+
+		if v, ok := tags["codec"]; ok {
+			if fi.codec, ok = fieldCodecs[v]; !ok {
+				err = fmt.Errorf("codec `%s` is not registered, use `RegisterFieldCodec()` first", v)
+				goto end
+			}
+			fieldType = TypeTextField
+			break checkType
+		}
+
+		if isScannerFieldType(field.Type()) {
+			fi.isScanner = true
+			typ := field.Type()
+			if typ.Kind() == reflect.Ptr {
+				typ = typ.Elem()
+			}
+			// the column type follows the kind, others like uuid.UUID use varchar
+			if fieldType, err = getFieldType(reflect.New(typ)); err != nil {
+				fieldType, err = TypeVarCharField, nil
+			}
+		} else {
+			fieldType, err = getFieldType(addrField)
+			if err != nil {
+				goto end
+			}
 		}
 		if fieldType == TypeVarCharField {
 			switch tags["type"] {
@@ -258,7 +325,7 @@ checkType:
 				fieldType = TypeJsonbField
 			}
 		}
-		if fieldType == TypeFloatField && (digits != "" || decimals != "") {
+		if (fieldType == TypeFloatField || fi.isScanner) && (digits != "" || decimals != "") {
 			fieldType = TypeDecimalField
 		}
 		if fieldType == TypeDateTimeField && tags["type"] == "date" {
@@ -297,7 +364,7 @@ checkType:
 
 	fi.fieldType = fieldType
 	fi.name = sf.Name
-	fi.column = getColumnName(fieldType, addrField, sf, tags["column"])
+	fi.column = getColumnName(fieldType, addrField, sf, tags["column"], mi.nameStrategy)
 	fi.addrValue = addrField
 	fi.sf = sf
 	fi.fullName = mi.fullName + mName + "." + sf.Name
@@ -305,9 +372,20 @@ checkType:
 	fi.description = tags["description"]
 	fi.null = attrs["null"]
 	fi.index = attrs["index"]
+	fi.indexName = tags["index"]
 	fi.auto = attrs["auto"]
+	fi.sequence = attrs["sequence"]
 	fi.pk = attrs["pk"]
 	fi.unique = attrs["unique"]
+	fi.logRedact = attrs["log_redact"]
+	fi.useDbDefault = attrs["use_db_default"]
+	fi.generated = attrs["generated"]
+	if fi.generated {
+		mi.hasGenerated = true
+	}
+	if attrs["query_scope"] {
+		mi.queryScoped = true
+	}
 
 	// Mark object property if there is attribute "default" in the orm configuration
 	if _, ok := tags["default"]; ok {
@@ -319,6 +397,7 @@ checkType:
 		fi.null = false
 		fi.index = false
 		fi.auto = false
+		fi.sequence = false
 		fi.pk = false
 		fi.unique = false
 	default:
@@ -418,6 +497,50 @@ checkType:
 			err = fmt.Errorf("non-integer type cannot set auto")
 			goto end
 		}
+		if fi.sequence {
+			err = fmt.Errorf("non-integer type cannot set sequence")
+			goto end
+		}
+	}
+
+	if fi.sequence && !fi.pk {
+		err = fmt.Errorf("sequence can only be set on primary key")
+		goto end
+	}
+
+	if v, ok := tags["enum"]; ok {
+		switch fieldType {
+		case TypeVarCharField, TypeCharField, TypeTextField:
+		default:
+			err = fmt.Errorf("enum can only be set on string type")
+			goto end
+		}
+		for _, e := range strings.Split(v, ",") {
+			if e = strings.TrimSpace(e); e != "" {
+				fi.enum = append(fi.enum, e)
+			}
+		}
+		if len(fi.enum) == 0 {
+			err = fmt.Errorf("enum need at least one value")
+			goto end
+		}
+		fi.enumNative = tags["type"] == "enum"
+	}
+
+	if v, ok := tags["transform"]; ok {
+		switch {
+		case fi.codec != nil:
+			err = fmt.Errorf("transform can not be set with codec")
+			goto end
+		case fieldType == TypeVarCharField, fieldType == TypeCharField, fieldType == TypeTextField:
+		default:
+			err = fmt.Errorf("transform can only be set on string type")
+			goto end
+		}
+		if fi.transformer, ok = fieldTransformers[v]; !ok {
+			err = fmt.Errorf("transform `%s` is not registered, use `RegisterFieldTransformer()` first", v)
+			goto end
+		}
 	}
 
 	if fi.auto || fi.pk {
@@ -483,3 +606,42 @@ end:
 wrongTag:
 	return nil, fmt.Errorf("wrong tag format: `%s:\"%s\"`, %s", tag, tagValue, err)
 }
+
+// parse the column types of drivers, e.g. mysql:longtext;sqlite:text,
+// the driver is the name registered by RegisterDriver.
+func parseDriverColumnTypes(value string) (map[DriverType]string, error) {
+	types := make(map[DriverType]string)
+	for _, v := range strings.Split(value, defaultStructTagDelim) {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		i := strings.Index(v, ":")
+		if i <= 0 || i == len(v)-1 {
+			return nil, fmt.Errorf("column type of driver must be driver:type, got `%s`", v)
+		}
+		name := strings.ToLower(strings.TrimSpace(v[:i]))
+		if name == "sqlite" {
+			name = "sqlite3"
+		}
+		typ, ok := drivers[name]
+		if !ok {
+			return nil, fmt.Errorf("driver `%s` have not registered", name)
+		}
+		types[typ] = strings.TrimSpace(v[i+1:])
+	}
+	return types, nil
+}
+
+// check the value of enum field, nil is left to the database.
+func (fi *fieldInfo) checkEnum(value interface{}) error {
+	if len(fi.enum) == 0 || value == nil {
+		return nil
+	}
+	s := ToStr(value)
+	for _, e := range fi.enum {
+		if s == e {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: `%s` of field `%s`, expected one of `%s`", ErrEnumValue, s, fi.fullName, strings.Join(fi.enum, ","))
+}
@@ -16,8 +16,9 @@ package orm
 
 import (
 	"fmt"
-	"os"
 	"reflect"
+	"strings"
+	"time"
 )
 
 // single model info
@@ -32,22 +33,67 @@ type modelInfo struct {
 	fields    *fields
 	addrField reflect.Value // store the original struct value
 	uniques   []string
+	// map the field name to column name, nil means the global name strategy
+	nameStrategy fn
+	// the queries are scoped by the query scoper
+	queryScoped bool
+	// some fields are generated columns
+	hasGenerated bool
 }
 
 // new model info
-func newModelInfo(val reflect.Value) (mi *modelInfo) {
-	mi = &modelInfo{}
+func newModelInfo(val reflect.Value, strategy fn) (mi *modelInfo, err error) {
+	mi = &modelInfo{nameStrategy: strategy}
 	mi.fields = newFields()
 	ind := reflect.Indirect(val)
 	mi.addrField = val
 	mi.name = ind.Type().Name()
 	mi.fullName = getFullName(ind.Type())
-	addModelFields(mi, ind, "", []int{})
+	err = addModelFields(mi, ind, "", []int{}, "")
 	return
 }
 
+// the tags of embedded struct which are not inherited by its fields
+var notInheritedTags = map[string]bool{
+	"-": true, "column": true, "pk": true, "auto": true, "sequence": true,
+}
+
+// get the tags of embedded struct which are inherited by its fields
+func inheritedTag(tag string) string {
+	parts := make([]string, 0, 4)
+	for _, v := range strings.Split(tag, defaultStructTagDelim) {
+		v = strings.TrimSpace(v)
+		name := strings.ToLower(v)
+		if i := strings.Index(name, "("); i > 0 {
+			name = name[:i]
+		}
+		if v != "" && !notInheritedTags[name] {
+			parts = append(parts, v)
+		}
+	}
+	return strings.Join(parts, defaultStructTagDelim)
+}
+
+// check whether the anonymous field is flattened into the columns of model,
+// time.Time and the types with sql.Scanner or driver.Valuer are one column.
+func isEmbeddedModelStruct(field reflect.Value) bool {
+	if field.Kind() != reflect.Struct || field.Type() == reflect.TypeOf(time.Time{}) {
+		return false
+	}
+	if ptr := reflect.PtrTo(field.Type()); ptr.Implements(scannerType) || ptr.Implements(valuerType) {
+		return false
+	}
+	if field.CanAddr() {
+		if _, ok := field.Addr().Interface().(Fielder); ok {
+			return false
+		}
+	}
+	return true
+}
+
 // index: FieldByIndex returns the nested field corresponding to index
-func addModelFields(mi *modelInfo, ind reflect.Value, mName string, index []int) {
+// inherit: the orm tag of the embedded structs, the fields inherit it
+func addModelFields(mi *modelInfo, ind reflect.Value, mName string, index []int, inherit string) error {
 	var (
 		err error
 		fi  *fieldInfo
@@ -63,11 +109,27 @@ func addModelFields(mi *modelInfo, ind reflect.Value, mName string, index []int)
 		}
 		// add anonymous struct fields
 		if sf.Anonymous {
-			addModelFields(mi, field, mName+"."+sf.Name, append(index, i))
-			continue
+			tag := sf.Tag.Get(defaultStructTagName)
+			if attrs, _ := parseStructTag(tag); attrs["-"] {
+				continue
+			}
+			if field.Kind() == reflect.Ptr && field.Type().Elem().Kind() == reflect.Struct {
+				err = fmt.Errorf("embedded struct pointer is not supported, embed the struct value instead")
+				break
+			}
+			if isEmbeddedModelStruct(field) {
+				tag = inheritedTag(tag)
+				if inherit != "" {
+					tag = inherit + defaultStructTagDelim + tag
+				}
+				if err := addModelFields(mi, field, mName+"."+sf.Name, append(index, i), tag); err != nil {
+					return err
+				}
+				continue
+			}
 		}
 
-		fi, err = newFieldInfo(mi, field, sf, mName)
+		fi, err = newFieldInfo(mi, field, sf, mName, inherit)
 		if err == errSkipField {
 			err = nil
 			continue
@@ -94,9 +156,9 @@ func addModelFields(mi *modelInfo, ind reflect.Value, mName string, index []int)
 	}
 
 	if err != nil {
-		fmt.Println(fmt.Errorf("field: %s.%s, %s", ind.Type(), sf.Name, err))
-		os.Exit(2)
+		return fmt.Errorf("field: %s.%s, %s", ind.Type(), sf.Name, err)
 	}
+	return nil
 }
 
 // combine related model info to new model info.
@@ -0,0 +1,131 @@
+// Copyright 2020 beego
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orm
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Point is the value of the field with tag `orm:"type(point)"`, which is saved as
+// geography(Point) column of PostGIS. the first is the longitude (X) and the second
+// is the latitude (Y). [2]float64 can be used as well.
+//
+//	type Shop struct {
+//		Id       int
+//		Location orm.Point `orm:"type(point)"`
+//	}
+type Point [2]float64
+
+// the spatial reference of the points, WGS 84 used by GPS
+const pointSRID = 4326
+
+// check the type can be used by field with tag `orm:"type(point)"`
+func isPointFieldType(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Array && typ.Len() == 2 && typ.Elem().Kind() == reflect.Float64
+}
+
+// get the column type of point field, the others than postgres save the text of point.
+func getPointColumnTyp(al *alias) string {
+	if al.Driver != DRPostgres {
+		return al.DbBaser.DbTypes()["string-text"]
+	}
+	return fmt.Sprintf("geography(Point,%d)", pointSRID)
+}
+
+// format the point as EWKT, e.g. SRID=4326;POINT(121.47 31.23),
+// PostGIS takes it as ST_SetSRID(ST_MakePoint(121.47, 31.23), 4326).
+func formatPoint(x, y float64) string {
+	return fmt.Sprintf("SRID=%d;POINT(%s %s)", pointSRID,
+		strconv.FormatFloat(x, 'f', -1, 64), strconv.FormatFloat(y, 'f', -1, 64))
+}
+
+// get the EWKT of a point field
+func getPointFieldValue(field reflect.Value) interface{} {
+	return formatPoint(field.Index(0).Float(), field.Index(1).Float())
+}
+
+// convert a value of Params to EWKT
+func getPointParamValue(value interface{}) interface{} {
+	if val := reflect.ValueOf(value); value != nil && isPointFieldType(val.Type()) {
+		return getPointFieldValue(val)
+	}
+	return value
+}
+
+// parse the point returned by database, it's the hex EWKB of PostGIS,
+// or the WKT/EWKT text, e.g. the text column or ST_AsText.
+func parsePoint(s string) (x, y float64, err error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(strings.ToUpper(s), "SRID=") {
+		if i := strings.IndexByte(s, ';'); i > 0 {
+			s = s[i+1:]
+		}
+	}
+	if strings.HasPrefix(strings.ToUpper(s), "POINT") {
+		coords := strings.Fields(strings.Trim(strings.TrimSpace(s[len("POINT"):]), "()"))
+		if len(coords) != 2 {
+			return 0, 0, fmt.Errorf("invalid point `%s`", s)
+		}
+		if x, err = strconv.ParseFloat(coords[0], 64); err != nil {
+			return 0, 0, err
+		}
+		if y, err = strconv.ParseFloat(coords[1], 64); err != nil {
+			return 0, 0, err
+		}
+		return x, y, nil
+	}
+
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) < 21 {
+		return 0, 0, fmt.Errorf("invalid point `%s`", s)
+	}
+	var order binary.ByteOrder = binary.BigEndian
+	if b[0] == 1 {
+		order = binary.LittleEndian
+	}
+	typ := order.Uint32(b[1:5])
+	b = b[5:]
+	// the flag of EWKB with SRID
+	if typ&0x20000000 != 0 {
+		b = b[4:]
+	}
+	if typ&0xffff != 1 || len(b) < 16 {
+		return 0, 0, fmt.Errorf("invalid point `%s`", s)
+	}
+	x = math.Float64frombits(order.Uint64(b[0:8]))
+	y = math.Float64frombits(order.Uint64(b[8:16]))
+	return x, y, nil
+}
+
+// set the point value read from database to the point field, NULL is (0, 0)
+func setPointFieldValue(field reflect.Value, value interface{}) error {
+	if value == nil {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+	x, y, err := parsePoint(ToStr(value))
+	if err != nil {
+		return err
+	}
+	field.Index(0).SetFloat(x)
+	field.Index(1).SetFloat(y)
+	return nil
+}
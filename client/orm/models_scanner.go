@@ -0,0 +1,93 @@
+// Copyright 2020 beego
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orm
+
+import (
+	"database/sql"
+	sqldriver "database/sql/driver"
+	"reflect"
+)
+
+var (
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	valuerType  = reflect.TypeOf((*sqldriver.Valuer)(nil)).Elem()
+
+	// the types handled by the field type switches
+	nativeNullTypes = map[reflect.Type]bool{
+		reflect.TypeOf(sql.NullString{}):  true,
+		reflect.TypeOf(sql.NullInt64{}):   true,
+		reflect.TypeOf(sql.NullFloat64{}): true,
+		reflect.TypeOf(sql.NullBool{}):    true,
+	}
+)
+
+// check the field type implements both sql.Scanner and driver.Valuer,
+// e.g. sql.NullInt32, uuid.UUID or custom money types.
+// the field value is read by Scan and written by Value.
+func isScannerFieldType(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if nativeNullTypes[typ] {
+		return false
+	}
+	ptr := reflect.PtrTo(typ)
+	return ptr.Implements(scannerType) && (typ.Implements(valuerType) || ptr.Implements(valuerType))
+}
+
+// get the value of the field by driver.Valuer, nil pointer is NULL.
+func getScannerFieldValue(field reflect.Value) (interface{}, error) {
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return nil, nil
+		}
+		return field.Interface().(sqldriver.Valuer).Value()
+	}
+	if v, ok := field.Interface().(sqldriver.Valuer); ok {
+		return v.Value()
+	}
+	return field.Addr().Interface().(sqldriver.Valuer).Value()
+}
+
+// get the value of filter arg by driver.Valuer, ok is false if arg isn't a driver.Valuer
+func getValuerArg(arg interface{}) (value interface{}, ok bool, err error) {
+	v, ok := arg.(sqldriver.Valuer)
+	if !ok {
+		return nil, false, nil
+	}
+	if val := reflect.ValueOf(arg); val.Kind() == reflect.Ptr && val.IsNil() {
+		return nil, true, nil
+	}
+	value, err = v.Value()
+	return value, true, err
+}
+
+// set the value read from database to the field by sql.Scanner,
+// the pointer field is set to nil for NULL.
+func setScannerFieldValue(field reflect.Value, value interface{}) error {
+	if field.Kind() == reflect.Ptr {
+		if value == nil {
+			field.Set(reflect.Zero(field.Type()))
+			return nil
+		}
+		v := reflect.New(field.Type().Elem())
+		if err := v.Interface().(sql.Scanner).Scan(value); err != nil {
+			return err
+		}
+		field.Set(v)
+		return nil
+	}
+	return field.Addr().Interface().(sql.Scanner).Scan(value)
+}
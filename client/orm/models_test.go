@@ -16,7 +16,10 @@ package orm
 
 import (
 	"database/sql"
+	sqldriver "database/sql/driver"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -275,7 +278,7 @@ type User struct {
 	ID             int    `orm:"column(id)"`
 	UserName       string `orm:"size(30);unique"`
 	Email          string `orm:"size(100)"`
-	Password       string `orm:"size(100)"`
+	Password       string `orm:"size(100);log_redact"`
 	Status         int16  `orm:"column(Status)"`
 	IsStaff        bool
 	IsActive       bool `orm:"default(true)"`
@@ -456,6 +459,108 @@ type IntegerPk struct {
 	Value string
 }
 
+type SequencePk struct {
+	ID    int64 `orm:"pk;sequence"`
+	Value string
+}
+
+type Member struct {
+	ID     int
+	Status string `orm:"size(10);enum(active, inactive, banned);type(enum)"`
+}
+
+type Secret struct {
+	ID    int `orm:"column(id)"`
+	Name  string
+	Email string `orm:"size(100);null;transform(xor)"`
+}
+
+type CodecPayload struct {
+	Name string
+	Tags []string
+}
+
+type CodecModel struct {
+	ID      int            `orm:"column(id)"`
+	Payload *CodecPayload  `orm:"codec(gob);null"`
+	Meta    map[string]int `orm:"codec(json);null"`
+}
+
+type ArrayModel struct {
+	ID     int       `orm:"column(id)"`
+	Nums   []int     `orm:"type(array);null"`
+	Tags   []string  `orm:"type(array);null"`
+	Scores []float64 `orm:"type(array);null"`
+}
+
+type IndexModel struct {
+	ID        int    `orm:"column(id)"`
+	FirstName string `orm:"size(30);index(idx_index_model_name)"`
+	LastName  string `orm:"size(30);index(idx_index_model_name)"`
+	Age       int    `orm:"index"`
+}
+
+// a fixed size id saved as hex string, like uuid.UUID
+type HexID [4]byte
+
+func (h *HexID) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case nil:
+		*h = HexID{}
+		return nil
+	default:
+		return fmt.Errorf("unsupported HexID value %T", src)
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != len(h) {
+		return fmt.Errorf("invalid HexID `%s`", s)
+	}
+	copy(h[:], b)
+	return nil
+}
+
+func (h HexID) Value() (sqldriver.Value, error) {
+	return hex.EncodeToString(h[:]), nil
+}
+
+// money in cents, saved as integer
+type Cents int64
+
+func (c *Cents) Scan(src interface{}) error {
+	n, err := StrTo(ToStr(src)).Int64()
+	*c = Cents(n)
+	return err
+}
+
+func (c Cents) Value() (sqldriver.Value, error) {
+	if c < 0 {
+		return nil, errors.New("negative cents")
+	}
+	return int64(c), nil
+}
+
+type ScannerModel struct {
+	ID     int    `orm:"column(id)"`
+	Code   HexID  `orm:"size(8)"`
+	Parent *HexID `orm:"size(8);null"`
+	Amount Cents
+	Count  sql.NullInt32 `orm:"null"`
+}
+
+// deterministic transformer for tests
+func xorBytes(b []byte) ([]byte, error) {
+	res := make([]byte, len(b))
+	for i := range b {
+		res[i] = b[i] ^ 0x5a
+	}
+	return res, nil
+}
+
 type UintPk struct {
 	ID   uint32 `orm:"pk"`
 	Name string
@@ -541,6 +646,9 @@ func init() {
 	// Debug, _ = StrTo(DBARGS.Debug).Bool()
 	Debug = true
 
+	RegisterFieldTransformer("xor", xorBytes, xorBytes)
+	RegisterFieldCodec("json", json.Marshal, json.Unmarshal)
+
 	if DBARGS.Driver == "" || DBARGS.Source == "" {
 		fmt.Println(helpinfo)
 		os.Exit(2)
@@ -0,0 +1,76 @@
+// Copyright 2020 beego
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orm
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// transformer of the field with transform tag
+type fieldTransformer struct {
+	name string
+	enc  func([]byte) ([]byte, error)
+	dec  func([]byte) ([]byte, error)
+}
+
+var fieldTransformers = make(map[string]*fieldTransformer)
+
+// RegisterFieldTransformer register a transformer for fields with tag `orm:"transform(name)"`,
+// e.g. to encrypt the values of PII columns.
+// The value is transformed by enc before writing to database, and by dec after reading,
+// the transformed bytes are stored as base64 text, so only string fields are supported.
+// Filter with exact and in operators transforms the values too, which only matches when enc is deterministic,
+// other operators such as range and contains compare with the transformed values and won't work.
+// It must be called before RegisterModel.
+func RegisterFieldTransformer(name string, enc func([]byte) ([]byte, error), dec func([]byte) ([]byte, error)) {
+	if enc == nil || dec == nil {
+		panic(fmt.Errorf("<orm.RegisterFieldTransformer> transformer `%s` need both enc and dec", name))
+	}
+	fieldTransformers[name] = &fieldTransformer{name: name, enc: enc, dec: dec}
+}
+
+// transform the value to write to database
+func (t *fieldTransformer) encode(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	b, err := t.enc([]byte(ToStr(value)))
+	if err != nil {
+		return nil, fmt.Errorf("transform `%s` encode failed: %w", t.name, err)
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// transform the value read from database
+func (t *fieldTransformer) decode(value interface{}) (interface{}, error) {
+	var s string
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		s = string(v)
+	default:
+		s = ToStr(v)
+	}
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("transform `%s` decode failed: %w", t.name, err)
+	}
+	if b, err = t.dec(b); err != nil {
+		return nil, fmt.Errorf("transform `%s` decode failed: %w", t.name, err)
+	}
+	return string(b), nil
+}
@@ -24,28 +24,37 @@ import (
 
 // 1 is attr
 // 2 is tag
+// 3 is attr or tag
 var supportTag = map[string]int{
-	"-":            1,
-	"null":         1,
-	"index":        1,
-	"unique":       1,
-	"pk":           1,
-	"auto":         1,
-	"auto_now":     1,
-	"auto_now_add": 1,
-	"size":         2,
-	"column":       2,
-	"default":      2,
-	"rel":          2,
-	"reverse":      2,
-	"rel_table":    2,
-	"rel_through":  2,
-	"digits":       2,
-	"decimals":     2,
-	"on_delete":    2,
-	"type":         2,
-	"description":  2,
-	"precision":    2,
+	"-":              1,
+	"null":           1,
+	"index":          3,
+	"unique":         1,
+	"pk":             1,
+	"auto":           1,
+	"sequence":       1,
+	"auto_now":       1,
+	"auto_now_add":   1,
+	"size":           2,
+	"column":         2,
+	"default":        2,
+	"rel":            2,
+	"reverse":        2,
+	"rel_table":      2,
+	"rel_through":    2,
+	"digits":         2,
+	"decimals":       2,
+	"on_delete":      2,
+	"type":           2,
+	"description":    2,
+	"precision":      2,
+	"enum":           2,
+	"transform":      2,
+	"log_redact":     1,
+	"use_db_default": 1,
+	"query_scope":    1,
+	"generated":      1,
+	"codec":          2,
 }
 
 // get reflect.Type name with package path.
@@ -79,6 +88,18 @@ func getTableEngine(val reflect.Value) string {
 	return ""
 }
 
+// get table default charset, e.g. utf8mb4.
+func getTableCharset(val reflect.Value) string {
+	fun := val.MethodByName("TableCharset")
+	if fun.IsValid() {
+		vals := fun.Call([]reflect.Value{})
+		if len(vals) > 0 && vals[0].Kind() == reflect.String {
+			return vals[0].String()
+		}
+	}
+	return ""
+}
+
 // get table index from method.
 func getTableIndex(val reflect.Value) [][]string {
 	fun := val.MethodByName("TableIndex")
@@ -122,11 +143,14 @@ func isApplicableTableForDB(val reflect.Value, db string) bool {
 	return true
 }
 
-// get snaked column name
-func getColumnName(ft int, addrField reflect.Value, sf reflect.StructField, col string) string {
+// get snaked column name, or the name mapped by strategy if it isn't nil
+func getColumnName(ft int, addrField reflect.Value, sf reflect.StructField, col string, strategy fn) string {
 	column := col
 	if col == "" {
-		column = nameStrategyMap[nameStrategy](sf.Name)
+		if strategy == nil {
+			strategy = nameStrategyMap[nameStrategy]
+		}
+		column = strategy(sf.Name)
 	}
 	switch ft {
 	case RelForeignKey, RelOneToOne:
@@ -207,6 +231,14 @@ func getFieldType(val reflect.Value) (ft int, err error) {
 				ft = TypeBooleanField
 			case sql.NullString:
 				ft = TypeVarCharField
+			case sql.NullInt32:
+				ft = TypeIntegerField
+			case sql.NullInt16:
+				ft = TypeSmallIntegerField
+			case sql.NullByte:
+				ft = TypePositiveBitField
+			case sql.NullTime:
+				ft = TypeDateTimeField
 			case time.Time:
 				ft = TypeDateTimeField
 			}
@@ -218,20 +250,39 @@ func getFieldType(val reflect.Value) (ft int, err error) {
 	return
 }
 
+// split the tags by defaultStructTagDelim, the delimiters in the parentheses are kept,
+// e.g. type(mysql:longtext;sqlite:text) is one tag.
+func splitStructTag(data string) []string {
+	parts := make([]string, 0, 4)
+	depth, start := 0, 0
+	for i := 0; i < len(data); i++ {
+		switch {
+		case data[i] == '(':
+			depth++
+		case data[i] == ')' && depth > 0:
+			depth--
+		case depth == 0 && strings.HasPrefix(data[i:], defaultStructTagDelim):
+			parts = append(parts, data[start:i])
+			start = i + len(defaultStructTagDelim)
+		}
+	}
+	return append(parts, data[start:])
+}
+
 // parse struct tag string
 func parseStructTag(data string) (attrs map[string]bool, tags map[string]string) {
 	attrs = make(map[string]bool)
 	tags = make(map[string]string)
-	for _, v := range strings.Split(data, defaultStructTagDelim) {
+	for _, v := range splitStructTag(data) {
 		if v == "" {
 			continue
 		}
 		v = strings.TrimSpace(v)
-		if t := strings.ToLower(v); supportTag[t] == 1 {
+		if t := strings.ToLower(v); supportTag[t]&1 > 0 {
 			attrs[t] = true
 		} else if i := strings.Index(v, "("); i > 0 && strings.Index(v, ")") == len(v)-1 {
 			name := t[:i]
-			if supportTag[name] == 2 {
+			if supportTag[name]&2 > 0 {
 				v = v[i+1 : len(v)-1]
 				tags[name] = v
 			}
@@ -15,6 +15,7 @@
 package orm
 
 import (
+	"math/big"
 	"reflect"
 	"testing"
 
@@ -29,6 +30,152 @@ func (n *NotApplicableModel) IsApplicableTableForDB(db string) bool {
 	return db == "default"
 }
 
+func TestParseStructTagIndex(t *testing.T) {
+	attrs, tags := parseStructTag("size(30);index")
+	assert.True(t, attrs["index"])
+	assert.Equal(t, "", tags["index"])
+
+	attrs, tags = parseStructTag("size(30);index(idx_name)")
+	assert.False(t, attrs["index"])
+	assert.Equal(t, "idx_name", tags["index"])
+}
+
+type DriverColumnType struct {
+	Id      int
+	Content string `orm:"type(mysql:longtext;sqlite:text;postgres:text);null"`
+	Name    string `orm:"size(20)"`
+}
+
+func TestParseStructTagDriverType(t *testing.T) {
+	attrs, tags := parseStructTag("type(mysql:longtext;sqlite:text);null")
+	assert.True(t, attrs["null"])
+	assert.Equal(t, "mysql:longtext;sqlite:text", tags["type"])
+
+	types, err := parseDriverColumnTypes("mysql:LONGTEXT; sqlite:text;postgres:text")
+	assert.Nil(t, err)
+	assert.Equal(t, map[DriverType]string{DRMySQL: "LONGTEXT", DRSqlite: "text", DRPostgres: "text"}, types)
+
+	_, err = parseDriverColumnTypes("unknown:text")
+	assert.NotNil(t, err)
+	_, err = parseDriverColumnTypes("mysql:")
+	assert.NotNil(t, err)
+
+	mi, err := newModelInfo(reflect.ValueOf(&DriverColumnType{}), nil)
+	assert.Nil(t, err)
+	fi := mi.fields.GetByName("Content")
+	assert.True(t, fi.null)
+	assert.Equal(t, TypeVarCharField, fi.fieldType)
+	assert.Equal(t, "longtext", getColumnTyp(&alias{Driver: DRMySQL, DbBaser: dbBasers[DRMySQL]}, fi))
+	assert.Equal(t, "text", getColumnTyp(&alias{Driver: DRPostgres, DbBaser: dbBasers[DRPostgres]}, fi))
+	assert.Equal(t, "varchar(255)", getColumnTyp(&alias{Driver: DRTiDB, DbBaser: dbBasers[DRTiDB]}, fi))
+
+	fi = mi.fields.GetByName("Name")
+	assert.Equal(t, "varchar(20)", getColumnTyp(&alias{Driver: DRMySQL, DbBaser: dbBasers[DRMySQL]}, fi))
+}
+
+type PointModel struct {
+	Id       int
+	Location Point      `orm:"type(point)"`
+	Position [2]float64 `orm:"type(point)"`
+}
+
+type WrongPointModel struct {
+	Id       int
+	Location []float64 `orm:"type(point)"`
+}
+
+func TestPointField(t *testing.T) {
+	mi, err := newModelInfo(reflect.ValueOf(&PointModel{}), nil)
+	assert.Nil(t, err)
+	fi := mi.fields.GetByName("Location")
+	assert.True(t, fi.point)
+	assert.Equal(t, "geography(Point,4326)", getColumnTyp(&alias{Driver: DRPostgres, DbBaser: dbBasers[DRPostgres]}, fi))
+	assert.Equal(t, "text", getColumnTyp(&alias{Driver: DRSqlite, DbBaser: dbBasers[DRSqlite]}, fi))
+	assert.True(t, mi.fields.GetByName("Position").point)
+
+	m := &PointModel{Location: Point{121.47, 31.23}, Position: [2]float64{-0.5, 51}}
+	d := dbBasers[DRPostgres].(*dbBasePostgres)
+	value, err := d.collectFieldValue(mi, fi, reflect.ValueOf(m).Elem(), true, DefaultTimeLoc)
+	assert.Nil(t, err)
+	assert.Equal(t, "SRID=4326;POINT(121.47 31.23)", value)
+	assert.Equal(t, "SRID=4326;POINT(-0.5 51)", getPointParamValue(m.Position))
+
+	// the hex EWKB of SRID=4326;POINT(1 2) returned by PostGIS
+	m = &PointModel{}
+	field := reflect.ValueOf(m).Elem().FieldByIndex(fi.fieldIndex)
+	_, err = d.setFieldValue(fi, []byte("0101000020E6100000000000000000F03F0000000000000040"), field)
+	assert.Nil(t, err)
+	assert.Equal(t, Point{1, 2}, m.Location)
+	_, err = d.setFieldValue(fi, "POINT(3.5 -4)", field)
+	assert.Nil(t, err)
+	assert.Equal(t, Point{3.5, -4}, m.Location)
+	_, err = d.setFieldValue(fi, "SRID=4326;POINT(5 6)", field)
+	assert.Nil(t, err)
+	assert.Equal(t, Point{5, 6}, m.Location)
+	_, err = d.setFieldValue(fi, nil, field)
+	assert.Nil(t, err)
+	assert.Equal(t, Point{}, m.Location)
+	_, err = d.setFieldValue(fi, "LINESTRING(1 2, 3 4)", field)
+	assert.NotNil(t, err)
+
+	ind := reflect.ValueOf(&WrongPointModel{}).Elem()
+	_, err = newFieldInfo(mi, ind.Field(1), ind.Type().Field(1), "", "")
+	assert.NotNil(t, err)
+}
+
+type DecimalModel struct {
+	Id      int
+	Amount  Decimal  `orm:"digits(19);decimals(4)"`
+	Balance *Decimal `orm:"digits(10);decimals(2);null"`
+}
+
+func TestDecimalField(t *testing.T) {
+	mi, err := newModelInfo(reflect.ValueOf(&DecimalModel{}), nil)
+	assert.Nil(t, err)
+	fi := mi.fields.GetByName("Amount")
+	assert.Equal(t, TypeDecimalField, fi.fieldType)
+	assert.Equal(t, "numeric(19, 4)", getColumnTyp(&alias{Driver: DRMySQL, DbBaser: dbBasers[DRMySQL]}, fi))
+	assert.Equal(t, "numeric(19, 4)", getColumnTyp(&alias{Driver: DRPostgres, DbBaser: dbBasers[DRPostgres]}, fi))
+	assert.Equal(t, TypeDecimalField, mi.fields.GetByName("Balance").fieldType)
+
+	amount, err := NewDecimal("123456789012345.6789")
+	assert.Nil(t, err)
+	m := &DecimalModel{Amount: amount}
+	d := dbBasers[DRMySQL].(*dbBaseMysql)
+	value, err := d.collectFieldValue(mi, fi, reflect.ValueOf(m).Elem(), true, DefaultTimeLoc)
+	assert.Nil(t, err)
+	assert.Equal(t, "123456789012345.6789", value)
+	value, err = d.collectFieldValue(mi, mi.fields.GetByName("Balance"), reflect.ValueOf(m).Elem(), true, DefaultTimeLoc)
+	assert.Nil(t, err)
+	assert.Nil(t, value)
+
+	// the value is scanned without the float conversion
+	m = &DecimalModel{}
+	field := reflect.ValueOf(m).Elem().FieldByIndex(fi.fieldIndex)
+	value, err = d.convertValueFromDB(fi, []byte("9999999999999999.9999"), DefaultTimeLoc)
+	assert.Nil(t, err)
+	_, err = d.setFieldValue(fi, value, field)
+	assert.Nil(t, err)
+	assert.Equal(t, "9999999999999999.9999", m.Amount.String())
+	_, err = d.setFieldValue(fi, 12.5, field)
+	assert.Nil(t, err)
+	assert.Equal(t, "12.5", m.Amount.String())
+	_, err = d.setFieldValue(fi, int64(7), field)
+	assert.Nil(t, err)
+	assert.Equal(t, "7", m.Amount.String())
+	_, err = d.setFieldValue(fi, "abc", field)
+	assert.NotNil(t, err)
+
+	_, err = NewDecimal("1/3")
+	assert.NotNil(t, err)
+	a, _ := NewDecimal("1.50")
+	b, _ := NewDecimal("1.5")
+	assert.Equal(t, 0, a.Cmp(b))
+	assert.Equal(t, "0", Decimal{}.String())
+	assert.Equal(t, 1.5, a.Float64())
+	assert.Equal(t, "0.3333", NewDecimalFromRat(big.NewRat(1, 3), 4).String())
+}
+
 func TestIsApplicableTableForDB(t *testing.T) {
 	assert.False(t, isApplicableTableForDB(reflect.ValueOf(&NotApplicableModel{}), "defa"))
 	assert.True(t, isApplicableTableForDB(reflect.ValueOf(&NotApplicableModel{}), "default"))
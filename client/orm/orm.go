@@ -60,6 +60,9 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/beego/beego/v2/client/orm/clauses/order_clause"
@@ -86,10 +89,59 @@ var (
 	ErrStmtClosed    = errors.New("<QuerySeter> stmt already closed")
 	ErrArgs          = errors.New("<Ormer> args error may be empty")
 	ErrNotImplement  = errors.New("have not implement")
+	ErrNoDB          = errors.New("<Ormer> database of the alias is not available")
+	ErrEnumValue     = errors.New("<Ormer> value is not in the enum of field")
+	ErrWrongColumn   = errors.New("<Ormer> wrong db field/column name")
+	ErrTableNotFound = errors.New("<Ormer> table not found")
+	ErrIsolation     = errors.New("<Ormer> isolation level is not supported by the driver")
+
+	ErrLockNotAvailable = errors.New("<QuerySeter> lock of the rows is not available")
 
 	ErrLastInsertIdUnavailable = errors.New("<Ormer> last insert id is unavailable")
+
+	ErrEmptyCondition = errors.New("<Ormer.DeleteWhere> condition is empty, use Condition.AllowEmptyCondition to delete all rows")
 )
 
+// NotFoundError is returned by Read instead of ErrNoRows when the row is not found,
+// if it's enabled by EnableNotFoundError. errors.Is(err, ErrNoRows) is still true.
+type NotFoundError struct {
+	Table string
+	// PK is nil when reading by other columns
+	PK interface{}
+}
+
+func (e *NotFoundError) Error() string {
+	if e.PK == nil {
+		return fmt.Sprintf("no row found in table `%s`", e.Table)
+	}
+	return fmt.Sprintf("no row found in table `%s` with pk `%v`", e.Table, e.PK)
+}
+
+func (e *NotFoundError) Unwrap() error {
+	return ErrNoRows
+}
+
+// 1 if Read returns *NotFoundError
+var notFoundErrorEnabled int32
+
+// EnableNotFoundError make Read return *NotFoundError with the table and pk when the row is not found,
+// by default ErrNoRows is returned, compare the error by errors.Is(err, ErrNoRows) after enabling it.
+func EnableNotFoundError(enable bool) {
+	var v int32
+	if enable {
+		v = 1
+	}
+	atomic.StoreInt32(&notFoundErrorEnabled, v)
+}
+
+// get the error of Read when the row of table is not found
+func notFoundError(table string, pk interface{}) error {
+	if atomic.LoadInt32(&notFoundErrorEnabled) == 0 {
+		return ErrNoRows
+	}
+	return &NotFoundError{Table: table, PK: pk}
+}
+
 // Params stores the Params
 type Params map[string]interface{}
 
@@ -99,6 +151,8 @@ type ParamsList []interface{}
 type ormBase struct {
 	alias *alias
 	db    dbQuerier
+	// the context of methods without ctx, e.g. the begin context of transaction
+	ctx context.Context
 }
 
 var (
@@ -116,38 +170,73 @@ func (*ormBase) getMi(md interface{}) (mi *modelInfo) {
 	return
 }
 
+// the context used by the methods without ctx
+func (o *ormBase) defaultCtx() context.Context {
+	if o.ctx != nil {
+		return o.ctx
+	}
+	return context.Background()
+}
+
 // get need ptr model info and model reflect value
-func (*ormBase) getPtrMiInd(md interface{}) (mi *modelInfo, ind reflect.Value) {
+func (o *ormBase) getPtrMiInd(md interface{}) (mi *modelInfo, ind reflect.Value) {
+	mi, ind, err := o.getPtrMiIndE(md)
+	if err != nil {
+		panic(err)
+	}
+	return
+}
+
+// like getPtrMiInd, but return error instead of panic
+func (*ormBase) getPtrMiIndE(md interface{}) (mi *modelInfo, ind reflect.Value, err error) {
 	val := reflect.ValueOf(md)
 	ind = reflect.Indirect(val)
 	typ := ind.Type()
 	if val.Kind() != reflect.Ptr {
-		panic(fmt.Errorf("<Ormer> cannot use non-ptr model struct `%s`", getFullName(typ)))
+		return nil, ind, fmt.Errorf("<Ormer> cannot use non-ptr model struct `%s`", getFullName(typ))
 	}
-	mi = getTypeMi(typ)
+	mi, err = getTypeMiE(typ)
 	return
 }
 
 func getTypeMi(mdTyp reflect.Type) *modelInfo {
+	mi, err := getTypeMiE(mdTyp)
+	if err != nil {
+		panic(err)
+	}
+	return mi
+}
+
+// like getTypeMi, but return error instead of panic
+func getTypeMiE(mdTyp reflect.Type) (*modelInfo, error) {
 	name := getFullName(mdTyp)
 	if mi, ok := modelCache.getByFullName(name); ok {
-		return mi
+		return mi, nil
 	}
-	panic(fmt.Errorf("<Ormer> table: `%s` not found, make sure it was registered with `RegisterModel()`", name))
+	return nil, fmt.Errorf("%w: `%s`, make sure it was registered with `RegisterModel()`", ErrTableNotFound, name)
 }
 
 // get field info from model info by given field name
-func (*ormBase) getFieldInfo(mi *modelInfo, name string) *fieldInfo {
+func (o *ormBase) getFieldInfo(mi *modelInfo, name string) *fieldInfo {
+	fi, err := o.getFieldInfoE(mi, name)
+	if err != nil {
+		panic(err)
+	}
+	return fi
+}
+
+// like getFieldInfo, but return error instead of panic
+func (*ormBase) getFieldInfoE(mi *modelInfo, name string) (*fieldInfo, error) {
 	fi, ok := mi.fields.GetByAny(name)
 	if !ok {
-		panic(fmt.Errorf("<Ormer> cannot find field `%s` for model `%s`", name, mi.fullName))
+		return nil, fmt.Errorf("%w `%s` for model `%s`", ErrWrongColumn, name, mi.fullName)
 	}
-	return fi
+	return fi, nil
 }
 
 // read data to model
 func (o *ormBase) Read(md interface{}, cols ...string) error {
-	return o.ReadWithCtx(context.Background(), md, cols...)
+	return o.ReadWithCtx(o.defaultCtx(), md, cols...)
 }
 
 func (o *ormBase) ReadWithCtx(ctx context.Context, md interface{}, cols ...string) error {
@@ -155,9 +244,22 @@ func (o *ormBase) ReadWithCtx(ctx context.Context, md interface{}, cols ...strin
 	return o.alias.DbBaser.Read(ctx, o.db, mi, ind, o.alias.TZ, cols, false)
 }
 
+// read data to model, like Read(), but convert the times in loc instead of the database time zone
+func (o *ormBase) ReadInLocation(md interface{}, loc *time.Location, cols ...string) error {
+	return o.ReadInLocationWithCtx(o.defaultCtx(), md, loc, cols...)
+}
+
+func (o *ormBase) ReadInLocationWithCtx(ctx context.Context, md interface{}, loc *time.Location, cols ...string) error {
+	mi, ind := o.getPtrMiInd(md)
+	if loc == nil {
+		loc = o.alias.TZ
+	}
+	return o.alias.DbBaser.Read(ctx, o.db, mi, ind, loc, cols, false)
+}
+
 // read data to model, like Read(), but use "SELECT FOR UPDATE" form
 func (o *ormBase) ReadForUpdate(md interface{}, cols ...string) error {
-	return o.ReadForUpdateWithCtx(context.Background(), md, cols...)
+	return o.ReadForUpdateWithCtx(o.defaultCtx(), md, cols...)
 }
 
 func (o *ormBase) ReadForUpdateWithCtx(ctx context.Context, md interface{}, cols ...string) error {
@@ -167,14 +269,14 @@ func (o *ormBase) ReadForUpdateWithCtx(ctx context.Context, md interface{}, cols
 
 // Try to read a row from the database, or insert one if it doesn't exist
 func (o *ormBase) ReadOrCreate(md interface{}, col1 string, cols ...string) (bool, int64, error) {
-	return o.ReadOrCreateWithCtx(context.Background(), md, col1, cols...)
+	return o.ReadOrCreateWithCtx(o.defaultCtx(), md, col1, cols...)
 }
 
 func (o *ormBase) ReadOrCreateWithCtx(ctx context.Context, md interface{}, col1 string, cols ...string) (bool, int64, error) {
 	cols = append([]string{col1}, cols...)
 	mi, ind := o.getPtrMiInd(md)
 	err := o.alias.DbBaser.Read(ctx, o.db, mi, ind, o.alias.TZ, cols, false)
-	if err == ErrNoRows {
+	if errors.Is(err, ErrNoRows) {
 		// Create
 		id, err := o.InsertWithCtx(ctx, md)
 		return err == nil, id, err
@@ -192,9 +294,135 @@ func (o *ormBase) ReadOrCreateWithCtx(ctx context.Context, md interface{}, col1
 	return false, id, err
 }
 
+// ReadOrCreateMulti read the rows of models by lookup columns in one query,
+// and insert the models which don't exist.
+// the found rows are set back to the models, the created flags are returned in order.
+// the reads and the inserts aren't atomic, run it in a transaction and keep a unique index
+// on the lookup columns so that the concurrent callers can't insert the same rows.
+func (o *ormBase) ReadOrCreateMulti(mds interface{}, lookupCols []string) ([]bool, error) {
+	return o.ReadOrCreateMultiWithCtx(o.defaultCtx(), mds, lookupCols)
+}
+
+func (o *ormBase) ReadOrCreateMultiWithCtx(ctx context.Context, mds interface{}, lookupCols []string) ([]bool, error) {
+	sind := reflect.Indirect(reflect.ValueOf(mds))
+	if sind.Kind() != reflect.Slice && !(sind.Kind() == reflect.Array && sind.CanAddr()) {
+		return nil, ErrArgs
+	}
+	if sind.Len() == 0 || len(lookupCols) == 0 {
+		return nil, ErrArgs
+	}
+
+	mds0 := sind.Index(0)
+	if mds0.Kind() != reflect.Ptr {
+		mds0 = mds0.Addr()
+	}
+	mi := o.getMi(mds0.Interface())
+
+	fis := make([]*fieldInfo, 0, len(lookupCols))
+	for _, col := range lookupCols {
+		fi, ok := mi.fields.GetByAny(col)
+		if !ok || !fi.dbcol {
+			return nil, fmt.Errorf("%w `%s` in model `%s`", ErrWrongColumn, col, mi.fullName)
+		}
+		fis = append(fis, fi)
+	}
+
+	inds := make([]reflect.Value, sind.Len())
+	for i := range inds {
+		v := sind.Index(i)
+		if v.Kind() != reflect.Ptr {
+			v = v.Addr()
+		}
+		_, ind := o.getPtrMiInd(v.Interface())
+		inds[i] = ind
+	}
+
+	// the lookup is split into chunks under the placeholder limit of the driver
+	found := make(map[string]reflect.Value, len(inds))
+	bulk := safeBulk(o.alias.Driver, len(inds), len(fis))
+	if bulk < 1 {
+		bulk = 1
+	}
+	for start := 0; start < len(inds); start += bulk {
+		end := start + bulk
+		if end > len(inds) {
+			end = len(inds)
+		}
+		cond := NewCondition()
+		for _, ind := range inds[start:end] {
+			c := NewCondition()
+			for _, fi := range fis {
+				c = c.And(fi.name, ind.FieldByIndex(fi.fieldIndex).Interface())
+			}
+			cond = cond.OrCond(c)
+		}
+
+		rows := reflect.New(reflect.SliceOf(mi.addrField.Type()))
+		if _, err := newQuerySet(o, mi).SetCond(cond).Limit(-1).AllWithCtx(ctx, rows.Interface()); err != nil {
+			return nil, err
+		}
+		for i := 0; i < rows.Elem().Len(); i++ {
+			row := rows.Elem().Index(i).Elem()
+			found[lookupKey(row, fis)] = row
+		}
+	}
+
+	created := make([]bool, len(inds))
+	for i, ind := range inds {
+		key := lookupKey(ind, fis)
+		if row, ok := found[key]; ok {
+			ind.Set(row)
+			continue
+		}
+		// the values read back may differ from the model, e.g. by the case insensitive collation
+		// or the time precision, so the database decides whether the row exists
+		err := o.ReadWithCtx(ctx, ind.Addr().Interface(), lookupCols...)
+		if err == nil {
+			found[key] = ind
+			continue
+		}
+		if !errors.Is(err, ErrNoRows) {
+			return created, err
+		}
+		if _, err := o.InsertWithCtx(ctx, ind.Addr().Interface()); err != nil {
+			return created, err
+		}
+		created[i] = true
+		// the later duplicates read the inserted one
+		found[key] = ind
+	}
+	return created, nil
+}
+
+// the key of lookup columns values, the related model is keyed by its pk
+func lookupKey(ind reflect.Value, fis []*fieldInfo) string {
+	var key strings.Builder
+	for _, fi := range fis {
+		field := ind.FieldByIndex(fi.fieldIndex)
+		if fi.rel {
+			field = reflect.Indirect(field)
+			if !field.IsValid() {
+				key.WriteString("nil,")
+				continue
+			}
+			field = field.FieldByIndex(fi.relModelInfo.fields.pk.fieldIndex)
+		}
+		var val string
+		switch v := field.Interface().(type) {
+		case time.Time:
+			val = v.UTC().Format(time.RFC3339Nano)
+		default:
+			val = fmt.Sprintf("%T:%v", v, v)
+		}
+		key.WriteString(strconv.Quote(val))
+		key.WriteByte(',')
+	}
+	return key.String()
+}
+
 // insert model data to database
 func (o *ormBase) Insert(md interface{}) (int64, error) {
-	return o.InsertWithCtx(context.Background(), md)
+	return o.InsertWithCtx(o.defaultCtx(), md)
 }
 
 func (o *ormBase) InsertWithCtx(ctx context.Context, md interface{}) (int64, error) {
@@ -209,9 +437,14 @@ func (o *ormBase) InsertWithCtx(ctx context.Context, md interface{}) (int64, err
 	return id, nil
 }
 
-// set auto pk field
+// set auto pk field, or empty sequence pk field
 func (*ormBase) setPk(mi *modelInfo, ind reflect.Value, id int64) {
-	if mi.fields.pk.auto {
+	setAutoPk(mi, ind, id)
+}
+
+func setAutoPk(mi *modelInfo, ind reflect.Value, id int64) {
+	pk := mi.fields.pk
+	if pk.auto || pk.sequence && ind.FieldByIndex(pk.fieldIndex).IsZero() {
 		if mi.fields.pk.fieldType&IsPositiveIntegerField > 0 {
 			ind.FieldByIndex(mi.fields.pk.fieldIndex).SetUint(uint64(id))
 		} else {
@@ -220,9 +453,38 @@ func (*ormBase) setPk(mi *modelInfo, ind reflect.Value, id int64) {
 	}
 }
 
+// insert model data to database with the value of pk field, the pk field is not changed
+func (o *ormBase) InsertWithPK(md interface{}) (int64, error) {
+	return o.InsertWithPKWithCtx(o.defaultCtx(), md)
+}
+
+func (o *ormBase) InsertWithPKWithCtx(ctx context.Context, md interface{}) (int64, error) {
+	mi, ind := o.getPtrMiInd(md)
+	return o.alias.DbBaser.InsertWithPK(ctx, o.db, mi, ind, o.alias.TZ)
+}
+
+// InsertOrIgnore insert model data to database, do nothing if it conflicts with an existing row
+func (o *ormBase) InsertOrIgnore(md interface{}, conflictCols ...string) (int64, error) {
+	return o.InsertOrIgnoreWithCtx(o.defaultCtx(), md, conflictCols...)
+}
+
+func (o *ormBase) InsertOrIgnoreWithCtx(ctx context.Context, md interface{}, conflictCols ...string) (int64, error) {
+	mi, ind := o.getPtrMiInd(md)
+	cnt, id, err := o.alias.DbBaser.InsertOrIgnore(ctx, o.db, mi, ind, o.alias, conflictCols)
+	if err != nil {
+		return cnt, err
+	}
+
+	if cnt > 0 {
+		o.setPk(mi, ind, id)
+	}
+
+	return cnt, nil
+}
+
 // insert some models to database
 func (o *ormBase) InsertMulti(bulk int, mds interface{}) (int64, error) {
-	return o.InsertMultiWithCtx(context.Background(), bulk, mds)
+	return o.InsertMultiWithCtx(o.defaultCtx(), bulk, mds)
 }
 
 func (o *ormBase) InsertMultiWithCtx(ctx context.Context, bulk int, mds interface{}) (int64, error) {
@@ -239,6 +501,12 @@ func (o *ormBase) InsertMultiWithCtx(ctx context.Context, bulk int, mds interfac
 		return cnt, ErrArgs
 	}
 
+	if bulk > 1 {
+		// the driver placeholder limit bounds the rows of one statement
+		mi := o.getMi(sind.Index(0).Interface())
+		bulk = safeBulk(o.alias.Driver, bulk, len(mi.fields.dbcols))
+	}
+
 	if bulk <= 1 {
 		for i := 0; i < sind.Len(); i++ {
 			ind := reflect.Indirect(sind.Index(i))
@@ -259,9 +527,35 @@ func (o *ormBase) InsertMultiWithCtx(ctx context.Context, bulk int, mds interfac
 	return cnt, nil
 }
 
+// insert some models to database, return the result of each statement
+func (o *ormBase) InsertMultiResult(bulk int, mds interface{}) ([]sql.Result, error) {
+	return o.InsertMultiResultWithCtx(o.defaultCtx(), bulk, mds)
+}
+
+func (o *ormBase) InsertMultiResultWithCtx(ctx context.Context, bulk int, mds interface{}) ([]sql.Result, error) {
+	sind := reflect.Indirect(reflect.ValueOf(mds))
+
+	switch sind.Kind() {
+	case reflect.Array, reflect.Slice:
+		if sind.Len() == 0 {
+			return nil, ErrArgs
+		}
+	default:
+		return nil, ErrArgs
+	}
+
+	mi := o.getMi(sind.Index(0).Interface())
+	if bulk > 1 {
+		bulk = safeBulk(o.alias.Driver, bulk, len(mi.fields.dbcols))
+	} else {
+		bulk = 1
+	}
+	return o.alias.DbBaser.InsertMultiResult(ctx, o.db, mi, sind, bulk, o.alias.TZ)
+}
+
 // InsertOrUpdate data to database
 func (o *ormBase) InsertOrUpdate(md interface{}, colConflictAndArgs ...string) (int64, error) {
-	return o.InsertOrUpdateWithCtx(context.Background(), md, colConflictAndArgs...)
+	return o.InsertOrUpdateWithCtx(o.defaultCtx(), md, colConflictAndArgs...)
 }
 
 func (o *ormBase) InsertOrUpdateWithCtx(ctx context.Context, md interface{}, colConflitAndArgs ...string) (int64, error) {
@@ -271,7 +565,10 @@ func (o *ormBase) InsertOrUpdateWithCtx(ctx context.Context, md interface{}, col
 		return id, err
 	}
 
-	o.setPk(mi, ind, id)
+	// 0 means the row is kept by the update condition
+	if id != 0 {
+		o.setPk(mi, ind, id)
+	}
 
 	return id, nil
 }
@@ -279,7 +576,7 @@ func (o *ormBase) InsertOrUpdateWithCtx(ctx context.Context, md interface{}, col
 // update model to database.
 // cols set the columns those want to update.
 func (o *ormBase) Update(md interface{}, cols ...string) (int64, error) {
-	return o.UpdateWithCtx(context.Background(), md, cols...)
+	return o.UpdateWithCtx(o.defaultCtx(), md, cols...)
 }
 
 func (o *ormBase) UpdateWithCtx(ctx context.Context, md interface{}, cols ...string) (int64, error) {
@@ -290,7 +587,7 @@ func (o *ormBase) UpdateWithCtx(ctx context.Context, md interface{}, cols ...str
 // delete model in database
 // cols shows the delete conditions values read from. default is pk
 func (o *ormBase) Delete(md interface{}, cols ...string) (int64, error) {
-	return o.DeleteWithCtx(context.Background(), md, cols...)
+	return o.DeleteWithCtx(o.defaultCtx(), md, cols...)
 }
 
 func (o *ormBase) DeleteWithCtx(ctx context.Context, md interface{}, cols ...string) (int64, error) {
@@ -299,6 +596,19 @@ func (o *ormBase) DeleteWithCtx(ctx context.Context, md interface{}, cols ...str
 	return num, err
 }
 
+// delete the rows of model matching cond, the rows are not loaded into models.
+func (o *ormBase) DeleteWhere(md interface{}, cond *Condition) (int64, error) {
+	return o.DeleteWhereWithCtx(o.defaultCtx(), md, cond)
+}
+
+func (o *ormBase) DeleteWhereWithCtx(ctx context.Context, md interface{}, cond *Condition) (int64, error) {
+	if cond == nil || cond.IsEmpty() && !cond.allowEmpty {
+		return 0, ErrEmptyCondition
+	}
+	mi, _ := o.getPtrMiInd(md)
+	return newQuerySet(o, mi).SetCond(cond).DeleteWithCtx(ctx)
+}
+
 // create a models to models queryer
 func (o *ormBase) QueryM2M(md interface{}, name string) QueryM2Mer {
 	mi, ind := o.getPtrMiInd(md)
@@ -329,7 +639,7 @@ func (o *ormBase) QueryM2MWithCtx(_ context.Context, md interface{}, name string
 //
 // make sure the relation is defined in model struct tags.
 func (o *ormBase) LoadRelated(md interface{}, name string, args ...utils.KV) (int64, error) {
-	return o.LoadRelatedWithCtx(context.Background(), md, name, args...)
+	return o.LoadRelatedWithCtx(o.defaultCtx(), md, name, args...)
 }
 
 func (o *ormBase) LoadRelatedWithCtx(_ context.Context, md interface{}, name string, args ...utils.KV) (int64, error) {
@@ -396,6 +706,171 @@ func (o *ormBase) LoadRelatedWithCtx(_ context.Context, md interface{}, name str
 	return nums, err
 }
 
+// load related models to every model of mds, like LoadRelated,
+// but the related rows of all the models are fetched with one IN query
+// (two for many to many relations) instead of one query per model.
+// args are the same as LoadRelated, except limit and offset are not supported.
+func (o *ormBase) LoadRelatedBatch(mds interface{}, name string, args ...utils.KV) (int64, error) {
+	return o.LoadRelatedBatchWithCtx(o.defaultCtx(), mds, name, args...)
+}
+
+func (o *ormBase) LoadRelatedBatchWithCtx(ctx context.Context, mds interface{}, name string, args ...utils.KV) (int64, error) {
+	sind := reflect.Indirect(reflect.ValueOf(mds))
+
+	switch sind.Kind() {
+	case reflect.Array, reflect.Slice:
+		if sind.Len() == 0 {
+			return 0, nil
+		}
+	default:
+		return 0, ErrArgs
+	}
+
+	mi := o.getMi(sind.Index(0).Interface())
+	fi := o.getFieldInfo(mi, name)
+	if !fi.inModel || !fi.rel && !fi.reverse {
+		panic(fmt.Errorf("<Ormer> name `%s` for model `%s` is not an available rel/reverse field", name, mi.fullName))
+	}
+
+	inds := make([]reflect.Value, 0, sind.Len())
+	pks := make([]interface{}, 0, sind.Len())
+	index := make(map[string][]int, sind.Len())
+	for i := 0; i < sind.Len(); i++ {
+		ind := reflect.Indirect(sind.Index(i))
+		_, pk, ok := getExistPk(mi, ind)
+		if !ok {
+			panic(ErrMissPK)
+		}
+		key := ToStr(pk)
+		if _, ok := index[key]; !ok {
+			pks = append(pks, pk)
+		}
+		index[key] = append(index[key], i)
+		inds = append(inds, ind)
+	}
+
+	var relDepth int
+	var order string
+
+	kvs := utils.NewKVs(args...)
+	kvs.IfContains(hints.KeyRelDepth, func(value interface{}) {
+		if v, ok := value.(bool); ok {
+			if v {
+				relDepth = DefaultRelsDepth
+			}
+		} else if v, ok := value.(int); ok {
+			relDepth = v
+		}
+	}).IfContains(hints.KeyOrderBy, func(value interface{}) {
+		if v, ok := value.(string); ok {
+			order = v
+		}
+	})
+
+	// pk of the related rows to query, and the index of models each related row belongs to.
+	// for reverse relations, related rows are queried by the fk to the models.
+	var relPks []interface{}
+	var byFk bool
+	owners := make(map[string][]int)
+	relMi := fi.relModelInfo
+
+	switch {
+	case fi.fieldType == RelForeignKey || fi.fieldType == RelOneToOne:
+		for i, ind := range inds {
+			field := ind.FieldByIndex(fi.fieldIndex)
+			if field.IsNil() {
+				continue
+			}
+			if _, pk, ok := getExistPk(relMi, reflect.Indirect(field)); ok {
+				key := ToStr(pk)
+				if _, ok := owners[key]; !ok {
+					relPks = append(relPks, pk)
+				}
+				owners[key] = append(owners[key], i)
+			}
+		}
+	case fi.fieldType == RelManyToMany || fi.reverseFieldInfo.mi.isThrough:
+		// fetch the pairs of pk from the through table first
+		qs := newQuerySet(o, fi.relThroughModelInfo).(*querySet)
+		qs.limit = -1
+		qs.cond = NewCondition().And(fi.reverseFieldInfo.name+ExprSep+"in", pks...)
+		var pairs []ParamsList
+		if _, err := qs.ValuesListWithCtx(ctx, &pairs, fi.reverseFieldInfo.name, fi.reverseFieldInfoTwo.name); err != nil {
+			return 0, err
+		}
+		for _, pair := range pairs {
+			key := ToStr(pair[1])
+			if _, ok := owners[key]; !ok {
+				relPks = append(relPks, pair[1])
+			}
+			owners[key] = append(owners[key], index[ToStr(pair[0])]...)
+		}
+	default:
+		relPks = pks
+		byFk = true
+	}
+
+	if len(relPks) == 0 {
+		return 0, nil
+	}
+
+	qs := newQuerySet(o, relMi).(*querySet)
+	qs.limit = -1
+	qs.relDepth = relDepth
+	if len(order) > 0 {
+		qs.orders = order_clause.ParseOrder(order)
+	}
+	if byFk {
+		qs.cond = NewCondition().And(fi.reverseFieldInfo.name+ExprSep+"in", relPks...)
+	} else {
+		qs.cond = NewCondition().And(relMi.fields.pk.name+ExprSep+"in", relPks...)
+	}
+
+	typ := inds[0].FieldByIndex(fi.fieldIndex).Type()
+	if typ.Kind() == reflect.Slice {
+		typ = typ.Elem()
+	}
+	rows := reflect.New(reflect.SliceOf(typ))
+	nums, err := qs.AllWithCtx(ctx, rows.Interface())
+	if err != nil {
+		return nums, err
+	}
+
+	if fi.fieldType == RelManyToMany || fi.fieldType == RelReverseMany {
+		for _, ind := range inds {
+			field := ind.FieldByIndex(fi.fieldIndex)
+			field.Set(reflect.MakeSlice(field.Type(), 0, 0))
+		}
+	}
+
+	rows = rows.Elem()
+	for i := 0; i < rows.Len(); i++ {
+		row := rows.Index(i)
+		var idx []int
+		if byFk {
+			fk := reflect.Indirect(row).FieldByIndex(fi.reverseFieldInfo.fieldIndex)
+			if fk.IsNil() {
+				continue
+			}
+			_, pk, _ := getExistPk(mi, reflect.Indirect(fk))
+			idx = index[ToStr(pk)]
+		} else {
+			_, pk, _ := getExistPk(relMi, reflect.Indirect(row))
+			idx = owners[ToStr(pk)]
+		}
+		for _, j := range idx {
+			field := inds[j].FieldByIndex(fi.fieldIndex)
+			if field.Kind() == reflect.Slice {
+				field.Set(reflect.Append(field, row))
+			} else {
+				field.Set(row)
+			}
+		}
+	}
+
+	return nums, nil
+}
+
 // get QuerySeter for related models to md model
 func (o *ormBase) queryRelated(md interface{}, name string) (*modelInfo, *fieldInfo, reflect.Value, *querySet) {
 	mi, ind := o.getPtrMiInd(md)
@@ -473,22 +948,29 @@ func (o *ormBase) getRelQs(md interface{}, mi *modelInfo, fi *fieldInfo) *queryS
 // table name can be string or struct.
 // e.g. QueryTable("user"), QueryTable(&user{}) or QueryTable((*User)(nil)),
 func (o *ormBase) QueryTable(ptrStructOrTableName interface{}) (qs QuerySeter) {
+	qs, err := o.QueryTableE(ptrStructOrTableName)
+	if err != nil {
+		panic(err)
+	}
+	return qs
+}
+
+// like QueryTable, but return ErrTableNotFound instead of panic
+// if the table is not registered.
+func (o *ormBase) QueryTableE(ptrStructOrTableName interface{}) (QuerySeter, error) {
 	var name string
 	if table, ok := ptrStructOrTableName.(string); ok {
 		name = nameStrategyMap[defaultNameStrategy](table)
 		if mi, ok := modelCache.get(name); ok {
-			qs = newQuerySet(o, mi)
+			return newQuerySet(o, mi), nil
 		}
 	} else {
 		name = getFullName(indirectType(reflect.TypeOf(ptrStructOrTableName)))
 		if mi, ok := modelCache.getByFullName(name); ok {
-			qs = newQuerySet(o, mi)
+			return newQuerySet(o, mi), nil
 		}
 	}
-	if qs == nil {
-		panic(fmt.Errorf("<Ormer.QueryTable> table name: `%s` not exists", name))
-	}
-	return qs
+	return nil, fmt.Errorf("%w: `%s`", ErrTableNotFound, name)
 }
 
 // NOTE: this method is deprecated, context parameter will not take effect.
@@ -499,7 +981,7 @@ func (o *ormBase) QueryTableWithCtx(_ context.Context, ptrStructOrTableName inte
 
 // return a raw query seter for raw sql string.
 func (o *ormBase) Raw(query string, args ...interface{}) RawSeter {
-	return o.RawWithCtx(context.Background(), query, args...)
+	return o.RawWithCtx(o.defaultCtx(), query, args...)
 }
 
 func (o *ormBase) RawWithCtx(_ context.Context, query string, args ...interface{}) RawSeter {
@@ -520,12 +1002,53 @@ func (o *ormBase) DBStats() *sql.DBStats {
 	return nil
 }
 
+// ping the database of current alias
+func (o *ormBase) Ping(ctx context.Context) error {
+	if o.alias == nil || o.alias.DB == nil || o.alias.DB.DB == nil {
+		return ErrNoDB
+	}
+	return o.alias.DB.DB.PingContext(ctx)
+}
+
+// return the last sql and args recorded in dry run mode
+func (o *ormBase) LastSQL() (string, []interface{}) {
+	if d, ok := o.db.(*dryRunQuerier); ok {
+		return d.last()
+	}
+	return "", nil
+}
+
 type orm struct {
 	ormBase
 }
 
 var _ Ormer = new(orm)
 
+// return a new Ormer which records every executed sql to recorder
+func (o *orm) WithSQLRecorder(recorder *SQLRecorder) Ormer {
+	return &orm{
+		ormBase: ormBase{
+			alias: o.alias,
+			db:    newSQLRecorderLog(o.alias, o.db, recorder),
+		},
+	}
+}
+
+// return the *sql.DB of the database
+func (o *orm) RawDB() *sql.DB {
+	return o.alias.DB.DB
+}
+
+// return a new Ormer which records the sql instead of executing it
+func (o *orm) DryRun() Ormer {
+	return &orm{
+		ormBase: ormBase{
+			alias: o.alias,
+			db:    new(dryRunQuerier),
+		},
+	}
+}
+
 func (o *orm) Begin() (TxOrmer, error) {
 	return o.BeginWithCtx(context.Background())
 }
@@ -539,26 +1062,51 @@ func (o *orm) BeginWithOpts(opts *sql.TxOptions) (TxOrmer, error) {
 }
 
 func (o *orm) BeginWithCtxAndOpts(ctx context.Context, opts *sql.TxOptions) (TxOrmer, error) {
+	if opts != nil && !o.alias.DbBaser.SupportIsolationLevel(opts.Isolation) {
+		return nil, fmt.Errorf("%w: `%s`", ErrIsolation, opts.Isolation)
+	}
+
 	tx, err := o.db.(txer).BeginTx(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
 
+	txDB := &TxDB{tx: tx}
 	_txOrm := &txOrm{
 		ormBase: ormBase{
 			alias: o.alias,
-			db:    &TxDB{tx: tx},
+			db:    txDB,
+			ctx:   ctx,
 		},
+		tx: txDB,
 	}
 
+	if o.alias.ScanGuard {
+		_txOrm.db = newScanGuard(o.alias, _txOrm.db)
+	}
 	if Debug {
 		_txOrm.db = newDbQueryLog(o.alias, _txOrm.db)
 	}
+	if recorder := getSQLRecorder(o.db); recorder != nil {
+		_txOrm.db = newSQLRecorderLog(o.alias, _txOrm.db, recorder)
+	}
 
 	var taskTxOrm TxOrmer = _txOrm
 	return taskTxOrm, nil
 }
 
+func (o *orm) BeginReadCommitted(ctx context.Context) (TxOrmer, error) {
+	return o.BeginWithCtxAndOpts(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+}
+
+func (o *orm) BeginRepeatableRead(ctx context.Context) (TxOrmer, error) {
+	return o.BeginWithCtxAndOpts(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+}
+
+func (o *orm) BeginSerializable(ctx context.Context) (TxOrmer, error) {
+	return o.BeginWithCtxAndOpts(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+}
+
 func (o *orm) DoTx(task func(ctx context.Context, txOrm TxOrmer) error) error {
 	return o.DoTxWithCtx(context.Background(), task)
 }
@@ -598,11 +1146,16 @@ func doTxTemplate(ctx context.Context, o TxBeginner, opts *sql.TxOptions,
 	taskTxOrm := _txOrm
 	err = task(ctx, taskTxOrm)
 	panicked = false
+	// don't commit if the context is done
+	if err == nil && ctx.Err() != nil {
+		err = fmt.Errorf("transaction rolled back: %w", ctx.Err())
+	}
 	return err
 }
 
 type txOrm struct {
 	ormBase
+	tx *TxDB
 }
 
 var _ TxOrmer = new(txOrm)
@@ -619,6 +1172,113 @@ func (t *txOrm) RollbackUnlessCommit() error {
 	return t.db.(txEnder).RollbackUnlessCommit()
 }
 
+func (t *txOrm) DB() *sql.Tx {
+	if t.tx == nil || t.tx.done {
+		return nil
+	}
+	return t.tx.tx
+}
+
+func (t *txOrm) Savepoint(name string) error {
+	return t.execSavepoint("Savepoint", "SAVEPOINT", name)
+}
+
+func (t *txOrm) RollbackTo(name string) error {
+	return t.execSavepoint("RollbackTo", "ROLLBACK TO SAVEPOINT", name)
+}
+
+func (t *txOrm) ReleaseSavepoint(name string) error {
+	return t.execSavepoint("ReleaseSavepoint", "RELEASE SAVEPOINT", name)
+}
+
+func (t *txOrm) ExecBatch(stmts []RawStmt) ([]sql.Result, error) {
+	ctx := t.defaultCtx()
+	if t.canMultiStatements(stmts) {
+		queries := make([]string, 0, len(stmts))
+		for _, stmt := range stmts {
+			queries = append(queries, strings.TrimRight(strings.TrimSpace(stmt.SQL), ";"))
+		}
+		res, err := t.db.ExecContext(ctx, strings.Join(queries, ";"))
+		if err != nil {
+			return nil, err
+		}
+		return []sql.Result{&JoinedResult{Result: res, Stmts: len(stmts)}}, nil
+	}
+
+	results := make([]sql.Result, 0, len(stmts))
+	for i, stmt := range stmts {
+		query := stmt.SQL
+		t.alias.DbBaser.ReplaceMarks(&query)
+		args := getFlatParams(nil, stmt.Args, t.alias.TZ)
+		res, err := t.db.ExecContext(ctx, query, args...)
+		if err != nil {
+			return results, fmt.Errorf("<TxOrmer.ExecBatch> statement %d: %w", i, err)
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// the statements can be sent in one round trip only if the mysql driver enables multiStatements,
+// and the args can't be bound to the joined statements
+func (t *txOrm) canMultiStatements(stmts []RawStmt) bool {
+	if len(stmts) < 2 || (t.alias.Driver != DRMySQL && t.alias.Driver != DRTiDB) {
+		return false
+	}
+	if !isMultiStatementsDSN(t.alias.DataSource) {
+		return false
+	}
+	for _, stmt := range stmts {
+		if len(stmt.Args) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// check whether the mysql data source enables multiStatements,
+// the value is parsed like the driver does, e.g. multiStatements=1 or multiStatements=True
+func isMultiStatementsDSN(dsn string) bool {
+	i := strings.LastIndex(dsn, "?")
+	if i < 0 {
+		return false
+	}
+	for _, param := range strings.Split(dsn[i+1:], "&") {
+		kv := strings.SplitN(param, "=", 2)
+		if len(kv) != 2 || kv[0] != "multiStatements" {
+			continue
+		}
+		enabled, err := strconv.ParseBool(kv[1])
+		return err == nil && enabled
+	}
+	return false
+}
+
+// execute the savepoint statement stmt on the savepoint name
+func (t *txOrm) execSavepoint(method string, stmt string, name string) error {
+	if !isSavepointName(name) {
+		return fmt.Errorf("<TxOrmer.%s> invalid savepoint name `%s`", method, name)
+	}
+	_, err := t.db.ExecContext(t.defaultCtx(), stmt+" "+name)
+	return err
+}
+
+// the savepoint name is written into the sql, so only the identifiers are allowed
+func isSavepointName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		switch {
+		case c == '_', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
 // NewOrm create new orm
 func NewOrm() Ormer {
 	BootStrap() // execute only once
@@ -647,10 +1307,12 @@ func newDBWithAlias(al *alias) Ormer {
 	o := new(orm)
 	o.alias = al
 
+	o.db = al.DB
+	if al.ScanGuard {
+		o.db = newScanGuard(al, o.db)
+	}
 	if Debug {
-		o.db = newDbQueryLog(al, al.DB)
-	} else {
-		o.db = al.DB
+		o.db = newDbQueryLog(al, o.db)
 	}
 
 	if len(globalFilterChains) > 0 {
@@ -35,12 +35,15 @@ type condValue struct {
 	isCond bool
 	isRaw  bool
 	sql    string
+	// exprs is the relation and cond is the condition of the EXISTS subquery
+	isExists bool
 }
 
 // Condition struct.
 // work for WHERE conditions.
 type Condition struct {
-	params []condValue
+	params     []condValue
+	allowEmpty bool
 }
 
 // NewCondition return new condition struct
@@ -49,12 +52,31 @@ func NewCondition() *Condition {
 	return c
 }
 
-// Raw add raw sql to condition
-func (c Condition) Raw(expr string, sql string) *Condition {
+// Raw add raw sql to condition, args are bound to the placeholders of sql.
+// if expr is empty, sql is used as a whole predicate, e.g. Raw("", "data @> ?", value)
+func (c Condition) Raw(expr string, sql string, args ...interface{}) *Condition {
 	if len(sql) == 0 {
 		panic(fmt.Errorf("<Condition.Raw> sql cannot empty"))
 	}
-	c.params = append(c.params, condValue{exprs: strings.Split(expr, ExprSep), sql: sql, isRaw: true})
+	var exprs []string
+	if expr != "" {
+		exprs = strings.Split(expr, ExprSep)
+	}
+	c.params = append(c.params, condValue{exprs: exprs, args: args, sql: sql, isRaw: true})
+	return &c
+}
+
+// OrRaw add OR raw sql to condition, it has the same usage as Raw.
+// e.g. And("status", 1).OrRaw("", "created > NOW() - INTERVAL ? DAY", 7)
+func (c Condition) OrRaw(expr string, sql string, args ...interface{}) *Condition {
+	if len(sql) == 0 {
+		panic(fmt.Errorf("<Condition.OrRaw> sql cannot empty"))
+	}
+	var exprs []string
+	if expr != "" {
+		exprs = strings.Split(expr, ExprSep)
+	}
+	c.params = append(c.params, condValue{exprs: exprs, args: args, sql: sql, isRaw: true, isOr: true})
 	return &c
 }
 
@@ -146,11 +168,27 @@ func (c *Condition) OrNotCond(cond *Condition) *Condition {
 	return c
 }
 
+// andNotExists add the NOT EXISTS subquery on the related rows of rel to condition
+func (c Condition) andNotExists(rel string, cond *Condition) *Condition {
+	if rel == "" {
+		panic(fmt.Errorf("<Condition.andNotExists> rel cannot empty"))
+	}
+	c.params = append(c.params, condValue{exprs: []string{rel}, cond: cond, isNot: true, isExists: true})
+	return &c
+}
+
 // IsEmpty check the condition arguments are empty or not.
 func (c *Condition) IsEmpty() bool {
 	return len(c.params) == 0
 }
 
+// AllowEmptyCondition allow the condition to be empty for the delete,
+// which deletes all the rows of the table.
+func (c Condition) AllowEmptyCondition() *Condition {
+	c.allowEmpty = true
+	return &c
+}
+
 // clone clone a condition
 func (c Condition) clone() *Condition {
 	params := make([]condValue, len(c.params))
@@ -0,0 +1,121 @@
+// Copyright 2020 beego
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// count the placeholders of condition
+func countCondArgs(cond *Condition) int {
+	if cond == nil {
+		return 0
+	}
+	num := 0
+	for _, p := range cond.params {
+		if p.isCond || p.isExists {
+			num += countCondArgs(p.cond)
+		} else {
+			num += len(getFlatParams(nil, p.args, DefaultTimeLoc))
+		}
+	}
+	return num
+}
+
+// split the condition with a huge IN list into the conditions which don't exceed
+// the max placeholders of the driver, nil is returned if it doesn't need to split.
+// only the IN list joined by AND at the top level can be split.
+func (o *querySet) splitInChunks() ([]*Condition, error) {
+	limit := getMaxPlaceholders(o.orm.alias.Driver)
+	total := countCondArgs(o.cond)
+	if limit <= 0 || total <= limit {
+		return nil, nil
+	}
+
+	index, values := -1, []interface{}(nil)
+	for i, p := range o.cond.params {
+		if p.isOr {
+			return nil, nil
+		}
+		if p.isNot || p.isCond || p.isExists || p.isRaw || p.exprs[len(p.exprs)-1] != "in" {
+			continue
+		}
+		if args := getFlatParams(nil, p.args, DefaultTimeLoc); len(args) > len(values) {
+			index, values = i, args
+		}
+	}
+	size := limit - (total - len(values))
+	if index < 0 || size <= 0 {
+		return nil, nil
+	}
+	if o.limit != 0 || o.offset != 0 {
+		return nil, fmt.Errorf("<QuerySeter.All> the IN list of %d values exceeds the max placeholders %d, "+
+			"it cannot be split into chunks with limit or offset", len(values), limit)
+	}
+	// the rows of chunks are appended, so the clauses on the whole rows can't apply
+	if len(o.orders) > 0 || o.orderRandom || len(o.groups) > 0 || o.distinct || o.aggregate != "" {
+		return nil, fmt.Errorf("<QuerySeter.All> the IN list of %d values exceeds the max placeholders %d, "+
+			"it cannot be split into chunks with order, group, distinct or aggregate", len(values), limit)
+	}
+
+	// the duplicate values in different chunks would read the same row twice
+	seen := make(map[string]bool, len(values))
+	distinct := values[:0:0]
+	for _, v := range values {
+		key := fmt.Sprintf("%T:%v", v, v)
+		if t, ok := v.(time.Time); ok {
+			key = t.UTC().Format(time.RFC3339Nano)
+		}
+		if !seen[key] {
+			seen[key] = true
+			distinct = append(distinct, v)
+		}
+	}
+	values = distinct
+
+	conds := make([]*Condition, 0, len(values)/size+1)
+	for start := 0; start < len(values); start += size {
+		end := start + size
+		if end > len(values) {
+			end = len(values)
+		}
+		cond := o.cond.clone()
+		cond.params[index].args = []interface{}{values[start:end]}
+		conds = append(conds, cond)
+	}
+	return conds, nil
+}
+
+// query the chunks of condition one by one and append the rows to container
+func (o *querySet) readInChunks(ctx context.Context, conds []*Condition, container interface{}, cols []string) (int64, error) {
+	val := reflect.ValueOf(container)
+	ind := reflect.Indirect(val)
+	rows := reflect.MakeSlice(ind.Type(), 0, 0)
+	var cnt int64
+	for _, cond := range conds {
+		chunk := reflect.New(ind.Type())
+		num, err := o.orm.alias.DbBaser.ReadBatch(ctx, o.querier(), o, o.mi, scopeCond(ctx, o.mi, cond), chunk.Interface(), o.getTZ(), cols)
+		if err != nil {
+			return cnt, err
+		}
+		rows = reflect.AppendSlice(rows, chunk.Elem())
+		cnt += num
+	}
+	ind.Set(rows)
+	return cnt, nil
+}
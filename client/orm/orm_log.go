@@ -17,10 +17,13 @@ package orm
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -39,7 +42,42 @@ func NewLog(out io.Writer) *Log {
 	return d
 }
 
-func debugLogQueies(alias *alias, operaton, query string, t time.Time, err error, args ...interface{}) {
+// Logger logs the queries of Ormers in debug mode
+type Logger interface {
+	LogQuery(ctx context.Context, query string, args []interface{}, dur time.Duration, err error)
+}
+
+// the Logger is held by the struct, as atomic.Value only stores the values of the same type
+type loggerHolder struct {
+	Logger
+}
+
+// the logger set by SetLogger, nil if there is none
+var queryLogger atomic.Value
+
+// SetLogger replace the default query log of debug mode with logger,
+// DebugLog and LogFunc are not used for the queries after that.
+// use nil to restore the default one.
+func SetLogger(logger Logger) {
+	queryLogger.Store(loggerHolder{logger})
+}
+
+func getLogger() Logger {
+	holder, _ := queryLogger.Load().(loggerHolder)
+	return holder.Logger
+}
+
+func debugLogQueies(ctx context.Context, alias *alias, operaton, query string, t time.Time, err error, args ...interface{}) {
+	args = redactLogArgs(ctx, args)
+	// the query is logged already
+	var qe *QueryError
+	if errors.As(err, &qe) {
+		err = qe.Err
+	}
+	if logger := getLogger(); logger != nil {
+		logger.LogQuery(ctx, query, args, time.Since(t), err)
+		return
+	}
 	logMap := make(map[string]interface{})
 	sub := time.Since(t) / 1e5
 	elsp := float64(int(sub)) / 10.0
@@ -80,7 +118,7 @@ var _ stmtQuerier = new(stmtQueryLog)
 func (d *stmtQueryLog) Close() error {
 	a := time.Now()
 	err := d.stmt.Close()
-	debugLogQueies(d.alias, "st.Close", d.query, a, err)
+	debugLogQueies(context.Background(), d.alias, "st.Close", d.query, a, err)
 	return err
 }
 
@@ -91,7 +129,7 @@ func (d *stmtQueryLog) Exec(args ...interface{}) (sql.Result, error) {
 func (d *stmtQueryLog) ExecContext(ctx context.Context, args ...interface{}) (sql.Result, error) {
 	a := time.Now()
 	res, err := d.stmt.ExecContext(ctx, args...)
-	debugLogQueies(d.alias, "st.Exec", d.query, a, err, args...)
+	debugLogQueies(ctx, d.alias, "st.Exec", d.query, a, err, args...)
 	return res, err
 }
 
@@ -102,7 +140,7 @@ func (d *stmtQueryLog) Query(args ...interface{}) (*sql.Rows, error) {
 func (d *stmtQueryLog) QueryContext(ctx context.Context, args ...interface{}) (*sql.Rows, error) {
 	a := time.Now()
 	res, err := d.stmt.QueryContext(ctx, args...)
-	debugLogQueies(d.alias, "st.Query", d.query, a, err, args...)
+	debugLogQueies(ctx, d.alias, "st.Query", d.query, a, err, args...)
 	return res, err
 }
 
@@ -113,7 +151,7 @@ func (d *stmtQueryLog) QueryRow(args ...interface{}) *sql.Row {
 func (d *stmtQueryLog) QueryRowContext(ctx context.Context, args ...interface{}) *sql.Row {
 	a := time.Now()
 	res := d.stmt.QueryRow(args...)
-	debugLogQueies(d.alias, "st.QueryRow", d.query, a, nil, args...)
+	debugLogQueies(ctx, d.alias, "st.QueryRow", d.query, a, nil, args...)
 	return res
 }
 
@@ -128,10 +166,12 @@ func newStmtQueryLog(alias *alias, stmt stmtQuerier, query string) stmtQuerier {
 // database query logger struct.
 // if dev mode, use dbQueryLog, or use dbQuerier.
 type dbQueryLog struct {
-	alias *alias
-	db    dbQuerier
-	tx    txer
-	txe   txEnder
+	alias    *alias
+	db       dbQuerier
+	tx       txer
+	txe      txEnder
+	debug    bool
+	recorder *SQLRecorder
 }
 
 var (
@@ -140,6 +180,15 @@ var (
 	_ txEnder   = new(dbQueryLog)
 )
 
+func (d *dbQueryLog) log(ctx context.Context, operation, query string, t time.Time, err error, args ...interface{}) {
+	if d.recorder != nil {
+		d.recorder.record(query, args, time.Since(t))
+	}
+	if d.debug {
+		debugLogQueies(ctx, d.alias, operation, query, t, err, args...)
+	}
+}
+
 func (d *dbQueryLog) Prepare(query string) (*sql.Stmt, error) {
 	return d.PrepareContext(context.Background(), query)
 }
@@ -147,7 +196,7 @@ func (d *dbQueryLog) Prepare(query string) (*sql.Stmt, error) {
 func (d *dbQueryLog) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
 	a := time.Now()
 	stmt, err := d.db.PrepareContext(ctx, query)
-	debugLogQueies(d.alias, "db.Prepare", query, a, err)
+	d.log(ctx, "db.Prepare", query, a, err)
 	return stmt, err
 }
 
@@ -158,7 +207,7 @@ func (d *dbQueryLog) Exec(query string, args ...interface{}) (sql.Result, error)
 func (d *dbQueryLog) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
 	a := time.Now()
 	res, err := d.db.ExecContext(ctx, query, args...)
-	debugLogQueies(d.alias, "db.Exec", query, a, err, args...)
+	d.log(ctx, "db.Exec", query, a, err, args...)
 	return res, err
 }
 
@@ -169,7 +218,7 @@ func (d *dbQueryLog) Query(query string, args ...interface{}) (*sql.Rows, error)
 func (d *dbQueryLog) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
 	a := time.Now()
 	res, err := d.db.QueryContext(ctx, query, args...)
-	debugLogQueies(d.alias, "db.Query", query, a, err, args...)
+	d.log(ctx, "db.Query", query, a, err, args...)
 	return res, err
 }
 
@@ -180,7 +229,7 @@ func (d *dbQueryLog) QueryRow(query string, args ...interface{}) *sql.Row {
 func (d *dbQueryLog) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
 	a := time.Now()
 	res := d.db.QueryRowContext(ctx, query, args...)
-	debugLogQueies(d.alias, "db.QueryRow", query, a, nil, args...)
+	d.log(ctx, "db.QueryRow", query, a, nil, args...)
 	return res
 }
 
@@ -191,28 +240,28 @@ func (d *dbQueryLog) Begin() (*sql.Tx, error) {
 func (d *dbQueryLog) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
 	a := time.Now()
 	tx, err := d.db.(txer).BeginTx(ctx, opts)
-	debugLogQueies(d.alias, "db.BeginTx", "START TRANSACTION", a, err)
+	d.log(ctx, "db.BeginTx", "START TRANSACTION", a, err)
 	return tx, err
 }
 
 func (d *dbQueryLog) Commit() error {
 	a := time.Now()
 	err := d.db.(txEnder).Commit()
-	debugLogQueies(d.alias, "tx.Commit", "COMMIT", a, err)
+	d.log(context.Background(), "tx.Commit", "COMMIT", a, err)
 	return err
 }
 
 func (d *dbQueryLog) Rollback() error {
 	a := time.Now()
 	err := d.db.(txEnder).Rollback()
-	debugLogQueies(d.alias, "tx.Rollback", "ROLLBACK", a, err)
+	d.log(context.Background(), "tx.Rollback", "ROLLBACK", a, err)
 	return err
 }
 
 func (d *dbQueryLog) RollbackUnlessCommit() error {
 	a := time.Now()
 	err := d.db.(txEnder).RollbackUnlessCommit()
-	debugLogQueies(d.alias, "tx.RollbackUnlessCommit", "ROLLBACK UNLESS COMMIT", a, err)
+	d.log(context.Background(), "tx.RollbackUnlessCommit", "ROLLBACK UNLESS COMMIT", a, err)
 	return err
 }
 
@@ -224,5 +273,69 @@ func newDbQueryLog(alias *alias, db dbQuerier) dbQuerier {
 	d := new(dbQueryLog)
 	d.alias = alias
 	d.db = db
+	d.debug = true
 	return d
 }
+
+// SQLRecord is a sql executed by the Ormer which the SQLRecorder is attached to
+type SQLRecord struct {
+	SQL  string
+	Args []interface{}
+	Dur  time.Duration
+}
+
+// SQLRecorder records the sql executed by Ormers, it's designed for tests.
+// for example:
+//
+//	recorder := orm.NewSQLRecorder()
+//	o := orm.NewOrm().WithSQLRecorder(recorder)
+//	o.Read(&user)
+//	records := recorder.Records()
+type SQLRecorder struct {
+	mux     sync.Mutex
+	records []SQLRecord
+}
+
+// NewSQLRecorder create an empty SQLRecorder
+func NewSQLRecorder() *SQLRecorder {
+	return &SQLRecorder{}
+}
+
+func (r *SQLRecorder) record(query string, args []interface{}, dur time.Duration) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.records = append(r.records, SQLRecord{SQL: query, Args: args, Dur: dur})
+}
+
+// Records return a copy of the recorded sql in executing order
+func (r *SQLRecorder) Records() []SQLRecord {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	res := make([]SQLRecord, len(r.records))
+	copy(res, r.records)
+	return res
+}
+
+// Reset remove all the recorded sql
+func (r *SQLRecorder) Reset() {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.records = nil
+}
+
+// wrap db with a query logger which only records sql to the recorder
+func newSQLRecorderLog(alias *alias, db dbQuerier, recorder *SQLRecorder) dbQuerier {
+	d := new(dbQueryLog)
+	d.alias = alias
+	d.db = db
+	d.recorder = recorder
+	return d
+}
+
+// get the recorder attached to db, or nil
+func getSQLRecorder(db dbQuerier) *SQLRecorder {
+	if d, ok := db.(*dbQueryLog); ok {
+		return d.recorder
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2020 beego
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orm
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// LogRedacted replaces the values of the redacted columns in the query log
+const LogRedacted = "***"
+
+// LogRedactor returns the value to log for the arg bound to table.col,
+// table or col is empty if it's unknown, e.g. the args of Raw.
+type LogRedactor func(table, col string, val interface{}) interface{}
+
+// the registered LogRedactor, nil if there is none
+var logRedactor atomic.Value
+
+// RegisterLogRedactor set the redactor of the query log args in debug mode.
+// the fields with tag `orm:"log_redact"` are always logged as LogRedacted.
+// it only changes the log, the args of the query are not affected.
+//
+//	orm.RegisterLogRedactor(func(table, col string, val interface{}) interface{} {
+//		if col == "token" {
+//			return orm.LogRedacted
+//		}
+//		return val
+//	})
+func RegisterLogRedactor(redactor LogRedactor) {
+	logRedactor.Store(redactor)
+}
+
+func getLogRedactor() LogRedactor {
+	redactor, _ := logRedactor.Load().(LogRedactor)
+	return redactor
+}
+
+// the key of the fields which the args of query are bound to
+type argFieldsKey struct{}
+
+// attach the fields which the args of query are bound to, the sql builder knows them,
+// so the args are redacted by the fields instead of parsing the sql.
+// the field is nil for the arg which isn't bound to a column, e.g. the limit.
+func withArgFields(ctx context.Context, fields []*fieldInfo) context.Context {
+	return context.WithValue(ctx, argFieldsKey{}, fields)
+}
+
+// get the fields of columns cols in model mi, nil for the unknown column
+func columnFields(mi *modelInfo, cols []string) []*fieldInfo {
+	fields := make([]*fieldInfo, len(cols))
+	for i, col := range cols {
+		fields[i] = mi.fields.GetByColumn(col)
+	}
+	return fields
+}
+
+// get the fields of the values of a multi-row insert, the columns cols are repeated for each row
+func insertArgFields(mi *modelInfo, cols []string, values int) []*fieldInfo {
+	if len(cols) == 0 {
+		return nil
+	}
+	fields := make([]*fieldInfo, 0, values)
+	for len(fields) < values {
+		fields = append(fields, columnFields(mi, cols)...)
+	}
+	return fields[:values]
+}
+
+// redact the args of query for logging by the fields attached to ctx, args is not changed.
+// the args are unknown if they don't match the fields, e.g. Raw.
+func redactLogArgs(ctx context.Context, args []interface{}) []interface{} {
+	if len(args) == 0 {
+		return args
+	}
+	fields, _ := ctx.Value(argFieldsKey{}).([]*fieldInfo)
+	if len(fields) != len(args) {
+		fields = nil
+	}
+	redactor := getLogRedactor()
+	if fields == nil && redactor == nil {
+		return args
+	}
+	res := make([]interface{}, len(args))
+	copy(res, args)
+	for i := range res {
+		var fi *fieldInfo
+		if fields != nil {
+			fi = fields[i]
+		}
+		if fi != nil && fi.logRedact {
+			res[i] = LogRedacted
+			continue
+		}
+		if redactor == nil {
+			continue
+		}
+		if fi != nil && fi.mi != nil {
+			res[i] = redactor(fi.mi.table, fi.column, res[i])
+		} else {
+			res[i] = redactor("", "", res[i])
+		}
+	}
+	return res
+}
@@ -0,0 +1,139 @@
+// Copyright 2020 beego
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// a prepared select statement of querySet
+type preparedQuerySet struct {
+	qs     *querySet
+	query  string
+	nArgs  int
+	stmt   stmtQuerier
+	closed bool
+}
+
+var _ PreparedQuerySeter = new(preparedQuerySet)
+
+// database querier which queries the prepared statement with the bound args
+// instead of the args of the conditions.
+type preparedQuerier struct {
+	dbQuerier
+	query string
+	stmt  stmtQuerier
+	args  []interface{}
+}
+
+func (p *preparedQuerier) QueryContext(ctx context.Context, query string, _ ...interface{}) (*sql.Rows, error) {
+	if query != p.query {
+		return nil, fmt.Errorf("<PreparedQuerySeter> the query is changed after prepared: %s", query)
+	}
+	return p.stmt.QueryContext(ctx, p.args...)
+}
+
+func (o *preparedQuerySet) All(container interface{}, args ...interface{}) (int64, error) {
+	return o.AllWithCtx(o.qs.orm.defaultCtx(), container, args...)
+}
+
+func (o *preparedQuerySet) AllWithCtx(ctx context.Context, container interface{}, args ...interface{}) (int64, error) {
+	q, err := o.querier(args)
+	if err != nil {
+		return 0, err
+	}
+	return o.qs.orm.alias.DbBaser.ReadBatch(ctx, q, o.qs, o.qs.mi, o.qs.cond, container, o.qs.getTZ(), nil)
+}
+
+func (o *preparedQuerySet) One(container interface{}, args ...interface{}) error {
+	return o.OneWithCtx(o.qs.orm.defaultCtx(), container, args...)
+}
+
+func (o *preparedQuerySet) OneWithCtx(ctx context.Context, container interface{}, args ...interface{}) error {
+	q, err := o.querier(args)
+	if err != nil {
+		return err
+	}
+	num, err := o.qs.orm.alias.DbBaser.ReadBatch(ctx, q, o.qs, o.qs.mi, o.qs.cond, container, o.qs.getTZ(), nil)
+	if err != nil {
+		return err
+	}
+	if num == 0 {
+		return ErrNoRows
+	}
+	if num > 1 {
+		return ErrMultiRows
+	}
+	return nil
+}
+
+// get the querier binding args to the statement
+func (o *preparedQuerySet) querier(args []interface{}) (dbQuerier, error) {
+	if o.closed {
+		return nil, ErrStmtClosed
+	}
+	params := getFlatParams(nil, args, o.qs.getTZ())
+	if len(params) != o.nArgs {
+		return nil, fmt.Errorf("<PreparedQuerySeter> the statement needs %d args but got %d", o.nArgs, len(params))
+	}
+	return &preparedQuerier{dbQuerier: o.qs.orm.db, query: o.query, stmt: o.stmt, args: params}, nil
+}
+
+// close the prepared statement
+func (o *preparedQuerySet) Close() error {
+	if o.closed {
+		return ErrStmtClosed
+	}
+	o.closed = true
+	return o.stmt.Close()
+}
+
+// prepare the select statement of qs, the sql is recorded by the dry run querier
+// which doesn't execute it.
+func newPreparedQuerySet(ctx context.Context, qs *querySet) (PreparedQuerySeter, error) {
+	if qs.total != nil {
+		return nil, fmt.Errorf("<QuerySeter.Prepare> the total count cannot be prepared")
+	}
+	// the scope args would be bound by the caller, who can query the rows out of scope
+	if qs.mi.queryScoped && getQueryScoper() != nil {
+		return nil, fmt.Errorf("<QuerySeter.Prepare> the model `%s` is scoped by the query scoper, it cannot be prepared", qs.mi.fullName)
+	}
+	cp := *qs
+	p := &preparedQuerySet{qs: &cp}
+
+	container := reflect.New(reflect.SliceOf(qs.mi.addrField.Type())).Interface()
+	dry := new(dryRunQuerier)
+	if _, err := qs.orm.alias.DbBaser.ReadBatch(ctx, dry, p.qs, qs.mi, p.qs.cond, container, qs.getTZ(), nil); err != nil && !errors.Is(err, ErrDryRun) {
+		return nil, err
+	}
+	var args []interface{}
+	p.query, args = dry.last()
+	p.nArgs = len(args)
+
+	st, err := qs.querier().PrepareContext(ctx, p.query)
+	if err != nil {
+		return nil, err
+	}
+	if Debug {
+		p.stmt = newStmtQueryLog(qs.orm.alias, st, p.query)
+	} else {
+		p.stmt = st
+	}
+	return p, nil
+}
@@ -36,7 +36,7 @@ type queryM2M struct {
 //
 // make sure the relation is defined in post model struct tag.
 func (o *queryM2M) Add(mds ...interface{}) (int64, error) {
-	return o.AddWithCtx(context.Background(), mds...)
+	return o.AddWithCtx(o.qs.orm.defaultCtx(), mds...)
 }
 
 func (o *queryM2M) AddWithCtx(ctx context.Context, mds ...interface{}) (int64, error) {
@@ -108,19 +108,19 @@ func (o *queryM2M) AddWithCtx(ctx context.Context, mds ...interface{}) (int64, e
 
 // remove models following the origin model relationship
 func (o *queryM2M) Remove(mds ...interface{}) (int64, error) {
-	return o.RemoveWithCtx(context.Background(), mds...)
+	return o.RemoveWithCtx(o.qs.orm.defaultCtx(), mds...)
 }
 
 func (o *queryM2M) RemoveWithCtx(ctx context.Context, mds ...interface{}) (int64, error) {
 	fi := o.fi
 	qs := o.qs.Filter(fi.reverseFieldInfo.name, o.md)
 
-	return qs.Filter(fi.reverseFieldInfoTwo.name+ExprSep+"in", mds).Delete()
+	return qs.Filter(fi.reverseFieldInfoTwo.name+ExprSep+"in", mds).DeleteWithCtx(ctx)
 }
 
 // check model is existed in relationship of origin model
 func (o *queryM2M) Exist(md interface{}) bool {
-	return o.ExistWithCtx(context.Background(), md)
+	return o.ExistWithCtx(o.qs.orm.defaultCtx(), md)
 }
 
 func (o *queryM2M) ExistWithCtx(ctx context.Context, md interface{}) bool {
@@ -131,7 +131,7 @@ func (o *queryM2M) ExistWithCtx(ctx context.Context, md interface{}) bool {
 
 // clean all models in related of origin model
 func (o *queryM2M) Clear() (int64, error) {
-	return o.ClearWithCtx(context.Background())
+	return o.ClearWithCtx(o.qs.orm.defaultCtx())
 }
 
 func (o *queryM2M) ClearWithCtx(ctx context.Context) (int64, error) {
@@ -141,7 +141,7 @@ func (o *queryM2M) ClearWithCtx(ctx context.Context) (int64, error) {
 
 // count all related models of origin model
 func (o *queryM2M) Count() (int64, error) {
-	return o.CountWithCtx(context.Background())
+	return o.CountWithCtx(o.qs.orm.defaultCtx())
 }
 
 func (o *queryM2M) CountWithCtx(ctx context.Context) (int64, error) {
@@ -17,6 +17,11 @@ package orm
 import (
 	"context"
 	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/beego/beego/v2/client/orm/clauses/order_clause"
 	"github.com/beego/beego/v2/client/orm/hints"
@@ -63,26 +68,75 @@ func ColValue(opt operator, value interface{}) interface{} {
 	return val
 }
 
+type colRef struct {
+	name string
+}
+
+// Col references another column of the same table in an update. e.g Nickname = Name. usage:
+// 	Params{
+// 		"Nickname": Col("Name"),
+// 	}
+func Col(name string) interface{} {
+	return colRef{name: name}
+}
+
+// column value set by QuerySeter.Set
+type updateSet struct {
+	col   string
+	value interface{}
+}
+
+// string aggregation added by QuerySeter.GroupConcat
+type groupConcat struct {
+	col   string
+	alias string
+	sep   string
+}
+
 // real query struct
 type querySet struct {
-	mi        *modelInfo
-	cond      *Condition
-	related   []string
-	relDepth  int
-	limit     int64
-	offset    int64
-	groups    []string
-	orders    []*order_clause.Order
-	distinct  bool
-	forUpdate bool
-	useIndex  int
-	indexes   []string
-	orm       *ormBase
-	aggregate string
+	mi          *modelInfo
+	cond        *Condition
+	related     []string
+	relDepth    int
+	selectCols  []string
+	limit       int64
+	offset      int64
+	groups      []string
+	havings     []havingCond
+	sets        []updateSet
+	concats     []groupConcat
+	noStmtCache bool
+	orders      []*order_clause.Order
+	orderRandom bool
+	distinct    bool
+	forUpdate   bool
+	noWait      bool
+	useIndex    int
+	indexes     []string
+	orm         *ormBase
+	total       *int64 // read the total count of rows by window function into it
+	aggregate   string
+	table       string
+	tz          *time.Location
 }
 
 var _ QuerySeter = new(querySet)
 
+// set the time zone to convert the times of the query, instead of the database time zone.
+func (o querySet) InLocation(loc *time.Location) QuerySeter {
+	o.tz = loc
+	return &o
+}
+
+// get the time zone of the query
+func (o *querySet) getTZ() *time.Location {
+	if o.tz != nil {
+		return o.tz
+	}
+	return o.orm.alias.TZ
+}
+
 // add condition expression to QuerySeter.
 func (o querySet) Filter(expr string, args ...interface{}) QuerySeter {
 	if o.cond == nil {
@@ -92,15 +146,63 @@ func (o querySet) Filter(expr string, args ...interface{}) QuerySeter {
 	return &o
 }
 
+// add condition expression to querySeter with OR.
+func (o querySet) FilterOr(expr string, args ...interface{}) QuerySeter {
+	if o.cond == nil {
+		o.cond = NewCondition()
+	}
+	o.cond = o.cond.Or(expr, args...)
+	return &o
+}
+
 // add raw sql to querySeter.
-func (o querySet) FilterRaw(expr string, sql string) QuerySeter {
+func (o querySet) FilterRaw(expr string, sql string, args ...interface{}) QuerySeter {
 	if o.cond == nil {
 		o.cond = NewCondition()
 	}
-	o.cond = o.cond.Raw(expr, sql)
+	o.cond = o.cond.Raw(expr, sql, args...)
 	return &o
 }
 
+// add IS NULL condition on the field to querySeter.
+func (o querySet) FilterIsNull(field string) QuerySeter {
+	return o.filterNull(field, true)
+}
+
+// add IS NOT NULL condition on the field to querySeter.
+func (o querySet) FilterIsNotNull(field string) QuerySeter {
+	return o.filterNull(field, false)
+}
+
+func (o querySet) filterNull(field string, isNull bool) QuerySeter {
+	if o.cond == nil {
+		o.cond = NewCondition()
+	}
+	o.cond = o.cond.And(field+ExprSep+"isnull", isNull)
+	return &o
+}
+
+// add full text search condition on the columns to querySeter.
+func (o querySet) FilterFullText(cols []string, query string) QuerySeter {
+	if len(cols) == 0 {
+		panic(fmt.Errorf("<QuerySeter.FilterFullText> cols cannot empty"))
+	}
+	Q := o.orm.alias.DbBaser.TableQuote()
+	columns := make([]string, 0, len(cols))
+	for _, col := range cols {
+		fi, ok := o.mi.fields.GetByAny(col)
+		if !ok || !fi.dbcol {
+			panic(fmt.Errorf("<QuerySeter.FilterFullText> wrong field/column name `%s`", col))
+		}
+		columns = append(columns, fmt.Sprintf("T0.%s%s%s", Q, fi.column, Q))
+	}
+	sql, err := o.orm.alias.DbBaser.GenerateFullTextSQL(columns)
+	if err != nil {
+		panic(fmt.Errorf("<QuerySeter.FilterFullText> %w", err))
+	}
+	return o.FilterRaw("", sql, query)
+}
+
 // add NOT condition to querySeter.
 func (o querySet) Exclude(expr string, args ...interface{}) QuerySeter {
 	if o.cond == nil {
@@ -110,21 +212,141 @@ func (o querySet) Exclude(expr string, args ...interface{}) QuerySeter {
 	return &o
 }
 
+// add NOT EXISTS subquery on the related rows of rel to querySeter.
+func (o querySet) ExcludeExists(rel string, cond *Condition) QuerySeter {
+	if o.cond == nil {
+		o.cond = NewCondition()
+	}
+	o.cond = o.cond.andNotExists(rel, cond)
+	return &o
+}
+
+// add condition to querySeter with AND.
+func (o querySet) FilterCond(cond *Condition) QuerySeter {
+	if cond == nil || cond.IsEmpty() {
+		return &o
+	}
+	if o.cond == nil {
+		o.cond = NewCondition()
+	}
+	o.cond = o.cond.AndCond(cond)
+	return &o
+}
+
+// add the condition to querySeter with OR.
+func (o querySet) FilterOrCond(cond *Condition) QuerySeter {
+	if cond == nil || cond.IsEmpty() {
+		return &o
+	}
+	if o.cond == nil {
+		o.cond = NewCondition()
+	}
+	o.cond = o.cond.OrCond(cond)
+	return &o
+}
+
+// add NOT condition to querySeter with AND.
+func (o querySet) ExcludeCond(cond *Condition) QuerySeter {
+	if cond == nil || cond.IsEmpty() {
+		return &o
+	}
+	if o.cond == nil {
+		o.cond = NewCondition()
+	}
+	o.cond = o.cond.AndNotCond(cond)
+	return &o
+}
+
+// add keyset pagination condition, rows after the cursor value are queried,
+// and the rows are ordered by the cursor columns.
+// col can be several columns separated by comma for composite cursors,
+// then value must be a []interface{} with the same length.
+func (o querySet) SeekGt(col string, value interface{}) QuerySeter {
+	cols := splitCursorCols(col)
+	values := []interface{}{value}
+	if len(cols) > 1 {
+		vs, ok := value.([]interface{})
+		if !ok || len(vs) != len(cols) {
+			panic(fmt.Errorf("<QuerySeter.SeekGt> composite cursor `%s` need %d values", col, len(cols)))
+		}
+		values = vs
+	}
+
+	// (c1 > v1) OR (c1 = v1 AND c2 > v2) OR ...
+	seek := NewCondition()
+	for i := range cols {
+		cond := NewCondition()
+		for j := 0; j < i; j++ {
+			cond = cond.And(cols[j], values[j])
+		}
+		seek = seek.OrCond(cond.And(cols[i]+ExprSep+"gt", values[i]))
+	}
+
+	if o.cond == nil {
+		o.cond = NewCondition()
+	}
+	o.cond = o.cond.AndCond(seek)
+	o.orders = order_clause.ParseOrder(cols...)
+	return &o
+}
+
+func splitCursorCols(col string) []string {
+	cols := strings.Split(col, ",")
+	for i := range cols {
+		cols[i] = strings.TrimSpace(cols[i])
+	}
+	return cols
+}
+
 // set offset number
 func (o *querySet) setOffset(num interface{}) {
-	o.offset = ToInt64(num)
+	o.offset = toLimitValue("Offset", num)
+	if o.offset < 0 {
+		panic(fmt.Errorf("<QuerySeter.Offset> offset cannot be negative, got %d", o.offset))
+	}
+}
+
+// convert the value of LIMIT or OFFSET, the unsigned value which overflows int64 is rejected
+func toLimitValue(method string, num interface{}) int64 {
+	if val := reflect.ValueOf(num); val.Kind() >= reflect.Uint && val.Kind() <= reflect.Uint64 && val.Uint() > math.MaxInt64 {
+		panic(fmt.Errorf("<QuerySeter.%s> value %d overflows int64", method, val.Uint()))
+	}
+	return ToInt64(num)
 }
 
 // add LIMIT value.
 // args[0] means offset, e.g. LIMIT num,offset.
 func (o querySet) Limit(limit interface{}, args ...interface{}) QuerySeter {
-	o.limit = ToInt64(limit)
+	o.limit = toLimitValue("Limit", limit)
+	if o.limit < -1 {
+		panic(fmt.Errorf("<QuerySeter.Limit> limit must be -1 or not negative, got %d, use Unlimited to remove the limit", o.limit))
+	}
 	if len(args) > 0 {
 		o.setOffset(args[0])
 	}
 	return &o
 }
 
+// execute the sql directly instead of the cached prepared statement.
+func (o querySet) NoStmtCache() QuerySeter {
+	o.noStmtCache = true
+	return &o
+}
+
+// get the querier to execute the sql
+func (o *querySet) querier() dbQuerier {
+	if o.noStmtCache {
+		return noStmtCacheQuerier(o.orm.db)
+	}
+	return o.orm.db
+}
+
+// remove the limit, including DefaultRowsLimit.
+func (o querySet) Unlimited() QuerySeter {
+	o.limit = -1
+	return &o
+}
+
 // add OFFSET value
 func (o querySet) Offset(offset interface{}) QuerySeter {
 	o.setOffset(offset)
@@ -137,6 +359,28 @@ func (o querySet) GroupBy(exprs ...string) QuerySeter {
 	return &o
 }
 
+// add HAVING condition on the alias of aggregate expression.
+func (o querySet) Having(expr string, args ...interface{}) QuerySeter {
+	o.havings = append(o.havings[:len(o.havings):len(o.havings)], havingCond{expr: expr, args: args})
+	return &o
+}
+
+// add the values of col joined by sep in each group to the values as alias.
+func (o querySet) GroupConcat(col, alias, sep string) QuerySeter {
+	if col == "" || alias == "" {
+		panic(fmt.Errorf("<QuerySeter.GroupConcat> col and alias cannot empty"))
+	}
+	o.concats = append(o.concats[:len(o.concats):len(o.concats)], groupConcat{col: col, alias: alias, sep: sep})
+	return &o
+}
+
+// add a column value for Update, the columns are set in the order of Set.
+// value can be a literal or a column expression like ColValue(ColAdd, 10).
+func (o querySet) Set(col string, value interface{}) QuerySeter {
+	o.sets = append(o.sets[:len(o.sets):len(o.sets)], updateSet{col: col, value: value})
+	return &o
+}
+
 // add ORDER expression.
 // "column" means ASC, "-column" means DESC.
 func (o querySet) OrderBy(expressions ...string) QuerySeter {
@@ -147,6 +391,32 @@ func (o querySet) OrderBy(expressions ...string) QuerySeter {
 	return &o
 }
 
+// NullsFirstOrLast is the position of NULL values for OrderByNulls
+type NullsFirstOrLast = order_clause.Nulls
+
+// define the positions of NULL values
+const (
+	NullsFirst = order_clause.NullsFirst
+	NullsLast  = order_clause.NullsLast
+)
+
+// append ORDER expression with the position of NULL values.
+func (o querySet) OrderByNulls(col string, desc bool, nulls NullsFirstOrLast) QuerySeter {
+	sort := order_clause.SortAscending()
+	if desc {
+		sort = order_clause.SortDescending()
+	}
+	order := order_clause.Clause(order_clause.Column(col), sort, order_clause.WithNulls(nulls))
+	o.orders = append(append([]*order_clause.Order(nil), o.orders...), order)
+	return &o
+}
+
+// append random ORDER expression, the random order of the driver, e.g. RAND() or RANDOM().
+func (o querySet) OrderRandom() QuerySeter {
+	o.orderRandom = true
+	return &o
+}
+
 // add ORDER expression.
 func (o querySet) OrderClauses(orders ...*order_clause.Order) QuerySeter {
 	if len(orders) <= 0 {
@@ -168,6 +438,13 @@ func (o querySet) ForUpdate() QuerySeter {
 	return &o
 }
 
+// add FOR UPDATE NOWAIT to SELECT
+func (o querySet) ForUpdateNoWait() QuerySeter {
+	o.forUpdate = true
+	o.noWait = true
+	return &o
+}
+
 // ForceIndex force index for query
 func (o querySet) ForceIndex(indexes ...string) QuerySeter {
 	o.useIndex = hints.KeyForceIndex
@@ -189,6 +466,12 @@ func (o querySet) IgnoreIndex(indexes ...string) QuerySeter {
 	return &o
 }
 
+// UseTable use the given table name instead of the model's table
+func (o querySet) UseTable(table string) QuerySeter {
+	o.table = table
+	return &o
+}
+
 // set relation model to query together.
 // it will query relation models and assign to parent model.
 func (o querySet) RelatedSel(params ...interface{}) QuerySeter {
@@ -209,6 +492,12 @@ func (o querySet) RelatedSel(params ...interface{}) QuerySeter {
 	return &o
 }
 
+// set the columns to query of the model and the related models.
+func (o querySet) SelectColumns(cols ...string) QuerySeter {
+	o.selectCols = append(o.selectCols[:len(o.selectCols):len(o.selectCols)], cols...)
+	return &o
+}
+
 // set condition to QuerySeter.
 func (o querySet) SetCond(cond *Condition) QuerySeter {
 	o.cond = cond
@@ -220,41 +509,110 @@ func (o querySet) GetCond() *Condition {
 	return o.cond
 }
 
+// return a copy of QuerySeter.
+// conditions, orders, groups and relations are copied,
+// so the copy and the original can be changed separately.
+func (o querySet) Clone() QuerySeter {
+	if o.cond != nil {
+		o.cond = o.cond.clone()
+	}
+	o.related = append([]string(nil), o.related...)
+	o.groups = append([]string(nil), o.groups...)
+	o.havings = append([]havingCond(nil), o.havings...)
+	o.sets = append([]updateSet(nil), o.sets...)
+	o.concats = append([]groupConcat(nil), o.concats...)
+	o.orders = append([]*order_clause.Order(nil), o.orders...)
+	o.indexes = append([]string(nil), o.indexes...)
+	return &o
+}
+
 // return QuerySeter execution result number
 func (o *querySet) Count() (int64, error) {
-	return o.CountWithCtx(context.Background())
+	return o.CountWithCtx(o.orm.defaultCtx())
 }
 
 func (o *querySet) CountWithCtx(ctx context.Context) (int64, error) {
-	return o.orm.alias.DbBaser.Count(ctx, o.orm.db, o, o.mi, o.cond, o.orm.alias.TZ)
+	return o.orm.alias.DbBaser.Count(ctx, o.querier(), o, o.mi, o.scopedCond(ctx), o.getTZ())
+}
+
+// return the number of distinct values of the column
+func (o *querySet) CountDistinct(col string) (int64, error) {
+	return o.CountDistinctWithCtx(o.orm.defaultCtx(), col)
+}
+
+func (o *querySet) CountDistinctWithCtx(ctx context.Context, col string) (int64, error) {
+	var cnt int64
+	err := o.orm.alias.DbBaser.AggregateColumn(ctx, o.querier(), o, o.mi, o.scopedCond(ctx), "COUNT DISTINCT", col, &cnt, o.getTZ())
+	return cnt, err
+}
+
+// query SUM of the column into result
+func (o *querySet) Sum(expr string, result interface{}) error {
+	return o.SumWithCtx(o.orm.defaultCtx(), expr, result)
+}
+
+func (o *querySet) SumWithCtx(ctx context.Context, expr string, result interface{}) error {
+	return o.orm.alias.DbBaser.AggregateColumn(ctx, o.querier(), o, o.mi, o.scopedCond(ctx), "SUM", expr, result, o.getTZ())
+}
+
+// query MAX of the column into result
+func (o *querySet) Max(expr string, result interface{}) error {
+	return o.MaxWithCtx(o.orm.defaultCtx(), expr, result)
+}
+
+func (o *querySet) MaxWithCtx(ctx context.Context, expr string, result interface{}) error {
+	return o.orm.alias.DbBaser.AggregateColumn(ctx, o.querier(), o, o.mi, o.scopedCond(ctx), "MAX", expr, result, o.getTZ())
+}
+
+// query MIN of the column into result
+func (o *querySet) Min(expr string, result interface{}) error {
+	return o.MinWithCtx(o.orm.defaultCtx(), expr, result)
+}
+
+func (o *querySet) MinWithCtx(ctx context.Context, expr string, result interface{}) error {
+	return o.orm.alias.DbBaser.AggregateColumn(ctx, o.querier(), o, o.mi, o.scopedCond(ctx), "MIN", expr, result, o.getTZ())
+}
+
+// query AVG of the column into result
+func (o *querySet) Avg(expr string, result interface{}) error {
+	return o.AvgWithCtx(o.orm.defaultCtx(), expr, result)
+}
+
+func (o *querySet) AvgWithCtx(ctx context.Context, expr string, result interface{}) error {
+	return o.orm.alias.DbBaser.AggregateColumn(ctx, o.querier(), o, o.mi, o.scopedCond(ctx), "AVG", expr, result, o.getTZ())
 }
 
 // check result empty or not after QuerySeter executed
 func (o *querySet) Exist() bool {
-	return o.ExistWithCtx(context.Background())
+	return o.ExistWithCtx(o.orm.defaultCtx())
 }
 
 func (o *querySet) ExistWithCtx(ctx context.Context) bool {
-	cnt, _ := o.orm.alias.DbBaser.Count(ctx, o.orm.db, o, o.mi, o.cond, o.orm.alias.TZ)
+	cnt, _ := o.orm.alias.DbBaser.Count(ctx, o.querier(), o, o.mi, o.scopedCond(ctx), o.getTZ())
 	return cnt > 0
 }
 
 // execute update with parameters
 func (o *querySet) Update(values Params) (int64, error) {
-	return o.UpdateWithCtx(context.Background(), values)
+	return o.UpdateWithCtx(o.orm.defaultCtx(), values)
 }
 
 func (o *querySet) UpdateWithCtx(ctx context.Context, values Params) (int64, error) {
-	return o.orm.alias.DbBaser.UpdateBatch(ctx, o.orm.db, o, o.mi, o.cond, values, o.orm.alias.TZ)
+	return o.orm.alias.DbBaser.UpdateBatch(ctx, o.querier(), o, o.mi, o.scopedCond(ctx), values, o.getTZ())
 }
 
 // execute delete
 func (o *querySet) Delete() (int64, error) {
-	return o.DeleteWithCtx(context.Background())
+	return o.DeleteWithCtx(o.orm.defaultCtx())
 }
 
 func (o *querySet) DeleteWithCtx(ctx context.Context) (int64, error) {
-	return o.orm.alias.DbBaser.DeleteBatch(ctx, o.orm.db, o, o.mi, o.cond, o.orm.alias.TZ)
+	// the condition of the query scoper doesn't make the empty condition deletable
+	cond := o.cond
+	if cond != nil && (!cond.IsEmpty() || cond.allowEmpty) {
+		cond = o.scopedCond(ctx)
+	}
+	return o.orm.alias.DbBaser.DeleteBatch(ctx, o.querier(), o, o.mi, cond, o.getTZ())
 }
 
 // return a insert queryer.
@@ -263,32 +621,170 @@ func (o *querySet) DeleteWithCtx(ctx context.Context) (int64, error) {
 // 	i,err := sq.PrepareInsert()
 // 	i.Add(&user1{},&user2{})
 func (o *querySet) PrepareInsert() (Inserter, error) {
-	return o.PrepareInsertWithCtx(context.Background())
+	return o.PrepareInsertWithCtx(o.orm.defaultCtx())
 }
 
 func (o *querySet) PrepareInsertWithCtx(ctx context.Context) (Inserter, error) {
 	return newInsertSet(ctx, o.orm, o.mi)
 }
 
+// prepare the select statement of querySeter to query it in times with the different args.
+func (o *querySet) Prepare() (PreparedQuerySeter, error) {
+	return o.PrepareWithCtx(o.orm.defaultCtx())
+}
+
+func (o *querySet) PrepareWithCtx(ctx context.Context) (PreparedQuerySeter, error) {
+	return newPreparedQuerySet(ctx, o)
+}
+
 // query all data and map to containers.
 // cols means the columns when querying.
 func (o *querySet) All(container interface{}, cols ...string) (int64, error) {
-	return o.AllWithCtx(context.Background(), container, cols...)
+	return o.AllWithCtx(o.orm.defaultCtx(), container, cols...)
 }
 
 func (o *querySet) AllWithCtx(ctx context.Context, container interface{}, cols ...string) (int64, error) {
-	return o.orm.alias.DbBaser.ReadBatch(ctx, o.orm.db, o, o.mi, o.cond, container, o.orm.alias.TZ, cols)
+	if ind := reflect.Indirect(reflect.ValueOf(container)); ind.Kind() == reflect.Slice {
+		conds, err := o.splitInChunks()
+		if err != nil {
+			return 0, err
+		}
+		if conds != nil {
+			return o.readInChunks(ctx, conds, container, cols)
+		}
+	}
+	return o.orm.alias.DbBaser.ReadBatch(ctx, o.querier(), o, o.mi, o.scopedCond(ctx), container, o.getTZ(), cols)
+}
+
+// query all rows into container like All, and return the total count of rows
+// without limit and offset, e.g. for pagination.
+func (o *querySet) AllWithTotalCount(container interface{}, cols ...string) (int64, error) {
+	return o.AllWithTotalCountWithCtx(o.orm.defaultCtx(), container, cols...)
+}
+
+func (o *querySet) AllWithTotalCountWithCtx(ctx context.Context, container interface{}, cols ...string) (int64, error) {
+	// window function is not allowed with FOR UPDATE, and is computed before DISTINCT
+	if !isWindowFuncSupported(o.orm.alias) || o.distinct || o.aggregate != "" || o.forUpdate {
+		return o.allAndCount(ctx, container, cols)
+	}
+
+	total := int64(-1)
+	qs := *o
+	qs.total = &total
+	if _, err := o.orm.alias.DbBaser.ReadBatch(ctx, o.querier(), &qs, o.mi, o.scopedCond(ctx), container, o.getTZ(), cols); err != nil {
+		if !isSyntaxError(err) {
+			return 0, err
+		}
+		// the old database versions reject the window function, fall back to the count query,
+		// the error is returned if the fallback fails too
+		num, fallbackErr := o.allAndCount(ctx, container, cols)
+		if fallbackErr != nil {
+			return 0, err
+		}
+		atomic.StoreInt32(&o.orm.alias.noWindowFunc, 1)
+		return num, nil
+	}
+	// no row is returned, the page may be beyond the last row
+	if total < 0 {
+		if o.offset == 0 {
+			return 0, nil
+		}
+		return o.CountWithCtx(ctx)
+	}
+	return total, nil
+}
+
+// query all rows into container and count them by another query
+func (o *querySet) allAndCount(ctx context.Context, container interface{}, cols []string) (int64, error) {
+	if _, err := o.AllWithCtx(ctx, container, cols...); err != nil {
+		return 0, err
+	}
+	return o.CountWithCtx(ctx)
+}
+
+// query a page of rows after the cursor value to container by keyset pagination,
+// and return the cursor value of the last row for the next page.
+// the first page is queried when after is nil.
+func (o *querySet) Paginate(container interface{}, col string, after interface{}, limit int) (interface{}, error) {
+	return o.PaginateWithCtx(o.orm.defaultCtx(), container, col, after, limit)
+}
+
+func (o *querySet) PaginateWithCtx(ctx context.Context, container interface{}, col string, after interface{}, limit int) (interface{}, error) {
+	cols := splitCursorCols(col)
+
+	var qs QuerySeter
+	if after == nil {
+		qs = o.OrderBy(cols...)
+	} else {
+		qs = o.SeekGt(col, after)
+	}
+
+	num, err := qs.Limit(limit).AllWithCtx(ctx, container)
+	if err != nil || num == 0 {
+		return nil, err
+	}
+
+	last := reflect.Indirect(reflect.Indirect(reflect.ValueOf(container)).Index(int(num) - 1))
+	if last.Kind() != reflect.Struct {
+		panic(fmt.Errorf("<QuerySeter.Paginate> container must be a slice of model struct, but got `%s`", last.Type()))
+	}
+	values := make([]interface{}, 0, len(cols))
+	for _, c := range cols {
+		fi, ok := o.mi.fields.GetByAny(c)
+		if !ok {
+			panic(fmt.Errorf("<QuerySeter.Paginate> wrong field/column name `%s`", c))
+		}
+		values = append(values, last.FieldByIndex(fi.fieldIndex).Interface())
+	}
+	if len(values) == 1 {
+		return values[0], nil
+	}
+	return values, nil
+}
+
+// combine the rows of other with UNION
+func (o *querySet) Union(other QuerySeter) UnionSeter {
+	return newUnionSet(o, other, false)
+}
+
+// combine the rows of other with UNION ALL
+func (o *querySet) UnionAll(other QuerySeter) UnionSeter {
+	return newUnionSet(o, other, true)
+}
+
+// return the query plan of the query All would run, the plan rows are returned as text.
+func (o *querySet) Explain(ctx context.Context) (string, error) {
+	return o.explain(ctx, false)
+}
+
+// like Explain, but use EXPLAIN ANALYZE, the query is executed by the database.
+func (o *querySet) ExplainAnalyze(ctx context.Context) (string, error) {
+	return o.explain(ctx, true)
+}
+
+func (o *querySet) explain(ctx context.Context, analyze bool) (string, error) {
+	prefix, ok := explainPrefix(o.orm.alias.Driver, analyze)
+	if !ok {
+		return "", ErrNotImplement
+	}
+	q := &explainQuerier{dbQuerier: o.querier(), prefix: prefix}
+	container := reflect.New(reflect.SliceOf(o.mi.addrField.Type())).Interface()
+	_, err := o.orm.alias.DbBaser.ReadBatch(ctx, q, o, o.mi, o.scopedCond(ctx), container, o.getTZ(), nil)
+	if err != errExplained {
+		return "", err
+	}
+	return q.plan, nil
 }
 
 // query one row data and map to containers.
 // cols means the columns when querying.
 func (o *querySet) One(container interface{}, cols ...string) error {
-	return o.OneWithCtx(context.Background(), container, cols...)
+	return o.OneWithCtx(o.orm.defaultCtx(), container, cols...)
 }
 
 func (o *querySet) OneWithCtx(ctx context.Context, container interface{}, cols ...string) error {
 	o.limit = 1
-	num, err := o.orm.alias.DbBaser.ReadBatch(ctx, o.orm.db, o, o.mi, o.cond, container, o.orm.alias.TZ, cols)
+	num, err := o.orm.alias.DbBaser.ReadBatch(ctx, o.querier(), o, o.mi, o.scopedCond(ctx), container, o.getTZ(), cols)
 	if err != nil {
 		return err
 	}
@@ -302,35 +798,105 @@ func (o *querySet) OneWithCtx(ctx context.Context, container interface{}, cols .
 	return nil
 }
 
+// query the first row by the current order and map to container.
+// ErrNoRows is returned if no row found, the other rows are ignored.
+func (o *querySet) First(container interface{}, cols ...string) error {
+	return o.FirstWithCtx(o.orm.defaultCtx(), container, cols...)
+}
+
+func (o *querySet) FirstWithCtx(ctx context.Context, container interface{}, cols ...string) error {
+	qs := *o
+	qs.limit = 1
+	num, err := o.orm.alias.DbBaser.ReadBatch(ctx, qs.querier(), &qs, qs.mi, qs.scopedCond(ctx), container, qs.getTZ(), cols)
+	if err != nil {
+		return err
+	}
+	if num == 0 {
+		return ErrNoRows
+	}
+	return nil
+}
+
 // query all data and map to []map[string]interface.
 // expres means condition expression.
 // it converts data to []map[column]value.
 func (o *querySet) Values(results *[]Params, exprs ...string) (int64, error) {
-	return o.ValuesWithCtx(context.Background(), results, exprs...)
+	return o.ValuesWithCtx(o.orm.defaultCtx(), results, exprs...)
 }
 
 func (o *querySet) ValuesWithCtx(ctx context.Context, results *[]Params, exprs ...string) (int64, error) {
-	return o.orm.alias.DbBaser.ReadValues(ctx, o.orm.db, o, o.mi, o.cond, exprs, results, o.orm.alias.TZ)
+	return o.orm.alias.DbBaser.ReadValues(ctx, o.querier(), o, o.mi, o.scopedCond(ctx), exprs, results, o.getTZ())
+}
+
+// query one row data and map to Params.
+// return ErrNoRows if no row found, ErrMultiRows if more than one row found.
+func (o *querySet) ValuesOne(result *Params, exprs ...string) error {
+	return o.ValuesOneWithCtx(o.orm.defaultCtx(), result, exprs...)
+}
+
+func (o *querySet) ValuesOneWithCtx(ctx context.Context, result *Params, exprs ...string) error {
+	qs := *o
+	// query 2 rows to know whether there are multi rows
+	qs.limit = 2
+	var maps []Params
+	num, err := o.orm.alias.DbBaser.ReadValues(ctx, o.querier(), &qs, o.mi, o.scopedCond(ctx), exprs, &maps, o.getTZ())
+	if err != nil {
+		return err
+	}
+	if num == 0 {
+		return ErrNoRows
+	}
+
+	if num > 1 {
+		return ErrMultiRows
+	}
+	*result = maps[0]
+	return nil
 }
 
 // query all data and map to [][]interface
 // it converts data to [][column_index]value
 func (o *querySet) ValuesList(results *[]ParamsList, exprs ...string) (int64, error) {
-	return o.ValuesListWithCtx(context.Background(), results, exprs...)
+	return o.ValuesListWithCtx(o.orm.defaultCtx(), results, exprs...)
 }
 
 func (o *querySet) ValuesListWithCtx(ctx context.Context, results *[]ParamsList, exprs ...string) (int64, error) {
-	return o.orm.alias.DbBaser.ReadValues(ctx, o.orm.db, o, o.mi, o.cond, exprs, results, o.orm.alias.TZ)
+	return o.orm.alias.DbBaser.ReadValues(ctx, o.querier(), o, o.mi, o.scopedCond(ctx), exprs, results, o.getTZ())
+}
+
+// query all data and map to [][]interface like ValuesList,
+// the values are converted to the go types of the model fields.
+func (o *querySet) ValuesListTyped(results *[]ParamsList, exprs ...string) (int64, error) {
+	return o.ValuesListTypedWithCtx(o.orm.defaultCtx(), results, exprs...)
+}
+
+func (o *querySet) ValuesListTypedWithCtx(ctx context.Context, results *[]ParamsList, exprs ...string) (int64, error) {
+	return o.orm.alias.DbBaser.ReadValues(ctx, o.querier(), o, o.mi, o.scopedCond(ctx), exprs, typedParamsLists{results}, o.getTZ())
+}
+
+// query the exprs and map the rows to the struct slice container like RawSeter.QueryRows,
+// the struct doesn't need to be registered.
+func (o *querySet) Scan(container interface{}, exprs ...string) (int64, error) {
+	return o.ScanWithCtx(o.orm.defaultCtx(), container, exprs...)
+}
+
+func (o *querySet) ScanWithCtx(ctx context.Context, container interface{}, exprs ...string) (int64, error) {
+	query, args, fields, _, err := o.orm.alias.DbBaser.ValuesSQL(o, o.mi, o.scopedCond(ctx), exprs, o.getTZ(), true)
+	if err != nil {
+		return 0, err
+	}
+	rs := &rawSet{query: query, args: args, orm: o.orm, noStmtCache: o.noStmtCache}
+	return rs.QueryRowsWithCtx(withArgFields(ctx, fields), container)
 }
 
 // query all data and map to []interface.
 // it's designed for one row record set, auto change to []value, not [][column]value.
 func (o *querySet) ValuesFlat(result *ParamsList, expr string) (int64, error) {
-	return o.ValuesFlatWithCtx(context.Background(), result, expr)
+	return o.ValuesFlatWithCtx(o.orm.defaultCtx(), result, expr)
 }
 
 func (o *querySet) ValuesFlatWithCtx(ctx context.Context, result *ParamsList, expr string) (int64, error) {
-	return o.orm.alias.DbBaser.ReadValues(ctx, o.orm.db, o, o.mi, o.cond, []string{expr}, result, o.orm.alias.TZ)
+	return o.orm.alias.DbBaser.ReadValues(ctx, o.querier(), o, o.mi, o.scopedCond(ctx), []string{expr}, result, o.getTZ())
 }
 
 // query all rows into map[string]interface with specify key and value column name.
@@ -361,6 +927,49 @@ func (o *querySet) RowsToStruct(ptrStruct interface{}, keyCol, valueCol string)
 	panic(ErrNotImplement)
 }
 
+// rowHandler is used as the container of ReadBatch to handle rows one by one
+type rowHandler func(md interface{}) error
+
+// fold all rows into one value, rows are read one by one without loading into a slice.
+// it stops when ctx is done, and returns the partial result with the error.
+func (o *querySet) Reduce(ctx context.Context, initial interface{}, fn func(acc, md interface{}) interface{}) (interface{}, error) {
+	acc := initial
+	_, err := o.orm.alias.DbBaser.ReadBatch(ctx, o.querier(), o, o.mi, o.scopedCond(ctx), rowHandler(func(md interface{}) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		acc = fn(acc, md)
+		return nil
+	}), o.getTZ(), nil)
+	return acc, err
+}
+
+// read the rows one by one and send them to the channel in a new goroutine.
+// the channels are closed when all rows are sent or ctx is done.
+func (o *querySet) Channel(ctx context.Context, bufSize int) (<-chan interface{}, <-chan error) {
+	rows := make(chan interface{}, bufSize)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(rows)
+		_, err := o.orm.alias.DbBaser.ReadBatch(ctx, o.querier(), o, o.mi, o.scopedCond(ctx), rowHandler(func(md interface{}) error {
+			select {
+			case rows <- md:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}), o.getTZ(), nil)
+		if err == nil {
+			err = ctx.Err()
+		}
+		if err != nil {
+			errs <- err
+		}
+	}()
+	return rows, errs
+}
+
 // create new QuerySeter.
 func newQuerySet(orm *ormBase, mi *modelInfo) QuerySeter {
 	o := new(querySet)
@@ -15,9 +15,12 @@
 package orm
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -64,9 +67,10 @@ func newRawPreparer(rs *rawSet) (RawPreparer, error) {
 
 // raw query seter
 type rawSet struct {
-	query string
-	args  []interface{}
-	orm   *ormBase
+	query       string
+	args        []interface{}
+	orm         *ormBase
+	noStmtCache bool
 }
 
 var _ RawSeter = new(rawSet)
@@ -77,13 +81,31 @@ func (o rawSet) SetArgs(args ...interface{}) RawSeter {
 	return &o
 }
 
+// execute the sql directly instead of the cached prepared statement.
+func (o rawSet) NoStmtCache() RawSeter {
+	o.noStmtCache = true
+	return &o
+}
+
+// get the querier to execute the sql
+func (o *rawSet) querier() dbQuerier {
+	if o.noStmtCache {
+		return noStmtCacheQuerier(o.orm.db)
+	}
+	return o.orm.db
+}
+
 // execute raw sql and return sql.Result
 func (o *rawSet) Exec() (sql.Result, error) {
+	return o.ExecWithCtx(o.orm.defaultCtx())
+}
+
+func (o *rawSet) ExecWithCtx(ctx context.Context) (sql.Result, error) {
 	query := o.query
 	o.orm.alias.DbBaser.ReplaceMarks(&query)
 
 	args := getFlatParams(nil, o.args, o.orm.alias.TZ)
-	return o.orm.db.Exec(query, args...)
+	return o.querier().ExecContext(ctx, query, args...)
 }
 
 // set field value to row container
@@ -211,6 +233,55 @@ func (o *rawSet) setFieldValue(ind reflect.Value, value interface{}) {
 	}
 }
 
+// get the column name of the field of unregistered struct, by the column of orm tag,
+// the db tag or the name strategy in order.
+func rawStructFieldColumn(sf reflect.StructField, tags map[string]string) string {
+	if col := tags["column"]; col != "" {
+		return col
+	}
+	if col := sf.Tag.Get("db"); col != "" {
+		return col
+	}
+	return nameStrategyMap[nameStrategy](sf.Name)
+}
+
+// check whether the field of unregistered struct is tagged `orm:"type(json)"` or jsonb
+// and should be unmarshalled, the string and []byte fields get the raw json as it is.
+func isRawJSONField(typ reflect.Type, tags map[string]string) bool {
+	switch strings.ToLower(tags["type"]) {
+	case "json", "jsonb":
+	default:
+		return false
+	}
+	if typ.Kind() == reflect.String {
+		return false
+	}
+	return !(typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8)
+}
+
+// set the json field of unregistered struct by unmarshalling the value, so the rows
+// aggregated by json_agg of postgres can be read into the nested structs, e.g. []Child.
+func setRawJSONFieldValue(ind reflect.Value, value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		ind.Set(reflect.Zero(ind.Type()))
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("<RawSeter> cannot unmarshal %T into json field of type %s", value, ind.Type())
+	}
+	nv := reflect.New(ind.Type())
+	if err := json.Unmarshal(data, nv.Interface()); err != nil {
+		return fmt.Errorf("<RawSeter> unmarshal json field of type %s: %w", ind.Type(), err)
+	}
+	ind.Set(nv.Elem())
+	return nil
+}
+
 // set field value in loop for slice container
 func (o *rawSet) loopSetRefs(refs []interface{}, sInds []reflect.Value, nIndsPtr *[]reflect.Value, eTyps []reflect.Type, init bool) {
 	nInds := *nIndsPtr
@@ -283,6 +354,10 @@ func (o *rawSet) loopSetRefs(refs []interface{}, sInds []reflect.Value, nIndsPtr
 
 // query data and map to container
 func (o *rawSet) QueryRow(containers ...interface{}) error {
+	return o.QueryRowWithCtx(o.orm.defaultCtx(), containers...)
+}
+
+func (o *rawSet) QueryRowWithCtx(ctx context.Context, containers ...interface{}) error {
 	var (
 		refs  = make([]interface{}, 0, len(containers))
 		sInds []reflect.Value
@@ -327,7 +402,9 @@ func (o *rawSet) QueryRow(containers ...interface{}) error {
 	o.orm.alias.DbBaser.ReplaceMarks(&query)
 
 	args := getFlatParams(nil, o.args, o.orm.alias.TZ)
-	rows, err := o.orm.db.Query(query, args...)
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+	rows, err := o.querier().QueryContext(ctx, query, args...)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return ErrNoRows
@@ -391,37 +468,47 @@ func (o *rawSet) QueryRow(containers ...interface{}) error {
 				}
 			} else {
 				// define recursive function
-				var recursiveSetField func(rv reflect.Value)
-				recursiveSetField = func(rv reflect.Value) {
+				var recursiveSetField func(rv reflect.Value) error
+				recursiveSetField = func(rv reflect.Value) error {
 					for i := 0; i < rv.NumField(); i++ {
 						f := rv.Field(i)
 						fe := rv.Type().Field(i)
 
-						// check if the field is a Struct
-						// recursive the Struct type
-						if fe.Type.Kind() == reflect.Struct {
-							recursiveSetField(f)
-						}
-
 						// thanks @Gazeboxu.
 						tags := structTagMap[fe.Tag]
 						if tags == nil {
 							_, tags = parseStructTag(fe.Tag.Get(defaultStructTagName))
 							structTagMap[fe.Tag] = tags
 						}
-						var col string
-						if col = tags["column"]; col == "" {
-							col = nameStrategyMap[nameStrategy](fe.Name)
+						isJSON := isRawJSONField(fe.Type, tags)
+
+						// check if the field is a Struct
+						// recursive the Struct type
+						if fe.Type.Kind() == reflect.Struct && !isJSON {
+							if err := recursiveSetField(f); err != nil {
+								return err
+							}
 						}
+
+						col := rawStructFieldColumn(fe, tags)
 						if v, ok := columnsMp[col]; ok {
 							value := reflect.ValueOf(v).Elem().Interface()
-							o.setFieldValue(f, value)
+							if isJSON {
+								if err := setRawJSONFieldValue(f, value); err != nil {
+									return err
+								}
+							} else {
+								o.setFieldValue(f, value)
+							}
 						}
 					}
+					return nil
 				}
 
 				// init call the recursive function
-				recursiveSetField(ind)
+				if err := recursiveSetField(ind); err != nil {
+					return err
+				}
 			}
 
 		} else {
@@ -445,6 +532,65 @@ func (o *rawSet) QueryRow(containers ...interface{}) error {
 
 // query data rows and map to container
 func (o *rawSet) QueryRows(containers ...interface{}) (int64, error) {
+	return o.QueryRowsWithCtx(o.orm.defaultCtx(), containers...)
+}
+
+func (o *rawSet) QueryRowsWithCtx(ctx context.Context, containers ...interface{}) (int64, error) {
+	query := o.query
+	o.orm.alias.DbBaser.ReplaceMarks(&query)
+
+	args := getFlatParams(nil, o.args, o.orm.alias.TZ)
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+	rows, err := o.querier().QueryContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	defer rows.Close()
+
+	return o.readRows(rows, containers...)
+}
+
+// query the sql which returns multiple result sets, e.g. calling the stored procedure,
+// and map each result set to the container at the same position
+func (o *rawSet) QueryMulti(containers ...interface{}) (int64, error) {
+	return o.QueryMultiWithCtx(o.orm.defaultCtx(), containers...)
+}
+
+func (o *rawSet) QueryMultiWithCtx(ctx context.Context, containers ...interface{}) (int64, error) {
+	query := o.query
+	o.orm.alias.DbBaser.ReplaceMarks(&query)
+
+	args := getFlatParams(nil, o.args, o.orm.alias.TZ)
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+	rows, err := o.querier().QueryContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	defer rows.Close()
+
+	var cnt int64
+	for i, container := range containers {
+		if i > 0 && !rows.NextResultSet() {
+			if err := rows.Err(); err != nil {
+				return cnt, err
+			}
+			return cnt, fmt.Errorf("<RawSeter.QueryMulti> the query returns %d result sets, but %d containers are given", i, len(containers))
+		}
+		num, err := o.readRows(rows, container)
+		if err != nil {
+			return cnt, err
+		}
+		cnt += num
+	}
+	return cnt, nil
+}
+
+// map the rows of the current result set to containers
+func (o *rawSet) readRows(rows *sql.Rows, containers ...interface{}) (int64, error) {
 	var (
 		refs  = make([]interface{}, 0, len(containers))
 		sInds []reflect.Value
@@ -484,17 +630,6 @@ func (o *rawSet) QueryRows(containers ...interface{}) (int64, error) {
 		}
 	}
 
-	query := o.query
-	o.orm.alias.DbBaser.ReplaceMarks(&query)
-
-	args := getFlatParams(nil, o.args, o.orm.alias.TZ)
-	rows, err := o.orm.db.Query(query, args...)
-	if err != nil {
-		return 0, err
-	}
-
-	defer rows.Close()
-
 	var cnt int64
 	nInds := make([]reflect.Value, len(sInds))
 	sInd := sInds[0]
@@ -558,32 +693,42 @@ func (o *rawSet) QueryRows(containers ...interface{}) (int64, error) {
 				}
 			} else {
 				// define recursive function
-				var recursiveSetField func(rv reflect.Value)
-				recursiveSetField = func(rv reflect.Value) {
+				var recursiveSetField func(rv reflect.Value) error
+				recursiveSetField = func(rv reflect.Value) error {
 					for i := 0; i < rv.NumField(); i++ {
 						f := rv.Field(i)
 						fe := rv.Type().Field(i)
 
+						_, tags := parseStructTag(fe.Tag.Get(defaultStructTagName))
+						isJSON := isRawJSONField(fe.Type, tags)
+
 						// check if the field is a Struct
 						// recursive the Struct type
-						if fe.Type.Kind() == reflect.Struct {
-							recursiveSetField(f)
+						if fe.Type.Kind() == reflect.Struct && !isJSON {
+							if err := recursiveSetField(f); err != nil {
+								return err
+							}
 						}
 
-						_, tags := parseStructTag(fe.Tag.Get(defaultStructTagName))
-						var col string
-						if col = tags["column"]; col == "" {
-							col = nameStrategyMap[nameStrategy](fe.Name)
-						}
+						col := rawStructFieldColumn(fe, tags)
 						if v, ok := columnsMp[col]; ok {
 							value := reflect.ValueOf(v).Elem().Interface()
-							o.setFieldValue(f, value)
+							if isJSON {
+								if err := setRawJSONFieldValue(f, value); err != nil {
+									return err
+								}
+							} else {
+								o.setFieldValue(f, value)
+							}
 						}
 					}
+					return nil
 				}
 
 				// init call the recursive function
-				recursiveSetField(ind)
+				if err := recursiveSetField(ind); err != nil {
+					return 0, err
+				}
 			}
 
 			if eTyps[0].Kind() == reflect.Ptr {
@@ -617,7 +762,7 @@ func (o *rawSet) QueryRows(containers ...interface{}) (int64, error) {
 	return cnt, nil
 }
 
-func (o *rawSet) readValues(container interface{}, needCols []string) (int64, error) {
+func (o *rawSet) readValues(ctx context.Context, container interface{}, needCols []string) (int64, error) {
 	var (
 		maps  []Params
 		lists []ParamsList
@@ -641,8 +786,10 @@ func (o *rawSet) readValues(container interface{}, needCols []string) (int64, er
 
 	args := getFlatParams(nil, o.args, o.orm.alias.TZ)
 
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 	var rs *sql.Rows
-	rs, err := o.orm.db.Query(query, args...)
+	rs, err := o.querier().QueryContext(ctx, query, args...)
 	if err != nil {
 		return 0, err
 	}
@@ -742,7 +889,7 @@ func (o *rawSet) readValues(container interface{}, needCols []string) (int64, er
 	return cnt, nil
 }
 
-func (o *rawSet) queryRowsTo(container interface{}, keyCol, valueCol string) (int64, error) {
+func (o *rawSet) queryRowsTo(ctx context.Context, container interface{}, keyCol, valueCol string) (int64, error) {
 	var (
 		maps Params
 		ind  *reflect.Value
@@ -768,7 +915,9 @@ func (o *rawSet) queryRowsTo(container interface{}, keyCol, valueCol string) (in
 
 	args := getFlatParams(nil, o.args, o.orm.alias.TZ)
 
-	rs, err := o.orm.db.Query(query, args...)
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+	rs, err := o.querier().QueryContext(ctx, query, args...)
 	if err != nil {
 		return 0, err
 	}
@@ -855,17 +1004,17 @@ func (o *rawSet) queryRowsTo(container interface{}, keyCol, valueCol string) (in
 
 // query data to []map[string]interface
 func (o *rawSet) Values(container *[]Params, cols ...string) (int64, error) {
-	return o.readValues(container, cols)
+	return o.readValues(o.orm.defaultCtx(), container, cols)
 }
 
 // query data to [][]interface
 func (o *rawSet) ValuesList(container *[]ParamsList, cols ...string) (int64, error) {
-	return o.readValues(container, cols)
+	return o.readValues(o.orm.defaultCtx(), container, cols)
 }
 
 // query data to []interface
 func (o *rawSet) ValuesFlat(container *ParamsList, cols ...string) (int64, error) {
-	return o.readValues(container, cols)
+	return o.readValues(o.orm.defaultCtx(), container, cols)
 }
 
 // query all rows into map[string]interface with specify key and value column name.
@@ -879,7 +1028,7 @@ func (o *rawSet) ValuesFlat(container *ParamsList, cols ...string) (int64, error
 // 	"found": 200,
 // }
 func (o *rawSet) RowsToMap(result *Params, keyCol, valueCol string) (int64, error) {
-	return o.queryRowsTo(result, keyCol, valueCol)
+	return o.queryRowsTo(o.orm.defaultCtx(), result, keyCol, valueCol)
 }
 
 // query all rows into struct with specify key and value column name.
@@ -893,7 +1042,7 @@ func (o *rawSet) RowsToMap(result *Params, keyCol, valueCol string) (int64, erro
 // 	Found int
 // }
 func (o *rawSet) RowsToStruct(ptrStruct interface{}, keyCol, valueCol string) (int64, error) {
-	return o.queryRowsTo(ptrStruct, keyCol, valueCol)
+	return o.queryRowsTo(o.orm.defaultCtx(), ptrStruct, keyCol, valueCol)
 }
 
 // return prepared raw statement for used in times.
@@ -0,0 +1,126 @@
+// Copyright 2020 beego
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.18
+
+package orm
+
+import (
+	"context"
+)
+
+// Repository is the typed CRUD of model T, which delegates to the Ormer.
+// T must be a registered model struct.
+type Repository[T any] struct {
+	o Ormer
+}
+
+// Repo return the Repository of model T over o
+// for example:
+//
+//	users := orm.Repo[User](orm.NewOrm())
+//	user, err := users.Get(1)
+func Repo[T any](o Ormer) *Repository[T] {
+	return &Repository[T]{o: o}
+}
+
+// get the QuerySeter of model T filtered by the pk
+func (r *Repository[T]) queryPk(md *T, pk interface{}) (QuerySeter, error) {
+	qs, err := r.o.QueryTableE(md)
+	if err != nil {
+		return nil, err
+	}
+	mi, _ := modelCache.getByMd(md)
+	if mi.fields.pk == nil {
+		return nil, ErrMissPK
+	}
+	return qs.Filter(mi.fields.pk.name, pk), nil
+}
+
+// Get read the model by the pk, ErrNoRows is returned if it is not found
+func (r *Repository[T]) Get(pk interface{}) (*T, error) {
+	md := new(T)
+	qs, err := r.queryPk(md, pk)
+	if err != nil {
+		return nil, err
+	}
+	if err = qs.One(md); err != nil {
+		return nil, err
+	}
+	return md, nil
+}
+
+func (r *Repository[T]) GetWithCtx(ctx context.Context, pk interface{}) (*T, error) {
+	md := new(T)
+	qs, err := r.queryPk(md, pk)
+	if err != nil {
+		return nil, err
+	}
+	if err = qs.OneWithCtx(ctx, md); err != nil {
+		return nil, err
+	}
+	return md, nil
+}
+
+// Create insert the model, the pk field of md is set
+func (r *Repository[T]) Create(md *T) (int64, error) {
+	return r.o.Insert(md)
+}
+
+func (r *Repository[T]) CreateWithCtx(ctx context.Context, md *T) (int64, error) {
+	return r.o.InsertWithCtx(ctx, md)
+}
+
+// Update update the columns of the model by the pk, all columns if cols is empty
+func (r *Repository[T]) Update(md *T, cols ...string) (int64, error) {
+	return r.o.Update(md, cols...)
+}
+
+func (r *Repository[T]) UpdateWithCtx(ctx context.Context, md *T, cols ...string) (int64, error) {
+	return r.o.UpdateWithCtx(ctx, md, cols...)
+}
+
+// Delete delete the model by the pk
+func (r *Repository[T]) Delete(md *T) (int64, error) {
+	return r.o.Delete(md)
+}
+
+func (r *Repository[T]) DeleteWithCtx(ctx context.Context, md *T) (int64, error) {
+	return r.o.DeleteWithCtx(ctx, md)
+}
+
+// Find read the models matching cond, all the models if cond is nil
+func (r *Repository[T]) Find(cond *Condition) ([]T, error) {
+	qs, err := r.o.QueryTableE(new(T))
+	if err != nil {
+		return nil, err
+	}
+	var mds []T
+	if _, err = qs.SetCond(cond).All(&mds); err != nil {
+		return nil, err
+	}
+	return mds, nil
+}
+
+func (r *Repository[T]) FindWithCtx(ctx context.Context, cond *Condition) ([]T, error) {
+	qs, err := r.o.QueryTableE(new(T))
+	if err != nil {
+		return nil, err
+	}
+	var mds []T
+	if _, err = qs.SetCond(cond).AllWithCtx(ctx, &mds); err != nil {
+		return nil, err
+	}
+	return mds, nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2020 beego
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orm
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// QueryScoper returns the condition ANDed into the queries of the scoped model,
+// e.g. the tenant condition derived from ctx. nil means no condition.
+type QueryScoper func(ctx context.Context, table string) *Condition
+
+// the registered QueryScoper, nil if there is none
+var queryScoper atomic.Value
+
+// RegisterQueryScoper set the scoper of the QuerySeter queries, select, update and delete,
+// of the models which have a field tagged `orm:"query_scope"`, e.g. the tenant id field.
+// the queries by pk of Ormer, e.g. Ormer.Read, and the raw sql are not scoped.
+//
+//	orm.RegisterQueryScoper(func(ctx context.Context, table string) *orm.Condition {
+//		return orm.NewCondition().And("tenant_id", ctx.Value(tenantKey))
+//	})
+func RegisterQueryScoper(scoper QueryScoper) {
+	queryScoper.Store(scoper)
+}
+
+func getQueryScoper() QueryScoper {
+	scoper, _ := queryScoper.Load().(QueryScoper)
+	return scoper
+}
+
+// AND the condition of the query scoper into cond if mi is scoped
+func scopeCond(ctx context.Context, mi *modelInfo, cond *Condition) *Condition {
+	scoper := getQueryScoper()
+	if scoper == nil || !mi.queryScoped {
+		return cond
+	}
+	scope := scoper(ctx, mi.table)
+	if scope == nil || scope.IsEmpty() {
+		return cond
+	}
+	if cond == nil || cond.IsEmpty() {
+		return scope
+	}
+	return NewCondition().AndCond(cond).AndCond(scope)
+}
+
+// get the condition of the query with the condition of the query scoper
+func (o *querySet) scopedCond(ctx context.Context) *Condition {
+	return scopeCond(ctx, o.mi, o.cond)
+}
@@ -20,6 +20,8 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"math"
@@ -27,7 +29,9 @@ import (
 	"path/filepath"
 	"reflect"
 	"runtime"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -188,6 +192,17 @@ func TestGetDB(t *testing.T) {
 	}
 }
 
+func TestPing(t *testing.T) {
+	o := NewOrmUsingDB("default")
+	err := o.Ping(context.Background())
+	throwFail(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = o.Ping(ctx)
+	throwFail(t, AssertIs(err, context.Canceled))
+}
+
 func TestSyncDb(t *testing.T) {
 	RegisterModel(new(Data), new(DataNull), new(DataCustom))
 	RegisterModel(new(User))
@@ -204,6 +219,13 @@ func TestSyncDb(t *testing.T) {
 	RegisterModel(new(InLine))
 	RegisterModel(new(InLineOneToOne))
 	RegisterModel(new(IntegerPk))
+	RegisterModel(new(SequencePk))
+	RegisterModel(new(Member))
+	RegisterModel(new(Secret))
+	RegisterModel(new(CodecModel))
+	RegisterModel(new(ArrayModel))
+	RegisterModel(new(IndexModel))
+	RegisterModel(new(ScannerModel))
 	RegisterModel(new(UintPk))
 	RegisterModel(new(PtrPk))
 	RegisterModel(new(Index))
@@ -233,6 +255,13 @@ func TestRegisterModels(_ *testing.T) {
 	RegisterModel(new(InLine))
 	RegisterModel(new(InLineOneToOne))
 	RegisterModel(new(IntegerPk))
+	RegisterModel(new(SequencePk))
+	RegisterModel(new(Member))
+	RegisterModel(new(Secret))
+	RegisterModel(new(CodecModel))
+	RegisterModel(new(ArrayModel))
+	RegisterModel(new(IndexModel))
+	RegisterModel(new(ScannerModel))
 	RegisterModel(new(UintPk))
 	RegisterModel(new(PtrPk))
 	RegisterModel(new(Index))
@@ -246,6 +275,51 @@ func TestRegisterModels(_ *testing.T) {
 	dDbBaser = getDbAlias("default").DbBaser
 }
 
+func TestGenerateSQL(t *testing.T) {
+	queries, err := GenerateCreateSQL("default")
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(len(queries) >= len(modelCache.allOrdered()), true))
+	throwFail(t, AssertIs(strings.Contains(queries[0], "CREATE TABLE"), true))
+
+	// all the tables are synced
+	queries, err = GenerateAlterSQL("default")
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(len(queries), 0, queries))
+
+	// the dropped index is created again
+	_, indexes, err := modelCache.getDbCreateSQL(getDbAlias("default"))
+	throwFailNow(t, err)
+	idx := indexes["user"][0]
+	query := fmt.Sprintf("DROP INDEX %s", idx.Name)
+	if IsMysql {
+		query += " ON user"
+	}
+	_, err = dORM.Raw(query).Exec()
+	throwFailNow(t, err)
+	queries, err = GenerateAlterSQL("default")
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(len(queries), 1))
+	throwFail(t, AssertIs(queries[0], idx.SQL))
+	_, err = dORM.Raw(queries[0]).Exec()
+	throwFail(t, err)
+
+	// multi-column index of index(name) tag
+	named := false
+	for _, idx := range indexes["index_model"] {
+		if idx.Name == "idx_index_model_name" {
+			named = true
+			throwFail(t, AssertIs(strings.Contains(idx.SQL, "first_name"), true))
+			throwFail(t, AssertIs(strings.Contains(idx.SQL, "last_name"), true))
+		}
+	}
+	throwFail(t, AssertIs(named, true))
+	throwFail(t, AssertIs(len(indexes["index_model"]), 2))
+	throwFail(t, AssertIs(dDbBaser.IndexExists(context.Background(), getDbAlias("default").DB, "index_model", "idx_index_model_name"), true))
+
+	_, err = GenerateAlterSQL("nothing")
+	throwFail(t, AssertNot(err, nil))
+}
+
 func TestModelSyntax(t *testing.T) {
 	user := &User{}
 	ind := reflect.ValueOf(user).Elem()
@@ -683,6 +757,13 @@ func TestCRUD(t *testing.T) {
 	err = dORM.Read(u)
 	throwFail(t, AssertIs(err, ErrNoRows))
 
+	err = dORM.Read(&User{UserName: "slene"}, "UserNmae")
+	throwFail(t, AssertIs(errors.Is(err, ErrWrongColumn), true))
+	throwFail(t, AssertIs(err.Error(), "<Ormer> wrong db field/column name `UserNmae` for model `github.com/beego/beego/v2/client/orm.User`"))
+
+	err = dORM.Read(&User{}, "Posts")
+	throwFail(t, AssertIs(errors.Is(err, ErrWrongColumn), true))
+
 	ub := UserBig{}
 	ub.Name = "name"
 	id, err = dORM.Insert(&ub)
@@ -930,6 +1011,211 @@ func TestSpecifyIndex(t *testing.T) {
 
 	_ = dORM.QueryTable(&Index{}).Filter(`f1`, `1`).IgnoreIndex(`index_f1`, `index_f2`).One(index)
 	throwFailNow(t, AssertIs(index.F2, 2))
+
+	err := dORM.QueryTable(&Index{}).Filter(`f2`, `4`).ForceIndex(`index_f2`, `index_f1`).One(index)
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(index.F1, 3))
+}
+
+func TestSeekGt(t *testing.T) {
+	var users []*User
+	num, err := dORM.QueryTable("user").SeekGt("id", 1).Limit(2).All(&users)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 2))
+	throwFail(t, AssertIs(users[0].ID, 2))
+	throwFail(t, AssertIs(users[1].ID, 3))
+
+	// composite cursor
+	var posts []*Post
+	num, err = dORM.QueryTable("post").SeekGt("user,id", []interface{}{2, 1}).All(&posts)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num > 0, true))
+	for _, post := range posts {
+		throwFail(t, AssertIs(post.User.ID > 2 || post.User.ID == 2 && post.ID > 1, true))
+	}
+	for i := 1; i < len(posts); i++ {
+		prev, cur := posts[i-1], posts[i]
+		throwFail(t, AssertIs(prev.User.ID < cur.User.ID || prev.User.ID == cur.User.ID && prev.ID < cur.ID, true))
+	}
+
+	assert.Panics(t, func() { dORM.QueryTable("post").SeekGt("user,id", 2) })
+}
+
+func TestPaginate(t *testing.T) {
+	total, err := dORM.QueryTable("user").Count()
+	throwFail(t, err)
+
+	qs := dORM.QueryTable("user")
+	var ids []int
+	var cursor interface{}
+	for {
+		var users []*User
+		cursor, err = qs.Paginate(&users, "id", cursor, 2)
+		throwFail(t, err)
+		if cursor == nil {
+			break
+		}
+		throwFail(t, AssertIs(cursor, users[len(users)-1].ID))
+		for _, u := range users {
+			ids = append(ids, u.ID)
+		}
+	}
+	throwFail(t, AssertIs(len(ids), total))
+	for i := 1; i < len(ids); i++ {
+		throwFail(t, AssertIs(ids[i] > ids[i-1], true))
+	}
+
+	var posts []Post
+	cursor, err = dORM.QueryTable("post").Paginate(&posts, "user,id", nil, 1)
+	throwFail(t, err)
+	throwFail(t, AssertIs(len(posts), 1))
+	throwFail(t, AssertIs(len(cursor.([]interface{})), 2))
+
+	first := posts[0]
+	cursor, err = dORM.QueryTable("post").Paginate(&posts, "user,id", cursor, 1)
+	throwFail(t, err)
+	throwFail(t, AssertIs(len(posts), 1))
+	throwFail(t, AssertIs(posts[0].User.ID > first.User.ID || posts[0].User.ID == first.User.ID && posts[0].ID > first.ID, true))
+}
+
+func TestExplain(t *testing.T) {
+	qs := dORM.QueryTable("user").Filter("user_name", "slene")
+	plan, err := qs.Explain(context.Background())
+	throwFail(t, err)
+	throwFail(t, AssertIs(len(plan) > 0, true))
+	if IsSqlite {
+		throwFail(t, AssertIs(strings.Contains(plan, "SEARCH"), true))
+
+		_, err = qs.ExplainAnalyze(context.Background())
+		throwFail(t, AssertIs(err, ErrNotImplement))
+	}
+
+	// the query of QuerySeter still works
+	num, err := qs.Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+}
+
+func TestOrderByNulls(t *testing.T) {
+	var users []*User
+	num, err := dORM.QueryTable("user").OrderByNulls("profile", true, NullsLast).OrderByNulls("id", false, NullsFirst).All(&users)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num > 0, true))
+
+	column := "T0.`age`"
+	throwFail(t, AssertIs(newdbBaseMysql().GenerateOrderNulls(column, "DESC", NullsLast), "ISNULL(T0.`age`) ASC, T0.`age` DESC"))
+	throwFail(t, AssertIs(newdbBaseTidb().GenerateOrderNulls(column, "ASC", NullsFirst), "ISNULL(T0.`age`) DESC, T0.`age` ASC"))
+	throwFail(t, AssertIs(newdbBasePostgres().GenerateOrderNulls(column, "DESC", NullsLast), "T0.`age` DESC NULLS LAST"))
+	throwFail(t, AssertIs(newdbBaseSqlite().GenerateOrderNulls(column, "DESC", NullsFirst), "T0.`age` DESC"))
+}
+
+func TestClone(t *testing.T) {
+	base := dORM.QueryTable("user").Filter("user_name__in", "slene", "astaxie").RelatedSel("profile").OrderBy("id")
+
+	num, err := base.Clone().Filter("user_name", "slene").Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+
+	num, err = base.Clone().Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 2))
+
+	var users []*User
+	num, err = base.Clone().Filter("user_name", "astaxie").Limit(1).All(&users)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+	throwFail(t, AssertIs(users[0].Profile.Age, 30))
+
+	qs := base.(*querySet)
+	clone := base.Clone().(*querySet)
+	clone.related[0] = "posts"
+	clone.orders[0] = nil
+	throwFail(t, AssertIs(qs.related[0], "profile"))
+	throwFail(t, AssertNot(qs.orders[0], nil))
+	throwFail(t, AssertIs(qs.cond == clone.cond, false))
+}
+
+func TestUseTable(t *testing.T) {
+	_, err := dORM.Raw("CREATE TABLE tag_copy AS SELECT * FROM tag").Exec()
+	throwFailNow(t, err)
+	defer func() {
+		_, _ = dORM.Raw("DROP TABLE tag_copy").Exec()
+	}()
+
+	total, err := dORM.QueryTable("tag").Count()
+	throwFail(t, err)
+
+	qs := dORM.QueryTable("tag").UseTable("tag_copy")
+	num, err := qs.Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, total))
+
+	num, err = qs.Filter("name", "golang").Update(Params{"name": "go"})
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+
+	var tag Tag
+	err = qs.Filter("name", "go").One(&tag)
+	throwFail(t, err)
+	throwFail(t, AssertIs(tag.Name, "go"))
+
+	_, err = dORM.Raw("INSERT INTO tag_copy (id, name) VALUES (?, ?)", 100, "copy").Exec()
+	throwFail(t, err)
+
+	num, err = qs.Filter("id", 100).Delete()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+
+	num, err = qs.Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, total))
+
+	num, err = dORM.QueryTable("tag").Filter("name", "golang").Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+}
+
+func TestFullScanGuard(t *testing.T) {
+	EnableFullScanGuard("default", 0)
+	defer DisableFullScanGuard("default")
+	o := NewOrm()
+
+	num, err := o.QueryTable("user").Filter("id", 2).Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+
+	var users []*User
+	_, err = o.QueryTable("user").All(&users)
+	assert.True(t, errors.Is(err, ErrFullTableScan))
+
+	err = o.Raw("SELECT COUNT(*) FROM tag WHERE name = ?", "golang").QueryRow(&num)
+	assert.True(t, errors.Is(err, ErrFullTableScan))
+
+	// the rejected QueryRow returns the error instead of panic
+	err = o.Read(&User{Email: "slene@gmail.com"}, "Email")
+	assert.True(t, errors.Is(err, ErrFullTableScan))
+
+	// guard only works for the Ormers created after it's enabled
+	_, err = dORM.QueryTable("user").All(&users)
+	throwFail(t, err)
+}
+
+func TestQueryTableE(t *testing.T) {
+	qs, err := dORM.QueryTableE("user")
+	throwFail(t, err)
+	num, err := qs.Filter("user_name", "slene").Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+
+	qs, err = dORM.QueryTableE(new(User))
+	throwFail(t, err)
+	throwFail(t, AssertNot(qs, nil))
+
+	qs, err = dORM.QueryTableE("not_registered")
+	throwFail(t, AssertIs(qs, nil))
+	throwFail(t, AssertIs(errors.Is(err, ErrTableNotFound), true))
+
+	assert.Panics(t, func() { dORM.QueryTable("not_registered") })
 }
 
 func TestOperators(t *testing.T) {
@@ -1073,6 +1359,18 @@ func TestOperators(t *testing.T) {
 	num, err = qs.FilterRaw("profile_id", "IN (SELECT id FROM user_profile WHERE age=30)").Count()
 	throwFail(t, err)
 	throwFail(t, AssertIs(num, 1))
+
+	num, err = qs.FilterRaw("status", "IN (?, ?)", 1, 2).Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 2))
+
+	num, err = qs.Filter("status", 2).FilterRaw("", "user_name = ? OR user_name = ?", "slene", "astaxie").Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+
+	num, err = qs.FilterRaw("", "status IN (?, ?)", 1, 2).Exclude("user_name", "slene").Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
 }
 
 func TestSetCond(t *testing.T) {
@@ -1103,6 +1401,64 @@ func TestSetCond(t *testing.T) {
 	num, err = qs.SetCond(cond5).Count()
 	throwFail(t, err)
 	throwFail(t, AssertIs(num, 3))
+
+	cond6 := cond.And("user_name", "slene").OrRaw("", "user_name = ?", "astaxie")
+	num, err = qs.SetCond(cond6).Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 2))
+
+	cond7 := cond.And("is_staff", true).AndCond(cond.Raw("user_name", "= ?", "nobody").OrRaw("user_name", "IN (?, ?)", "slene", "astaxie"))
+	expected, err := qs.Filter("is_staff", true).Count()
+	throwFail(t, err)
+	num, err = qs.SetCond(cond7).Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, expected))
+}
+
+func TestFilterFullText(t *testing.T) {
+	sql, err := newdbBaseMysql().GenerateFullTextSQL([]string{"T0.`title`", "T0.`content`"})
+	throwFail(t, err)
+	throwFail(t, AssertIs(sql, "MATCH(T0.`title`, T0.`content`) AGAINST(? IN BOOLEAN MODE)"))
+
+	sql, err = newdbBasePostgres().GenerateFullTextSQL([]string{`T0."title"`})
+	throwFail(t, err)
+	throwFail(t, AssertIs(sql, `to_tsvector(concat_ws(' ', T0."title")) @@ plainto_tsquery(?)`))
+
+	_, err = newdbBaseSqlite().GenerateFullTextSQL([]string{`T0."title"`})
+	throwFail(t, AssertIs(err, ErrNotImplement))
+
+	qs := dORM.QueryTable("post")
+	assert.Panics(t, func() { qs.FilterFullText(nil, "orm") })
+	assert.Panics(t, func() { qs.FilterFullText([]string{"unknown"}, "orm") })
+
+	switch dORM.Driver().Type() {
+	case DRMySQL, DRPostgres:
+		cond := qs.FilterFullText([]string{"title", "content"}, "orm").GetCond()
+		throwFail(t, AssertIs(cond.IsEmpty(), false))
+	default:
+		assert.Panics(t, func() { qs.FilterFullText([]string{"title"}, "orm") })
+	}
+}
+
+func TestFilterCond(t *testing.T) {
+	qs := dORM.QueryTable("user").Filter("user_name__in", "slene", "astaxie")
+	cond := NewCondition().And("user_name", "slene").Or("user_name", "nobody")
+
+	num, err := qs.FilterCond(cond).Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+
+	var user User
+	throwFail(t, qs.ExcludeCond(cond).One(&user))
+	throwFail(t, AssertIs(user.UserName, "astaxie"))
+
+	num, err = qs.FilterCond(nil).ExcludeCond(NewCondition()).Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 2))
+
+	num, err = dORM.QueryTable("user").FilterCond(cond).Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 2))
 }
 
 func TestLimit(t *testing.T) {
@@ -1123,6 +1479,32 @@ func TestLimit(t *testing.T) {
 	num, err = qs.Limit(0, 2).All(&posts)
 	throwFail(t, err)
 	throwFail(t, AssertIs(num, 2))
+
+	assert.Panics(t, func() { qs.Limit(-2) })
+	assert.Panics(t, func() { qs.Limit(uint64(math.MaxUint64)) })
+	assert.Panics(t, func() { qs.Limit(1, -1) })
+	assert.Panics(t, func() { qs.Offset(-1) })
+}
+
+func TestUnlimited(t *testing.T) {
+	DefaultRowsLimit = 1
+	defer func() {
+		DefaultRowsLimit = -1
+	}()
+
+	var posts []*Post
+	qs := dORM.QueryTable("post")
+	num, err := qs.Limit(0).All(&posts)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+
+	num, err = qs.Limit(2).Unlimited().All(&posts)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 4))
+
+	num, err = qs.Unlimited().Offset(3).All(&posts)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
 }
 
 func TestOffset(t *testing.T) {
@@ -1221,6 +1603,50 @@ func TestAll(t *testing.T) {
 	throwFailNow(t, AssertIs(users3 == nil, false))
 }
 
+func TestReduce(t *testing.T) {
+	qs := dORM.QueryTable("user")
+	num, err := qs.Count()
+	throwFail(t, err)
+
+	names, err := qs.OrderBy("id").Reduce(context.Background(), "", func(acc, md interface{}) interface{} {
+		return acc.(string) + md.(*User).UserName + ","
+	})
+	throwFail(t, err)
+	throwFail(t, AssertIs(names, "slene,astaxie,nobody,"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cnt, err := qs.Reduce(ctx, int64(0), func(acc, md interface{}) interface{} {
+		cancel()
+		return acc.(int64) + 1
+	})
+	assert.True(t, errors.Is(err, context.Canceled))
+	throwFail(t, AssertIs(cnt.(int64) < num, true))
+}
+
+func TestChannel(t *testing.T) {
+	qs := dORM.QueryTable("user")
+	num, err := qs.Count()
+	throwFail(t, err)
+
+	rows, errs := qs.OrderBy("id").Channel(context.Background(), 1)
+	var names []string
+	for md := range rows {
+		names = append(names, md.(*User).UserName)
+	}
+	throwFail(t, <-errs)
+	throwFail(t, AssertIs(len(names), num))
+	throwFail(t, AssertIs(strings.Join(names, ","), "slene,astaxie,nobody"))
+
+	// stop sending when ctx is canceled
+	ctx, cancel := context.WithCancel(context.Background())
+	rows, errs = qs.Channel(ctx, 0)
+	<-rows
+	cancel()
+	for range rows {
+	}
+	assert.True(t, errors.Is(<-errs, context.Canceled))
+}
+
 func TestOne(t *testing.T) {
 	var user User
 	qs := dORM.QueryTable("user")
@@ -1243,6 +1669,22 @@ func TestOne(t *testing.T) {
 	throwFail(t, AssertIs(err, ErrNoRows))
 }
 
+func TestValuesOne(t *testing.T) {
+	var m Params
+	qs := dORM.QueryTable("user")
+	err := qs.Filter("user_name", "slene").ValuesOne(&m, "id", "user_name", "profile__age")
+	throwFail(t, err)
+	throwFail(t, AssertIs(len(m), 3))
+	throwFail(t, AssertIs(m["UserName"], "slene"))
+	throwFail(t, AssertIs(m["Profile__Age"], 28))
+
+	err = qs.Filter("user_name", "nothing").ValuesOne(&m)
+	throwFail(t, AssertIs(err, ErrNoRows))
+
+	err = qs.ValuesOne(&m)
+	throwFail(t, AssertIs(err, ErrMultiRows))
+}
+
 func TestValues(t *testing.T) {
 	var maps []Params
 	qs := dORM.QueryTable("user")
@@ -1304,6 +1746,31 @@ func TestValuesList(t *testing.T) {
 	}
 }
 
+func TestValuesListTyped(t *testing.T) {
+	var list []ParamsList
+	qs := dORM.QueryTable("user")
+
+	num, err := qs.OrderBy("Id").ValuesListTyped(&list, "ID", "UserName", "Status", "IsStaff", "Created", "Langs", "Profile", "Profile__Age")
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 3))
+	if num == 3 {
+		throwFail(t, AssertIs(list[0][0].(int), 2))
+		throwFail(t, AssertIs(list[0][1].(string), "slene"))
+		throwFail(t, AssertIs(list[0][2].(int16), 1))
+		_, ok := list[0][3].(bool)
+		throwFail(t, AssertIs(ok, true))
+		_, ok = list[0][4].(time.Time)
+		throwFail(t, AssertIs(ok, true))
+		_, ok = list[0][5].(SliceStringField)
+		throwFail(t, AssertIs(ok, true))
+		_, ok = list[0][6].(int)
+		throwFail(t, AssertIs(ok, true))
+		throwFail(t, AssertIs(list[0][7].(int16), 28))
+		throwFail(t, AssertIs(list[2][6], nil))
+		throwFail(t, AssertIs(list[2][7], nil))
+	}
+}
+
 func TestValuesFlat(t *testing.T) {
 	var list ParamsList
 	qs := dORM.QueryTable("user")
@@ -1373,6 +1840,30 @@ func TestRelatedSel(t *testing.T) {
 	throwFailNow(t, AssertIs(posts[1].User.UserName, "astaxie"))
 	throwFailNow(t, AssertIs(posts[2].User.UserName, "astaxie"))
 	throwFailNow(t, AssertIs(posts[3].User.UserName, "nobody"))
+
+	// only join the relations of the paths
+	recorder := NewSQLRecorder()
+	posts = nil
+	num, err = dORM.WithSQLRecorder(recorder).QueryTable("post").
+		Filter("user__user_name", "slene").RelatedSel("user__profile").All(&posts)
+	throwFail(t, err)
+	throwFailNow(t, AssertIs(num, 1))
+	throwFailNow(t, AssertNot(posts[0].User.Profile, nil))
+	throwFail(t, AssertIs(posts[0].User.UserName, "slene"))
+	throwFail(t, AssertIs(posts[0].User.Profile.Age, 28))
+	records := recorder.Records()
+	throwFailNow(t, AssertIs(len(records), 1))
+	throwFail(t, AssertIs(strings.Count(records[0].SQL, "JOIN"), 2))
+
+	recorder.Reset()
+	posts = nil
+	num, err = dORM.WithSQLRecorder(recorder).QueryTable("post").
+		Filter("user__user_name", "slene").RelatedSel("user").All(&posts)
+	throwFail(t, err)
+	throwFailNow(t, AssertIs(num, 1))
+	throwFail(t, AssertIs(posts[0].User.UserName, "slene"))
+	throwFail(t, AssertIs(posts[0].User.Profile == nil || posts[0].User.Profile.Age == 0, true))
+	throwFail(t, AssertIs(strings.Count(recorder.Records()[0].SQL, "JOIN"), 1))
 }
 
 func TestReverseQuery(t *testing.T) {
@@ -1594,37 +2085,111 @@ func TestLoadRelated(t *testing.T) {
 	throwFailNow(t, AssertIs(tag.Posts[0].User.UserName, "slene"))
 }
 
-func TestQueryM2M(t *testing.T) {
-	post := Post{ID: 4}
-	m2m := dORM.QueryM2M(&post, "Tags")
-
-	tag1 := []*Tag{{Name: "TestTag1"}, {Name: "TestTag2"}}
-	tag2 := &Tag{Name: "TestTag3"}
-	tag3 := []interface{}{&Tag{Name: "TestTag4"}}
-
-	tags := []interface{}{tag1[0], tag1[1], tag2, tag3[0]}
+func TestLoadRelatedBatch(t *testing.T) {
+	// load reverse foreign key
+	var users []*User
+	_, err := dORM.QueryTable("user").OrderBy("id").All(&users)
+	throwFailNow(t, err)
 
-	for _, tag := range tags {
-		_, err := dORM.Insert(tag)
+	num, err := dORM.LoadRelatedBatch(users, "Posts", hints.OrderBy("-Id"))
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(num, 4))
+	for _, user := range users {
+		one := User{ID: user.ID}
+		n, err := dORM.LoadRelated(&one, "Posts", hints.OrderBy("-Id"))
 		throwFailNow(t, err)
+		throwFailNow(t, AssertIs(len(user.Posts), n))
+		for i, post := range user.Posts {
+			throwFailNow(t, AssertIs(post.ID, one.Posts[i].ID))
+			throwFailNow(t, AssertIs(post.User.ID, user.ID))
+		}
+		if user.ID == 3 {
+			throwFailNow(t, AssertIs(user.Posts[0].Title, "Formatting"))
+		}
 	}
 
-	num, err := m2m.Add(tag1)
+	// load rel foreign key
+	var posts []Post
+	_, err = dORM.QueryTable("post").OrderBy("id").All(&posts)
 	throwFailNow(t, err)
-	throwFailNow(t, AssertIs(num, 2))
 
-	num, err = m2m.Add(tag2)
+	num, err = dORM.LoadRelatedBatch(&posts, "User", hints.DefaultRelDepth())
 	throwFailNow(t, err)
-	throwFailNow(t, AssertIs(num, 1))
+	throwFailNow(t, AssertIs(num, 3))
+	throwFailNow(t, AssertIs(posts[1].User.UserName, "astaxie"))
+	throwFailNow(t, AssertIs(posts[1].User.Profile.Age, 30))
+	throwFailNow(t, AssertIs(posts[0].User.UserName, "slene"))
 
-	num, err = m2m.Add(tag3)
+	// load rel m2m
+	_, err = dORM.LoadRelatedBatch(&posts, "Tags")
 	throwFailNow(t, err)
-	throwFailNow(t, AssertIs(num, 1))
+	for i := range posts {
+		one := Post{ID: posts[i].ID}
+		n, err := dORM.LoadRelated(&one, "Tags")
+		throwFailNow(t, err)
+		throwFailNow(t, AssertIs(len(posts[i].Tags), n))
+	}
+	throwFailNow(t, AssertIs(posts[1].Tags[0].Name, "golang"))
+
+	// load reverse m2m
+	tags := []*Tag{{ID: 1}, {ID: 2}}
+	_, err = dORM.LoadRelatedBatch(tags, "Posts")
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(len(tags[0].Posts), 3))
+	throwFailNow(t, AssertIs(tags[0].Posts[0].Title, "Introduction"))
+
+	// load reverse one to one
+	profiles := []*Profile{{ID: 3}}
+	num, err = dORM.LoadRelatedBatch(profiles, "User")
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(num, 1))
+	throwFailNow(t, AssertIs(profiles[0].User.UserName, "astaxie"))
+
+	num, err = dORM.LoadRelatedBatch([]*User{}, "Posts")
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(num, 0))
+}
+
+func TestQueryM2M(t *testing.T) {
+	post := Post{ID: 4}
+	m2m := dORM.QueryM2M(&post, "Tags")
+
+	tag1 := []*Tag{{Name: "TestTag1"}, {Name: "TestTag2"}}
+	tag2 := &Tag{Name: "TestTag3"}
+	tag3 := []interface{}{&Tag{Name: "TestTag4"}}
+
+	tags := []interface{}{tag1[0], tag1[1], tag2, tag3[0]}
+
+	for _, tag := range tags {
+		_, err := dORM.Insert(tag)
+		throwFailNow(t, err)
+	}
+
+	num, err := m2m.Add(tag1)
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(num, 2))
+
+	num, err = m2m.Add(tag2)
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(num, 1))
+
+	num, err = m2m.Add(tag3)
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(num, 1))
 
 	num, err = m2m.Count()
 	throwFailNow(t, err)
 	throwFailNow(t, AssertIs(num, 5))
 
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = m2m.RemoveWithCtx(ctx, tag3)
+	throwFailNow(t, AssertNot(err, nil))
+	_, err = m2m.ClearWithCtx(ctx)
+	throwFailNow(t, AssertNot(err, nil))
+	_, err = m2m.CountWithCtx(ctx)
+	throwFailNow(t, AssertNot(err, nil))
+
 	num, err = m2m.Remove(tag3)
 	throwFailNow(t, err)
 	throwFailNow(t, AssertIs(num, 1))
@@ -2093,132 +2658,1974 @@ func TestRawPrepare(t *testing.T) {
 			assert.Nil(t, err)
 			assert.Equal(t, id, tid+1)
 
-			result, err = pre.Exec("name3")
-			assert.Nil(t, err)
+			result, err = pre.Exec("name3")
+			assert.Nil(t, err)
+
+			id, err = result.LastInsertId()
+			assert.Nil(t, err)
+			assert.Equal(t, id, tid+2)
+
+			err = pre.Close()
+			assert.Nil(t, err)
+
+			res, err := dORM.Raw("DELETE FROM tag WHERE name IN (?, ?, ?)", []string{"name1", "name2", "name3"}).Exec()
+			assert.Nil(t, err)
+
+			num, err := res.RowsAffected()
+			assert.Nil(t, err)
+			assert.Equal(t, num, int64(3))
+		}
+	} else if IsPostgres {
+		pre, err = dORM.Raw(`INSERT INTO "tag" ("name") VALUES (?) RETURNING "id"`).Prepare()
+		assert.Nil(t, err)
+		if pre != nil {
+			_, err = pre.Exec("name1")
+			assert.Nil(t, err)
+
+			_, err = pre.Exec("name2")
+			assert.Nil(t, err)
+
+			_, err = pre.Exec("name3")
+			assert.Nil(t, err)
+
+			err = pre.Close()
+			assert.Nil(t, err)
+
+			res, err := dORM.Raw(`DELETE FROM "tag" WHERE "name" IN (?, ?, ?)`, []string{"name1", "name2", "name3"}).Exec()
+			assert.Nil(t, err)
+
+			if err == nil {
+				num, err := res.RowsAffected()
+				assert.Nil(t, err)
+				assert.Equal(t, num, int64(3))
+			}
+		}
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	qs := dORM.QueryTable("user")
+	num, err := qs.Filter("user_name", "slene").Filter("is_staff", false).Update(Params{
+		"is_staff":  true,
+		"is_active": true,
+	})
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+
+	// with join
+	num, err = qs.Filter("user_name", "slene").Filter("profile__age", 28).Filter("is_staff", true).Update(Params{
+		"is_staff": false,
+	})
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+
+	num, err = qs.Filter("user_name", "slene").Update(Params{
+		"Nums": ColValue(ColAdd, 100),
+	})
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+
+	num, err = qs.Filter("user_name", "slene").Update(Params{
+		"Nums": ColValue(ColMinus, 50),
+	})
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+
+	num, err = qs.Filter("user_name", "slene").Update(Params{
+		"Nums": ColValue(ColMultiply, 3),
+	})
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+
+	num, err = qs.Filter("user_name", "slene").Update(Params{
+		"Nums": ColValue(ColExcept, 5),
+	})
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+
+	user := User{UserName: "slene"}
+	err = dORM.Read(&user, "UserName")
+	throwFail(t, err)
+	throwFail(t, AssertIs(user.Nums, 30))
+
+	num, err = qs.Filter("user_name", "slene").Update(Params{
+		"Nums": Col("Status"),
+	})
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+
+	user = User{UserName: "slene"}
+	err = dORM.Read(&user, "UserName")
+	throwFail(t, err)
+	throwFail(t, AssertIs(user.Nums, int(user.Status)))
+
+	assert.Panics(t, func() {
+		_, _ = qs.Filter("user_name", "slene").Update(Params{"Nums": Col("UserName")})
+	})
+	assert.Panics(t, func() {
+		_, _ = qs.Filter("user_name", "slene").Update(Params{"Nums": Col("missing")})
+	})
+}
+
+func TestDelete(t *testing.T) {
+	qs := dORM.QueryTable("user_profile")
+	num, err := qs.Filter("user__user_name", "slene").Delete()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+
+	qs = dORM.QueryTable("user")
+	num, err = qs.Filter("user_name", "slene").Filter("profile__isnull", true).Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+
+	qs = dORM.QueryTable("comment")
+	num, err = qs.Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 6))
+
+	qs = dORM.QueryTable("post")
+	num, err = qs.Filter("Id", 3).Delete()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+
+	qs = dORM.QueryTable("comment")
+	num, err = qs.Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 4))
+
+	qs = dORM.QueryTable("comment")
+	num, err = qs.Filter("Post__User", 3).Delete()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 3))
+
+	qs = dORM.QueryTable("comment")
+	num, err = qs.Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+}
+
+func TestUnion(t *testing.T) {
+	qs1 := dORM.QueryTable("user").Filter("user_name__in", "slene", "astaxie")
+	qs2 := dORM.QueryTable("user").Filter("user_name__in", "astaxie", "nobody")
+
+	var users []*User
+	num, err := qs1.Union(qs2).OrderBy("-id").All(&users)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 3))
+	throwFail(t, AssertIs(users[0].ID > users[1].ID, true))
+	throwFail(t, AssertIs(users[1].ID > users[2].ID, true))
+
+	users = nil
+	num, err = qs1.UnionAll(qs2).All(&users, "id", "user_name")
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 4))
+
+	var maps []Params
+	num, err = qs1.Union(qs2).OrderBy("user_name").Limit(2, 1).Values(&maps, "user_name")
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 2))
+	throwFail(t, AssertIs(maps[0]["user_name"], "nobody"))
+	throwFail(t, AssertIs(maps[1]["user_name"], "slene"))
+
+	var lists []ParamsList
+	num, err = qs1.Union(qs2).UnionAll(qs1.Filter("user_name", "slene")).ValuesList(&lists, "id")
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 4))
+
+	_, err = dORM.QueryTable("user").Union(dORM.QueryTable("tag")).All(&users)
+	throwFail(t, AssertNot(err, nil))
+
+	_, err = qs1.Union(qs2).OrderBy("unknown").All(&users)
+	throwFail(t, AssertIs(errors.Is(err, ErrWrongColumn), true))
+}
+
+func TestReadInLocation(t *testing.T) {
+	user := &User{UserName: "slene"}
+	err := dORM.Read(user, "UserName")
+	throwFailNow(t, err)
+
+	// three hours ahead of the time zone of database
+	al, _ := dataBaseCache.get("default")
+	_, offset := user.Updated.In(al.TZ).Zone()
+	loc := time.FixedZone("test", offset+3600*3)
+
+	u := &User{UserName: "slene"}
+	err = dORM.ReadInLocation(u, loc, "UserName")
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(u.ID, user.ID))
+	// the same time is read in the given location, the wall clock is three hours ahead
+	throwFail(t, AssertIs(u.Updated.Location(), loc))
+	throwFail(t, AssertIs(u.Updated.Equal(user.Updated), true))
+	throwFail(t, AssertIs(u.Updated.Format(formatDateTime), user.Updated.In(al.TZ).Add(3*time.Hour).Format(formatDateTime)))
+
+	// the time args are formatted in the given location
+	before := user.Updated.Add(-time.Hour)
+	qs := dORM.QueryTable("user").Filter("user_name", "slene").Filter("updated__lte", before)
+	num, err := qs.Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 0))
+
+	var users []*User
+	num, err = qs.InLocation(loc).All(&users)
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(num, 1))
+	throwFail(t, AssertIs(users[0].ID, user.ID))
+
+	// nil means the time zone of database
+	u = &User{UserName: "slene"}
+	err = dORM.ReadInLocation(u, nil, "UserName")
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(u.Updated.Equal(user.Updated), true))
+}
+
+func TestRegisteredModels(t *testing.T) {
+	var user *ModelDesc
+	models := RegisteredModels()
+	for i := range models {
+		if models[i].Table == "user" {
+			user = &models[i]
+		}
+	}
+	throwFailNow(t, AssertNot(user, nil))
+	throwFail(t, AssertIs(user.Name, getFullName(reflect.TypeOf(User{}))))
+
+	fields := make(map[string]FieldDesc, len(user.Fields))
+	for _, fd := range user.Fields {
+		fields[fd.Name] = fd
+	}
+
+	id := fields["ID"]
+	throwFail(t, AssertIs(id.Column, "id"))
+	throwFail(t, AssertIs(id.Pk, true))
+	throwFail(t, AssertIs(id.Auto, true))
+	throwFail(t, AssertIs(id.Rel == nil, true))
+
+	name := fields["UserName"]
+	throwFail(t, AssertIs(name.Column, "user_name"))
+	throwFail(t, AssertIs(name.Unique, true))
+	throwFail(t, AssertIs(strings.Contains(name.DbType, "(30)"), true))
+
+	profile := fields["Profile"]
+	throwFail(t, AssertIs(profile.Column, "profile_id"))
+	throwFail(t, AssertIs(profile.Null, true))
+	throwFailNow(t, AssertNot(profile.Rel, nil))
+	throwFail(t, AssertIs(profile.Rel.Type, "one"))
+	throwFail(t, AssertIs(profile.Rel.Table, "user_profile"))
+	throwFail(t, AssertIs(profile.Rel.OnDelete, "set_null"))
+
+	posts := fields["Posts"]
+	throwFail(t, AssertIs(posts.Column, ""))
+	throwFailNow(t, AssertNot(posts.Rel, nil))
+	throwFail(t, AssertIs(posts.Rel.Type, "reverse_many"))
+	throwFail(t, AssertIs(posts.Rel.Table, "post"))
+}
+
+func TestScannerField(t *testing.T) {
+	mi, _ := modelCache.getByMd(&ScannerModel{})
+	throwFail(t, AssertIs(mi.fields.GetByName("Code").isScanner, true))
+	throwFail(t, AssertIs(mi.fields.GetByName("Code").fieldType, TypeVarCharField))
+	throwFail(t, AssertIs(mi.fields.GetByName("Amount").fieldType, TypeBigIntegerField))
+	throwFail(t, AssertIs(mi.fields.GetByName("Count").fieldType, TypeIntegerField))
+
+	m := &ScannerModel{
+		Code:   HexID{1, 2, 3, 4},
+		Amount: 1999,
+		Count:  sql.NullInt32{Int32: 3, Valid: true},
+	}
+	id, err := dORM.Insert(m)
+	throwFailNow(t, err)
+
+	var values []Params
+	_, err = dORM.QueryTable("scanner_model").Filter("id", id).Values(&values, "code")
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(values[0]["Code"], "01020304"))
+
+	r := &ScannerModel{ID: int(id)}
+	throwFailNow(t, dORM.Read(r))
+	throwFail(t, AssertIs(r.Code, HexID{1, 2, 3, 4}))
+	throwFail(t, AssertIs(r.Parent == nil, true))
+	throwFail(t, AssertIs(r.Amount, Cents(1999)))
+	throwFail(t, AssertIs(r.Count, sql.NullInt32{Int32: 3, Valid: true}))
+
+	parent := HexID{0xa, 0xb, 0xc, 0xd}
+	r.Parent = &parent
+	r.Count = sql.NullInt32{}
+	_, err = dORM.Update(r)
+	throwFailNow(t, err)
+
+	// filter by the Valuer value
+	var ms []*ScannerModel
+	num, err := dORM.QueryTable("scanner_model").Filter("code", HexID{1, 2, 3, 4}).Filter("parent", &parent).All(&ms)
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(num, 1))
+	throwFailNow(t, AssertNot(ms[0].Parent, nil))
+	throwFail(t, AssertIs(*ms[0].Parent, parent))
+	throwFail(t, AssertIs(ms[0].Count.Valid, false))
+
+	num, err = dORM.QueryTable("scanner_model").Filter("amount__gt", Cents(2000)).Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 0))
+
+	// the error of Valuer is reported
+	_, ok, err := getValuerArg(Cents(-1))
+	throwFail(t, AssertIs(ok, true))
+	throwFail(t, AssertNot(err, nil))
+	func() {
+		defer func() {
+			throwFail(t, AssertNot(recover(), nil))
+		}()
+		_, _ = dORM.QueryTable("scanner_model").Filter("amount", Cents(-1)).Count()
+	}()
+}
+
+func TestAggregateColumn(t *testing.T) {
+	var profiles []*Profile
+	qs := dORM.QueryTable("user_profile").Filter("user__user_name__in", "slene", "astaxie")
+	num, err := qs.All(&profiles)
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(num > 0, true))
+	sum, max, min := int64(0), int16(0), int16(math.MaxInt16)
+	for _, p := range profiles {
+		sum += int64(p.Age)
+		if p.Age > max {
+			max = p.Age
+		}
+		if p.Age < min {
+			min = p.Age
+		}
+	}
+
+	var total int64
+	throwFail(t, qs.Sum("age", &total))
+	throwFail(t, AssertIs(total, sum))
+
+	var age int16
+	throwFail(t, qs.Max("age", &age))
+	throwFail(t, AssertIs(age, max))
+	throwFail(t, qs.Min("age", &age))
+	throwFail(t, AssertIs(age, min))
+
+	var avg float64
+	throwFail(t, qs.Avg("age", &avg))
+	throwFail(t, AssertIs(avg, float64(sum)/float64(num)))
+
+	// the column of relation
+	total = 0
+	throwFail(t, dORM.QueryTable("user").Filter("user_name__in", "slene", "astaxie").Sum("profile__age", &total))
+	throwFail(t, AssertIs(total, sum))
+
+	var updated time.Time
+	throwFail(t, dORM.QueryTable("user").Max("updated", &updated))
+	throwFail(t, AssertIs(updated.IsZero(), false))
+
+	// NULL of empty set
+	total = 1
+	throwFail(t, dORM.QueryTable("user").Filter("user_name", "nothing").Sum("nums", &total))
+	throwFail(t, AssertIs(total, 0))
+	throwFail(t, dORM.QueryTable("user").Filter("user_name", "nothing").Max("updated", &updated))
+	throwFail(t, AssertIs(updated.IsZero(), true))
+
+	err = qs.Sum("nothing", &total)
+	throwFail(t, AssertIs(errors.Is(err, ErrWrongColumn), true))
+	err = qs.Sum("age", total)
+	throwFail(t, AssertIs(errors.Is(err, ErrArgs), true))
+}
+
+func TestCountDistinct(t *testing.T) {
+	qs := dORM.QueryTable("post")
+	var ids ParamsList
+	_, err := qs.ValuesFlat(&ids, "user__id")
+	throwFailNow(t, err)
+	users := make(map[interface{}]bool)
+	for _, id := range ids {
+		users[id] = true
+	}
+
+	num, err := qs.CountDistinct("user")
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, len(users)))
+
+	num, err = qs.Filter("user__user_name", "slene").CountDistinct("user__id")
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+
+	num, err = qs.Filter("title", "nothing").CountDistinct("user")
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 0))
+
+	_, err = qs.CountDistinct("nothing")
+	throwFail(t, AssertIs(errors.Is(err, ErrWrongColumn), true))
+}
+
+func TestHaving(t *testing.T) {
+	type DeptTotal struct {
+		DeptName string
+		Total    int
+	}
+	var sums []DeptTotal
+	qs := dORM.QueryTable("dept_info").Aggregate("dept_name,sum(salary) as total").GroupBy("dept_name")
+	n, err := qs.Having("total__gt", 3000).All(&sums)
+	throwFail(t, err)
+	throwFail(t, AssertIs(n, 1))
+	throwFail(t, AssertIs(sums[0].DeptName, "B"))
+	throwFail(t, AssertIs(sums[0].Total, 9000))
+
+	n, err = qs.Having("total__between", 1000, 3000).Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(n, 1))
+
+	n, err = qs.Having("total__in", 3000, 9000).Having("total__lt", 5000).Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(n, 1))
+
+	_, err = qs.Having("nothing__gt", 1).All(&sums)
+	throwFail(t, AssertNot(err, nil))
+	_, err = qs.Having("total__contains", 1).Count()
+	throwFail(t, AssertNot(err, nil))
+
+	aliases := getAggregateAliases("dept_name, coalesce(sum(salary), 0) AS `total`,max(salary) as max")
+	throwFail(t, AssertIs(len(aliases), 2))
+	throwFail(t, AssertIs(aliases["total"], "coalesce(sum(salary), 0)"))
+	throwFail(t, AssertIs(aliases["max"], "max(salary)"))
+}
+
+func TestInsertOrIgnore(t *testing.T) {
+	user := User{UserName: "ignore", Email: "ignore@gmail.com"}
+	num, err := dORM.InsertOrIgnore(&user, "UserName")
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(num, 1))
+	throwFail(t, AssertNot(user.ID, 0))
+
+	// skip the conflicted row, and the existing row is not changed
+	dup := User{UserName: "ignore", Email: "dup@gmail.com"}
+	num, err = dORM.InsertOrIgnore(&dup, "UserName")
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 0))
+	throwFail(t, AssertIs(dup.ID, 0))
+
+	num, err = dORM.InsertOrIgnore(&dup)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 0))
+
+	u := User{UserName: "ignore"}
+	throwFailNow(t, dORM.Read(&u, "UserName"))
+	throwFail(t, AssertIs(u.ID, user.ID))
+	throwFail(t, AssertIs(u.Email, "ignore@gmail.com"))
+
+	_, err = dORM.InsertOrIgnore(&dup, "nothing")
+	throwFail(t, AssertIs(errors.Is(err, ErrWrongColumn), !IsMysql))
+
+	num, err = dORM.Delete(&user)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+}
+
+func TestInsertOrUpdatePartialIndex(t *testing.T) {
+	col, where := splitConflictTarget("user_name where deleted_at IS NULL")
+	throwFail(t, AssertIs(col, "user_name"))
+	throwFail(t, AssertIs(where, " WHERE deleted_at IS NULL"))
+	col, where = splitConflictTarget("user_name")
+	throwFail(t, AssertIs(col, "user_name"))
+	throwFail(t, AssertIs(where, ""))
+
+	// generate the sql of postgres without database
+	al := *getDbAlias("default")
+	al.Driver = DRPostgres
+	al.DbBaser = newdbBasePostgres()
+	q := new(dryRunQuerier)
+	user := &User{UserName: "partial"}
+	mi, _ := modelCache.getByMd(user)
+	_, _ = al.DbBaser.InsertOrUpdate(context.Background(), q, mi, reflect.ValueOf(user).Elem(), &al, "user_name WHERE status = 0")
+	query, _ := q.last()
+	throwFail(t, AssertIs(strings.Contains(query, `ON CONFLICT (user_name) WHERE status = 0 DO UPDATE SET`), true, query))
+}
+
+func TestInsertOrUpdateWhere(t *testing.T) {
+	// generate the sql of postgres without database
+	al := *getDbAlias("default")
+	al.Driver = DRPostgres
+	al.DbBaser = newdbBasePostgres()
+	q := new(dryRunQuerier)
+	post := &Post{ID: 1, Title: "newer", User: &User{ID: 2}}
+	mi, _ := modelCache.getByMd(post)
+	_, err := al.DbBaser.InsertOrUpdate(context.Background(), q, mi, reflect.ValueOf(post).Elem(), &al,
+		"id", "WHERE EXCLUDED.updated > post.updated")
+	query, _ := q.last()
+	throwFail(t, AssertIs(strings.Contains(query, `ON CONFLICT (id) DO UPDATE SET`), true, query))
+	throwFail(t, AssertIs(strings.HasSuffix(query, ` WHERE EXCLUDED.updated > post.updated RETURNING "id"`), true, query))
+
+	// the condition isn't taken as the conflict column
+	_, _ = al.DbBaser.InsertOrUpdate(context.Background(), q, mi, reflect.ValueOf(post).Elem(), &al,
+		"id", "where EXCLUDED.updated > post.updated", "title=title")
+	query, _ = q.last()
+	throwFail(t, AssertIs(strings.Contains(query, ` where EXCLUDED.updated > post.updated`), true, query))
+
+	al.Driver = DRMySQL
+	al.DbBaser = newdbBaseMysql()
+	_, err = al.DbBaser.InsertOrUpdate(context.Background(), q, mi, reflect.ValueOf(post).Elem(), &al,
+		"WHERE VALUES(updated) > updated")
+	throwFail(t, AssertNot(err, nil))
+}
+
+func TestReadOrCreateMulti(t *testing.T) {
+	users := []*User{{UserName: "slene"}, {UserName: "batch", Email: "batch@gmail.com"}, {UserName: "batch"}}
+	created, err := dORM.ReadOrCreateMulti(&users, []string{"UserName"})
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(len(created), 3))
+	throwFail(t, AssertIs(created[0], false))
+	throwFail(t, AssertIs(created[1], true))
+	throwFail(t, AssertIs(created[2], false))
+	throwFail(t, AssertNot(users[0].ID, 0))
+	throwFail(t, AssertNot(users[0].Email, ""))
+	throwFail(t, AssertNot(users[1].ID, 0))
+	throwFail(t, AssertIs(users[2].ID, users[1].ID))
+	throwFail(t, AssertIs(users[2].Email, "batch@gmail.com"))
+
+	// the slice of values and more lookup columns
+	values := []User{{UserName: "batch", Email: "batch@gmail.com"}, {UserName: "batch2", Email: "batch@gmail.com"}}
+	created, err = dORM.ReadOrCreateMulti(values, []string{"UserName", "Email"})
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(created[0], false))
+	throwFail(t, AssertIs(created[1], true))
+	throwFail(t, AssertIs(values[0].ID, users[1].ID))
+	throwFail(t, AssertNot(values[1].ID, users[1].ID))
+
+	// the lookup is split by the placeholder limit
+	SetMaxPlaceholders(dORM.Driver().Type(), 2)
+	values = []User{{UserName: "batch", Email: "batch@gmail.com"}, {UserName: "batch2", Email: "batch@gmail.com"}}
+	created, err = dORM.ReadOrCreateMulti(values, []string{"UserName", "Email"})
+	SetMaxPlaceholders(dORM.Driver().Type(), defaultMaxPlaceholders[dORM.Driver().Type()])
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(created[0], false))
+	throwFail(t, AssertIs(created[1], false))
+	throwFail(t, AssertIs(values[0].ID, users[1].ID))
+
+	// the missing rows are created if NotFoundError is enabled
+	EnableNotFoundError(true)
+	values = []User{{UserName: "batch3"}}
+	created, err = dORM.ReadOrCreateMulti(values, []string{"UserName"})
+	EnableNotFoundError(false)
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(created[0], true))
+	throwFail(t, AssertNot(values[0].ID, 0))
+
+	// the values joined by space are keyed apart
+	mi, _ := modelCache.getByFullName(getFullName(reflect.TypeOf(User{})))
+	fis := []*fieldInfo{mi.fields.GetByName("UserName"), mi.fields.GetByName("Email")}
+	throwFail(t, AssertNot(lookupKey(reflect.ValueOf(User{UserName: "a", Email: "b c"}), fis),
+		lookupKey(reflect.ValueOf(User{UserName: "a b", Email: "c"}), fis)))
+
+	_, err = dORM.ReadOrCreateMulti(users, []string{"nothing"})
+	throwFail(t, AssertIs(errors.Is(err, ErrWrongColumn), true))
+	_, err = dORM.ReadOrCreateMulti(users[0], []string{"UserName"})
+	throwFail(t, AssertIs(err, ErrArgs))
+
+	num, err := dORM.QueryTable("user").Filter("UserName__startswith", "batch").Delete()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 3))
+}
+
+func TestLogRedact(t *testing.T) {
+	logger := &testQueryLogger{}
+	SetLogger(logger)
+	defer SetLogger(nil)
+	RegisterLogRedactor(func(table, col string, val interface{}) interface{} {
+		if table == "user" && col == "email" {
+			return "hidden"
+		}
+		return val
+	})
+	defer RegisterLogRedactor(nil)
+	Debug = true
+	defer func() {
+		Debug = false
+	}()
+
+	o := NewOrm()
+	user := &User{UserName: "redact", Email: "redact@gmail.com", Password: "secret"}
+	_, err := o.Insert(user)
+	throwFailNow(t, err)
+	_, err = o.QueryTable(user).Filter("Password", "secret").Update(Params{"Password": "secret2"})
+	throwFail(t, err)
+	_, err = o.QueryTable(user).Filter("Password__in", "secret", "secret2").Count()
+	throwFail(t, err)
+	_, err = o.Delete(user)
+	throwFail(t, err)
+
+	logged := fmt.Sprint(logger.args)
+	assert.NotContains(t, logged, "secret")
+	assert.NotContains(t, logged, "redact@gmail.com")
+	assert.Contains(t, logged, LogRedacted)
+	assert.Contains(t, logged, "hidden")
+	assert.Contains(t, logged, "redact")
+	assert.Equal(t, "secret", user.Password)
+}
+
+func TestTxDefaultContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	err := dORM.DoTxWithCtx(ctx, func(c context.Context, txOrm TxOrmer) error {
+		throwFail(t, txOrm.Read(&User{ID: 2}))
+		cancel()
+		user := &User{ID: 2}
+		err := txOrm.Read(user)
+		throwFail(t, AssertIs(errors.Is(err, context.Canceled), true))
+		_, err = txOrm.QueryTable(user).Count()
+		throwFail(t, AssertIs(errors.Is(err, context.Canceled), true))
+		return nil
+	})
+	throwFail(t, AssertIs(errors.Is(err, context.Canceled), true))
+}
+
+func TestRawDBAndTx(t *testing.T) {
+	Q := dDbBaser.TableQuote()
+
+	db := dORM.RawDB()
+	throwFailNow(t, AssertNot(db, nil))
+	var num int
+	throwFail(t, db.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %suser%s", Q, Q)).Scan(&num))
+	throwFail(t, AssertIs(num, 3))
+
+	to, err := dORM.Begin()
+	throwFailNow(t, err)
+	tx := to.DB()
+	throwFailNow(t, AssertNot(tx, nil))
+	query := fmt.Sprintf("UPDATE %suser%s SET %sStatus%s = ? WHERE %suser_name%s = ?", Q, Q, Q, Q, Q, Q)
+	dDbBaser.ReplaceMarks(&query)
+	_, err = tx.Exec(query, 9, "nobody")
+	throwFail(t, err)
+
+	// the update is in the transaction
+	cnt, err := to.QueryTable("user").Filter("Status", 9).Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(cnt, 1))
+
+	throwFail(t, to.Rollback())
+	throwFail(t, AssertIs(to.DB() == nil, true))
+	cnt, err = dORM.QueryTable("user").Filter("Status", 9).Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(cnt, 0))
+}
+
+func TestInsertMultiIDs(t *testing.T) {
+	tags := []*Tag{{Name: "multi_1"}, {Name: "multi_2"}, {Name: "multi_3"}}
+	num, err := dORM.InsertMulti(2, tags)
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(num, 3))
+
+	values := []Tag{{Name: "multi_4"}, {Name: "multi_5"}}
+	num, err = dORM.InsertMulti(10, values)
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(num, 2))
+
+	for _, tag := range append(tags, &values[0], &values[1]) {
+		throwFailNow(t, AssertNot(tag.ID, 0))
+		read := Tag{ID: tag.ID}
+		throwFail(t, dORM.Read(&read))
+		throwFail(t, AssertIs(read.Name, tag.Name))
+	}
+
+	num, err = dORM.QueryTable("tag").Filter("name__startswith", "multi_").Delete()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 5))
+}
+
+func TestInsertMultiResult(t *testing.T) {
+	tags := []*Tag{{Name: "result_1"}, {Name: "result_2"}, {Name: "result_3"}}
+	results, err := dORM.InsertMultiResult(2, tags)
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(len(results), 2))
+	num, err := results[0].RowsAffected()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 2))
+	num, err = results[1].RowsAffected()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+	for _, tag := range tags {
+		throwFail(t, AssertNot(tag.ID, 0))
+	}
+
+	// one statement for each row
+	results, err = dORM.InsertMultiResult(0, []Tag{{Name: "result_4"}, {Name: "result_5"}})
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(len(results), 2))
+
+	_, err = dORM.InsertMultiResult(2, []*Tag{})
+	throwFail(t, AssertIs(err, ErrArgs))
+
+	num, err = dORM.QueryTable("tag").Filter("name__startswith", "result_").Delete()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 5))
+}
+
+func TestPlaceholderDbBaser(t *testing.T) {
+	// the sql built by the base uses the placeholders of the driver
+	d := newdbBasePlaceholder(DRMySQL, PlaceholderAtP)
+	q := new(dryRunQuerier)
+	user := &User{ID: 1}
+	mi, _ := modelCache.getByMd(user)
+	_, _ = d.Delete(context.Background(), q, mi, reflect.ValueOf(user).Elem(), time.UTC, nil)
+	query, _ := q.last()
+	throwFail(t, AssertIs(strings.Contains(query, "`id` = @p1"), true, query))
+}
+
+func TestQuerySetSet(t *testing.T) {
+	dry := dORM.DryRun()
+	_, err := dry.QueryTable("user").Filter("user_name", "slene").
+		Set("status", 3).
+		Set("nums", ColValue(ColAdd, 10)).
+		Set("email", "slene@set").
+		Update(Params{"is_staff": true})
+	throwFail(t, err)
+	query, args := dry.LastSQL()
+	Q := dDbBaser.TableQuote()
+	throwFail(t, AssertIs(strings.Index(query, Q+"Status"+Q) < strings.Index(query, Q+"nums"+Q), true))
+	throwFail(t, AssertIs(strings.Index(query, Q+"nums"+Q) < strings.Index(query, Q+"email"+Q), true))
+	throwFail(t, AssertIs(strings.Index(query, Q+"email"+Q) < strings.Index(query, Q+"is_staff"+Q), true))
+	throwFail(t, AssertIs(args[0], 3))
+	throwFail(t, AssertIs(args[1], 10))
+	throwFail(t, AssertIs(args[2], "slene@set"))
+	throwFail(t, AssertIs(args[3], true))
+
+	qs := dORM.QueryTable("user").Filter("user_name", "astaxie")
+	var user User
+	throwFail(t, qs.One(&user))
+
+	// the later value of the same column wins
+	num, err := qs.Set("nums", ColValue(ColAdd, 5)).Set("email", "a@set").Set("email", "b@set").Update(nil)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+
+	var updated User
+	throwFail(t, qs.One(&updated))
+	throwFail(t, AssertIs(updated.Nums, user.Nums+5))
+	throwFail(t, AssertIs(updated.Email, "b@set"))
+
+	// Set does not change the original QuerySeter
+	num, err = qs.Update(Params{"email": user.Email, "nums": user.Nums})
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+}
+
+func TestNoStmtCache(t *testing.T) {
+	err := RegisterDataBase("stmt-cache", DBARGS.Driver, DBARGS.Source, MaxStmtCacheSize(10))
+	throwFail(t, err)
+	al := getDbAlias("stmt-cache")
+	o := NewOrmUsingDB("stmt-cache")
+
+	num, err := o.QueryTable("user").NoStmtCache().Filter("user_name", "slene").Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+	var name string
+	Q := dDbBaser.TableQuote()
+	query := fmt.Sprintf("SELECT user_name FROM %suser%s WHERE id = ?", Q, Q)
+	throwFail(t, o.Raw(query, 2).NoStmtCache().QueryRow(&name))
+	throwFail(t, AssertIs(name, "slene"))
+	throwFail(t, AssertIs(al.DB.stmtDecorators.Len(), 0))
+
+	num, err = o.QueryTable("user").Filter("user_name", "slene").Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+	throwFail(t, AssertIs(al.DB.stmtDecorators.Len(), 1))
+
+	// the statements aren't cached through the query log and scan guard
+	q := noStmtCacheQuerier(newDbQueryLog(al, newScanGuard(al, al.DB)))
+	guard, ok := q.(*dbQueryLog).db.(*scanGuard)
+	throwFailNow(t, AssertIs(ok, true))
+	throwFail(t, AssertIs(guard.db.(*DB).stmtDecorators == nil, true))
+	throwFail(t, AssertIs(al.DB.stmtDecorators == nil, false))
+}
+
+func TestAllInChunks(t *testing.T) {
+	ids := []int{1, 2, 3, 4, 5, 6, 7}
+	qs := dORM.QueryTable("post").Filter("id__in", ids).Filter("id__gt", 0)
+	var expected []*Post
+	total, err := qs.OrderBy("id").All(&expected)
+	throwFail(t, err)
+
+	typ := dORM.Driver().Type()
+	SetMaxPlaceholders(typ, 3)
+	defer SetMaxPlaceholders(typ, defaultMaxPlaceholders[typ])
+
+	conds, err := qs.(*querySet).splitInChunks()
+	throwFail(t, err)
+	throwFail(t, AssertIs(len(conds), 4))
+
+	var posts []*Post
+	num, err := qs.All(&posts)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, total))
+	throwFail(t, AssertIs(len(posts), len(expected)))
+	sort.Slice(posts, func(i, j int) bool { return posts[i].ID < posts[j].ID })
+	for i, post := range posts {
+		throwFail(t, AssertIs(post.ID, expected[i].ID))
+	}
+
+	// the duplicate values don't read the same row twice
+	dup := append(append([]int{}, ids...), ids...)
+	num, err = dORM.QueryTable("post").Filter("id__in", dup).All(&posts)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, total))
+
+	// the order of the whole rows can't apply to the chunks
+	_, err = qs.OrderBy("-id").All(&posts)
+	throwFail(t, AssertNot(err, nil))
+	_, err = qs.Distinct().All(&posts)
+	throwFail(t, AssertNot(err, nil))
+
+	var values []Post
+	num, err = dORM.QueryTable("post").Filter("id__in", ids[0], ids[1], ids[2], ids[3], ids[4], ids[5], ids[6]).All(&values, "id")
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, total))
+
+	_, err = qs.Limit(2).All(&posts)
+	throwFail(t, AssertNot(err, nil))
+
+	// the IN list joined by OR or NOT can't be split
+	conds, err = dORM.QueryTable("post").Filter("id__gt", 0).
+		SetCond(NewCondition().Or("id__in", ids)).(*querySet).splitInChunks()
+	throwFail(t, err)
+	throwFail(t, AssertIs(len(conds), 0))
+	conds, err = dORM.QueryTable("post").Exclude("id__in", ids).(*querySet).splitInChunks()
+	throwFail(t, err)
+	throwFail(t, AssertIs(len(conds), 0))
+}
+
+func TestRawQueryMulti(t *testing.T) {
+	Q := dDbBaser.TableQuote()
+	query := fmt.Sprintf("SELECT * FROM %suser%s ORDER BY id", Q, Q)
+
+	var expected []User
+	total, err := dORM.Raw(query).QueryRows(&expected)
+	throwFail(t, err)
+
+	var users []User
+	num, err := dORM.Raw(query).QueryMulti(&users)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, total))
+	throwFail(t, AssertIs(len(users), len(expected)))
+	for i, user := range users {
+		throwFail(t, AssertIs(user.UserName, expected[i].UserName))
+	}
+
+	// the query returns one result set only
+	var names []string
+	num, err = dORM.Raw(query).QueryMulti(&users, &names)
+	throwFail(t, AssertNot(err, nil))
+	throwFail(t, AssertIs(num, total))
+	throwFail(t, AssertIs(len(names), 0))
+
+	// the ctx of caller is used
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = dORM.Raw(query).QueryMultiWithCtx(ctx, &users)
+	throwFail(t, AssertIs(errors.Is(err, context.Canceled), true))
+}
+
+func TestRawWithCtx(t *testing.T) {
+	Q := dDbBaser.TableQuote()
+	query := fmt.Sprintf("SELECT user_name FROM %suser%s WHERE id = ?", Q, Q)
+	ctx := context.Background()
+
+	var name string
+	throwFail(t, dORM.Raw(query, 2).QueryRowWithCtx(ctx, &name))
+	throwFail(t, AssertIs(name, "slene"))
+
+	var names []string
+	num, err := dORM.Raw(query, 2).QueryRowsWithCtx(ctx, &names)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+	throwFail(t, AssertIs(names[0], "slene"))
+
+	update := fmt.Sprintf("UPDATE %suser%s SET user_name = ? WHERE id = ?", Q, Q)
+	res, err := dORM.Raw(update, "slene", 2).ExecWithCtx(ctx)
+	throwFail(t, err)
+	num, err = res.RowsAffected()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+
+	// the canceled context stops the queries
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	throwFail(t, AssertNot(dORM.Raw(query, 2).QueryRowWithCtx(ctx, &name), nil))
+	_, err = dORM.Raw(query, 2).QueryRowsWithCtx(ctx, &names)
+	throwFail(t, AssertNot(err, nil))
+	_, err = dORM.Raw(update, "slene", 2).ExecWithCtx(ctx)
+	throwFail(t, AssertNot(err, nil))
+}
+
+func TestSetNowFunc(t *testing.T) {
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, DefaultTimeLoc)
+	SetNowFunc(func() time.Time { return now })
+	defer SetNowFunc(nil)
+
+	user := User{ID: 2}
+	throwFail(t, dORM.Read(&user))
+	_, err := dORM.Update(&user)
+	throwFail(t, err)
+	throwFail(t, AssertIs(user.Updated.Equal(now), true))
+	user = User{ID: 2}
+	throwFail(t, dORM.Read(&user))
+	throwFail(t, AssertIs(user.Updated.Equal(now), true))
+
+	// the auto_now field is appended to the specified columns
+	now = now.Add(time.Hour)
+	num, err := dORM.Update(&user, "Nums")
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+	user = User{ID: 2}
+	throwFail(t, dORM.Read(&user))
+	throwFail(t, AssertIs(user.Updated.Equal(now), true))
+
+	// the duration of transaction is measured by the real clock
+	to := NewFilterTxOrmDecorator(&filterMockOrm{}, nil, "now_tx")
+	throwFail(t, AssertIs(time.Since(to.(*filterOrmDecorator).txStartTime) < time.Minute, true))
+
+	SetNowFunc(nil)
+	throwFail(t, AssertIs(nowFunc().After(now), true))
+}
+
+func TestGroupConcat(t *testing.T) {
+	var lists []ParamsList
+	_, err := dORM.QueryTable("post").ValuesList(&lists, "user", "id")
+	throwFail(t, err)
+	expected := make(map[string][]string)
+	for _, values := range lists {
+		user := fmt.Sprint(values[0])
+		expected[user] = append(expected[user], fmt.Sprint(values[1]))
+	}
+
+	var maps []Params
+	num, err := dORM.QueryTable("post").GroupBy("user").OrderBy("user").
+		GroupConcat("id", "ids", "|").Values(&maps, "user")
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, len(expected)))
+	for _, m := range maps {
+		ids := strings.Split(m["ids"].(string), "|")
+		sort.Strings(ids)
+		want := expected[fmt.Sprint(m["User__User"])]
+		sort.Strings(want)
+		throwFail(t, AssertIs(strings.Join(ids, "|"), strings.Join(want, "|")))
+	}
+
+	post := &Post{ID: 1}
+	throwFail(t, dORM.Read(post))
+	var list ParamsList
+	_, err = dORM.QueryTable("post").Filter("id", post.ID).
+		GroupConcat("title", "titles", "','").ValuesFlat(&list, "id")
+	throwFail(t, err)
+	throwFail(t, AssertIs(len(list), 2))
+	throwFail(t, AssertIs(list[1], post.Title))
+
+	// the alias is quoted as an identifier
+	Q := dDbBaser.TableQuote()
+	alias := "x" + Q + " FROM post; --"
+	maps = nil
+	_, err = dORM.QueryTable("post").Filter("id", post.ID).GroupConcat("title", alias, ",").Values(&maps, "id")
+	throwFail(t, err)
+	throwFail(t, AssertIs(len(maps), 1))
+	throwFail(t, AssertIs(maps[0][alias], post.Title))
+
+	sql, err := newdbBaseMysql().GenerateGroupConcatSQL("T0.`title`", `'\`)
+	throwFail(t, err)
+	throwFail(t, AssertIs(sql, `GROUP_CONCAT(T0.`+"`title`"+` SEPARATOR '''\\')`))
+	sql, err = newdbBasePostgres().GenerateGroupConcatSQL(`T0."title"`, ",")
+	throwFail(t, err)
+	throwFail(t, AssertIs(sql, `string_agg(CAST(T0."title" AS TEXT), ',')`))
+	_, err = newdbBaseOracle().GenerateGroupConcatSQL(`T0."title"`, ",")
+	throwFail(t, AssertIs(err, ErrNotImplement))
+}
+
+func TestTxSavepoint(t *testing.T) {
+	to, err := dORM.Begin()
+	throwFailNow(t, err)
+	defer to.RollbackUnlessCommit()
+
+	num, err := to.QueryTable("user").Filter("user_name", "slene").Update(Params{"status": 7})
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+
+	throwFail(t, to.Savepoint("sp1"))
+	_, err = to.QueryTable("user").Filter("user_name", "nobody").Update(Params{"status": 7})
+	throwFail(t, err)
+	cnt, err := to.QueryTable("user").Filter("status", 7).Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(cnt, 2))
+
+	// the changes before the savepoint are kept
+	throwFail(t, to.RollbackTo("sp1"))
+	cnt, err = to.QueryTable("user").Filter("status", 7).Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(cnt, 1))
+
+	throwFail(t, to.Savepoint("sp2"))
+	throwFail(t, to.ReleaseSavepoint("sp2"))
+	throwFail(t, AssertNot(to.RollbackTo("sp2"), nil))
+
+	throwFail(t, AssertNot(to.Savepoint("sp; DROP TABLE user"), nil))
+	throwFail(t, AssertNot(to.Savepoint("1sp"), nil))
+	throwFail(t, AssertNot(to.Savepoint(""), nil))
+
+	throwFail(t, to.Rollback())
+	cnt, err = dORM.QueryTable("user").Filter("status", 7).Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(cnt, 0))
+}
+
+func TestTxExecBatch(t *testing.T) {
+	to, err := dORM.Begin()
+	throwFailNow(t, err)
+	defer to.RollbackUnlessCommit()
+
+	Q := dDbBaser.TableQuote()
+	update := fmt.Sprintf("UPDATE %suser%s SET %sstatus%s = ? WHERE %suser_name%s = ?", Q, Q, Q, Q, Q, Q)
+	results, err := to.ExecBatch([]RawStmt{
+		{SQL: update, Args: []interface{}{8, "slene"}},
+		{SQL: update, Args: []interface{}{8, "nobody"}},
+	})
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(len(results), 2))
+	for _, res := range results {
+		num, err := res.RowsAffected()
+		throwFail(t, err)
+		throwFail(t, AssertIs(num, 1))
+	}
+
+	// stop at the first failed statement
+	results, err = to.ExecBatch([]RawStmt{
+		{SQL: update, Args: []interface{}{9, "slene"}},
+		{SQL: "UPDATE no_such_table SET status = 9"},
+		{SQL: update, Args: []interface{}{9, "nobody"}},
+	})
+	throwFail(t, AssertNot(err, nil))
+	throwFail(t, AssertIs(len(results), 1))
+	throwFail(t, to.Rollback())
+
+	cnt, err := dORM.QueryTable("user").Filter("status__in", 8, 9).Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(cnt, 0))
+
+	throwFail(t, AssertIs(isMultiStatementsDSN("root:@/orm_test?charset=utf8&multiStatements=1"), true))
+	throwFail(t, AssertIs(isMultiStatementsDSN("root:@/orm_test?multiStatements=True"), true))
+	throwFail(t, AssertIs(isMultiStatementsDSN("root:@/orm_test?multiStatements=false"), false))
+	throwFail(t, AssertIs(isMultiStatementsDSN("root:@/orm_test?noMultiStatements=true"), false))
+	throwFail(t, AssertIs(isMultiStatementsDSN("root:@/orm_test"), false))
+
+	// the joined statements of mysql without database
+	al := *getDbAlias("default")
+	al.Driver = DRMySQL
+	al.DataSource = "root:@/orm_test?multiStatements=1"
+	dry := new(dryRunQuerier)
+	dryTx := &txOrm{ormBase: ormBase{alias: &al, db: dry}}
+	results, err = dryTx.ExecBatch([]RawStmt{
+		{SQL: "UPDATE user SET status = 8;"},
+		{SQL: "UPDATE post SET title = 'x'"},
+	})
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(len(results), 1))
+	joined, ok := results[0].(*JoinedResult)
+	throwFailNow(t, AssertIs(ok, true))
+	throwFail(t, AssertIs(joined.Stmts, 2))
+	query, _ := dry.last()
+	throwFail(t, AssertIs(query, "UPDATE user SET status = 8;UPDATE post SET title = 'x'"))
+
+	if !IsMysql {
+		return
+	}
+	// the statements without args are joined
+	source := DBARGS.Source + "?multiStatements=true"
+	if strings.Contains(DBARGS.Source, "?") {
+		source = DBARGS.Source + "&multiStatements=true"
+	}
+	throwFailNow(t, RegisterDataBase("multi-statements", DBARGS.Driver, source))
+	to, err = NewOrmUsingDB("multi-statements").Begin()
+	throwFailNow(t, err)
+	defer to.RollbackUnlessCommit()
+	results, err = to.ExecBatch([]RawStmt{
+		{SQL: "UPDATE user SET status = 8 WHERE user_name = 'slene'"},
+		{SQL: "UPDATE user SET status = 8 WHERE user_name = 'nobody'"},
+	})
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(len(results), 1))
+	joined, ok = results[0].(*JoinedResult)
+	throwFailNow(t, AssertIs(ok, true))
+	throwFail(t, AssertIs(joined.Stmts, 2))
+	throwFail(t, to.Rollback())
+}
+
+func TestQuerySetScan(t *testing.T) {
+	type userPost struct {
+		ID       int    `orm:"column(id)"`
+		Title    string `db:"title"`
+		UserName string `db:"user__user_name"`
+		Status   int16  `db:"user__Status"`
+	}
+
+	var expected []ParamsList
+	_, err := dORM.QueryTable("post").OrderBy("id").ValuesList(&expected, "id", "title", "user__user_name", "user__Status")
+	throwFail(t, err)
+
+	var rows []userPost
+	num, err := dORM.QueryTable("post").OrderBy("id").Scan(&rows, "id", "title", "user__user_name", "user__Status")
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, len(expected)))
+	for i, row := range rows {
+		throwFail(t, AssertIs(row.ID, expected[i][0]))
+		throwFail(t, AssertIs(row.Title, expected[i][1]))
+		throwFail(t, AssertIs(row.UserName, expected[i][2]))
+		throwFail(t, AssertIs(row.Status, expected[i][3]))
+	}
+
+	// the columns are named by the column names without exprs
+	type userName struct {
+		Name string `db:"user_name"`
+	}
+	var names []*userName
+	num, err = dORM.QueryTable("user").Filter("id", 2).Scan(&names)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+	throwFail(t, AssertIs(names[0].Name, "slene"))
+
+	// the db tag works for RawSeter too
+	Q := dDbBaser.TableQuote()
+	names = nil
+	_, err = dORM.Raw(fmt.Sprintf("SELECT user_name FROM %suser%s WHERE id = ?", Q, Q), 2).QueryRows(&names)
+	throwFail(t, err)
+	throwFail(t, AssertIs(names[0].Name, "slene"))
+}
+
+func TestSetDefaultQueryTimeout(t *testing.T) {
+	SetDefaultQueryTimeout(time.Nanosecond)
+	defer SetDefaultQueryTimeout(0)
+
+	_, err := dORM.QueryTable("user").Count()
+	throwFail(t, AssertIs(errors.Is(err, context.DeadlineExceeded), true))
+	var user User
+	err = dORM.QueryTable("user").Filter("id", 2).One(&user)
+	throwFail(t, AssertIs(errors.Is(err, context.DeadlineExceeded), true))
+	_, err = dORM.QueryTable("user").Filter("id", 2).Update(Params{"status": user.Status})
+	throwFail(t, AssertIs(errors.Is(err, context.DeadlineExceeded), true))
+	var tags []Tag
+	_, err = dORM.Raw("SELECT id, name FROM tag").QueryRows(&tags)
+	throwFail(t, AssertIs(errors.Is(err, context.DeadlineExceeded), true))
+
+	// the deadline of context is used as it is
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	num, err := dORM.QueryTable("user").CountWithCtx(ctx)
+	throwFail(t, err)
+	throwFail(t, AssertNot(num, 0))
+
+	SetDefaultQueryTimeout(time.Minute)
+	num, err = dORM.QueryTable("user").Count()
+	throwFail(t, err)
+	throwFail(t, AssertNot(num, 0))
+	// the rows are read before the timeout is canceled
+	var users []User
+	num, err = dORM.QueryTable("user").All(&users)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, len(users)))
+	throwFail(t, AssertNot(num, 0))
+}
+
+func TestOnConnWait(t *testing.T) {
+	var (
+		mux   sync.Mutex
+		waits []time.Duration
+	)
+	OnConnWait(func(dur time.Duration) {
+		mux.Lock()
+		defer mux.Unlock()
+		waits = append(waits, dur)
+	})
+	defer OnConnWait(nil)
+
+	_, err := dORM.QueryTable("user").Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(len(waits), 1))
+
+	// the only connection of the pool is held by the transaction
+	al := getDbAlias("default")
+	defer al.SetMaxOpenConns(al.MaxOpenConns)
+	al.SetMaxOpenConns(1)
+	to, err := dORM.Begin()
+	throwFail(t, err)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = to.Rollback()
+	}()
+	_, err = dORM.QueryTable("user").Count()
+	throwFail(t, err)
+	mux.Lock()
+	defer mux.Unlock()
+	throwFail(t, AssertIs(len(waits), 2))
+	throwFail(t, AssertIs(waits[1] >= 40*time.Millisecond, true, waits[1]))
+}
+
+func TestFilterOr(t *testing.T) {
+	qs := dORM.QueryTable("user")
+	num, err := qs.Filter("user_name", "slene").FilterOr("user_name", "astaxie").Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 2))
+
+	// AND takes precedence over OR
+	num, err = qs.Filter("user_name", "slene").FilterOr("user_name", "astaxie").Filter("user_name", "nothing").Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+
+	num, err = qs.FilterOr("user_name", "nobody").Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+
+	cond := NewCondition().And("user_name", "astaxie").And("id__gt", 0)
+	num, err = qs.Filter("user_name", "slene").FilterOrCond(cond).Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 2))
+
+	cond = NewCondition().And("user_name", "astaxie").Or("user_name", "nobody")
+	num, err = qs.Filter("user_name", "slene").FilterOrCond(cond).Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 3))
+
+	num, err = qs.Filter("user_name", "slene").FilterOrCond(nil).FilterOrCond(NewCondition()).Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+}
+
+func TestDeleteWhere(t *testing.T) {
+	tags := []*Tag{{Name: "delwhere1"}, {Name: "delwhere2"}}
+	_, err := dORM.InsertMulti(2, tags)
+	throwFail(t, err)
+
+	// the empty condition is refused
+	num, err := dORM.DeleteWhere(&Tag{}, nil)
+	throwFail(t, AssertIs(err, ErrEmptyCondition))
+	throwFail(t, AssertIs(num, 0))
+	_, err = dORM.DeleteWhere(&Tag{}, NewCondition())
+	throwFail(t, AssertIs(err, ErrEmptyCondition))
+
+	num, err = dORM.DeleteWhere(&Tag{}, NewCondition().And("name__startswith", "delwhere"))
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 2))
+	num, err = dORM.QueryTable("tag").Filter("name__startswith", "delwhere").Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 0))
+
+	total, err := dORM.QueryTable("tag").Count()
+	throwFail(t, err)
+	to, err := dORM.Begin()
+	throwFail(t, err)
+	num, err = to.DeleteWhere(&Tag{}, NewCondition().AllowEmptyCondition())
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, total))
+	throwFail(t, to.Rollback())
+	num, err = dORM.QueryTable("tag").Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, total))
+}
+
+func TestOrderRandom(t *testing.T) {
+	throwFail(t, AssertIs(newdbBaseMysql().GenerateRandomOrder(), "RAND()"))
+	throwFail(t, AssertIs(newdbBaseTidb().GenerateRandomOrder(), "RAND()"))
+	throwFail(t, AssertIs(newdbBasePostgres().GenerateRandomOrder(), "RANDOM()"))
+	throwFail(t, AssertIs(newdbBaseSqlite().GenerateRandomOrder(), "RANDOM()"))
+	throwFail(t, AssertIs(newdbBaseOracle().GenerateRandomOrder(), "DBMS_RANDOM.VALUE"))
+
+	var users []*User
+	qs := dORM.QueryTable("user")
+	num, err := qs.OrderRandom().All(&users)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 3))
+
+	var user User
+	err = qs.Filter("user_name__in", "slene", "astaxie").OrderRandom().Limit(1).One(&user)
+	throwFail(t, err)
+	throwFail(t, AssertIs(user.UserName == "slene" || user.UserName == "astaxie", true))
+
+	// the random order comes after the other orders
+	num, err = qs.OrderBy("-is_staff").OrderRandom().All(&users)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 3))
+	throwFail(t, AssertIs(users[0].IsStaff, true))
+
+	var maps []Params
+	num, err = qs.OrderRandom().Values(&maps, "user_name")
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 3))
+}
+
+func TestRepo(t *testing.T) {
+	tags := Repo[Tag](dORM)
+	tag := &Tag{Name: "repo"}
+	_, err := tags.Create(tag)
+	throwFail(t, err)
+	throwFail(t, AssertNot(tag.ID, 0))
+
+	got, err := tags.Get(tag.ID)
+	throwFail(t, err)
+	throwFail(t, AssertIs(got.Name, "repo"))
+
+	tag.Name = "repo2"
+	num, err := tags.Update(tag, "Name")
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+
+	found, err := tags.Find(NewCondition().And("name", "repo2"))
+	throwFail(t, err)
+	throwFail(t, AssertIs(len(found), 1))
+	throwFail(t, AssertIs(found[0].ID, tag.ID))
+
+	num, err = tags.DeleteWithCtx(context.Background(), tag)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+	_, err = tags.GetWithCtx(context.Background(), tag.ID)
+	throwFail(t, AssertIs(err, ErrNoRows))
+
+	// the model is not registered
+	_, err = Repo[DbDefaultTask](dORM).Find(nil)
+	throwFail(t, AssertIs(errors.Is(err, ErrTableNotFound), true))
+}
+
+func TestRawJSONAggregation(t *testing.T) {
+	type postTitle struct {
+		ID    int    `json:"id"`
+		Title string `json:"title"`
+	}
+	type userPosts struct {
+		UserName string
+		Posts    []postTitle `orm:"column(posts);type(json)"`
+	}
+
+	var agg string
+	switch {
+	case IsPostgres:
+		agg = "json_agg(json_build_object('id', T1.id, 'title', T1.title) ORDER BY T1.id)"
+	case IsSqlite:
+		// json1 extension may be unavailable, build the json array by the strings
+		agg = `'[' || group_concat('{"id":' || T1.id || ',"title":"' || T1.title || '"}') || ']'`
+	case IsMysql:
+		agg = "JSON_ARRAYAGG(JSON_OBJECT('id', T1.id, 'title', T1.title))"
+	default:
+		return
+	}
+	Q := dDbBaser.TableQuote()
+	query := fmt.Sprintf("SELECT T0.user_name, %s AS posts FROM %suser%s T0 INNER JOIN %spost%s T1 ON T1.user_id = T0.id "+
+		"WHERE T0.user_name = ? GROUP BY T0.user_name", agg, Q, Q, Q, Q)
+
+	var expected []*Post
+	num, err := dORM.QueryTable("post").Filter("user__user_name", "slene").OrderBy("id").All(&expected)
+	throwFail(t, err)
+	throwFail(t, AssertNot(num, 0))
+
+	var rows []userPosts
+	num, err = dORM.Raw(query, "slene").QueryRows(&rows)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+	throwFail(t, AssertIs(rows[0].UserName, "slene"))
+	throwFail(t, AssertIs(len(rows[0].Posts), len(expected)))
+	sort.Slice(rows[0].Posts, func(i, j int) bool { return rows[0].Posts[i].ID < rows[0].Posts[j].ID })
+	for i, post := range expected {
+		throwFail(t, AssertIs(rows[0].Posts[i].ID, post.ID))
+		throwFail(t, AssertIs(rows[0].Posts[i].Title, post.Title))
+	}
+
+	var row userPosts
+	err = dORM.Raw(query, "slene").QueryRow(&row)
+	throwFail(t, err)
+	throwFail(t, AssertIs(len(row.Posts), len(expected)))
+
+	// the invalid json is reported
+	err = dORM.Raw("SELECT 'slene' AS user_name, 'nothing' AS posts").QueryRow(&row)
+	throwFail(t, AssertNot(err, nil))
+
+	// the string field gets the raw json
+	var rawRow struct {
+		UserName string
+		Posts    string `orm:"column(posts);type(json)"`
+	}
+	err = dORM.Raw(query, "slene").QueryRow(&rawRow)
+	throwFail(t, err)
+	throwFail(t, AssertIs(strings.HasPrefix(rawRow.Posts, "["), true))
+}
+
+func TestQueryError(t *testing.T) {
+	Q := dDbBaser.TableQuote()
+	query := fmt.Sprintf("UPDATE %suser%s SET %spassword%s = ? WHERE %snothing%s = ?", Q, Q, Q, Q, Q, Q)
+	_, err := dORM.Raw(query, "secret", 1).Exec()
+	var qe *QueryError
+	throwFail(t, AssertIs(errors.As(err, &qe), true))
+	throwFail(t, AssertIs(qe.SQL, query))
+	throwFail(t, AssertIs(len(qe.Args), 2))
+	// the fields of the args of raw sql are unknown
+	throwFail(t, AssertIs(qe.Args[0], "secret"))
+	throwFail(t, AssertIs(qe.Args[1], 1))
+	throwFail(t, AssertIs(errors.Unwrap(err), qe.Err))
+	throwFail(t, AssertIs(strings.Contains(err.Error(), query), true))
+
+	// the failed query in transaction is wrapped too
+	to, err := dORM.Begin()
+	throwFail(t, err)
+	defer to.Rollback()
+	_, err = to.Raw(fmt.Sprintf("SELECT * FROM %snothing%s", Q, Q)).Exec()
+	throwFail(t, AssertIs(errors.As(err, &qe), true))
+
+	// not found is not a failed query
+	err = dORM.Read(&User{ID: 1000})
+	throwFail(t, AssertIs(errors.As(err, &qe), false))
+	throwFail(t, AssertIs(errors.Is(err, ErrNoRows), true))
+}
+
+func TestCaseInsensitiveOperators(t *testing.T) {
+	mi, _ := modelCache.getByMd(&User{})
+	cond := NewCondition().And("user_name__icontains", "SL%").And("email__iexact", "Slene@gmail.com")
+
+	where, args, _ := newDbTables(mi, newdbBaseOracle()).getCondSQL(cond, false, time.UTC)
+	throwFail(t, AssertIs(where, "WHERE UPPER(T0.`user_name`) LIKE UPPER(?) ESCAPE '\\' AND UPPER(T0.`email`) LIKE UPPER(?) ESCAPE '\\' ", where))
+	throwFail(t, AssertIs(args[0], `%SL\%%`))
+	throwFail(t, AssertIs(args[1], "Slene@gmail.com"))
+
+	where, _, _ = newDbTables(mi, newdbBasePostgres()).getCondSQL(cond, false, time.UTC)
+	throwFail(t, AssertIs(where, `WHERE UPPER(T0."user_name"::text) LIKE UPPER(?) AND UPPER(T0."email"::text) = UPPER(?) `, where))
+
+	num, err := dORM.QueryTable("user").Filter("user_name__istartswith", "SLE").Filter("user_name__iendswith", "NE").Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+}
+
+func TestAllWithTotalCount(t *testing.T) {
+	qs := dORM.QueryTable("post")
+	expected, err := qs.Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(expected > 2, true))
+
+	var posts []*Post
+	total, err := qs.OrderBy("id").Limit(2).AllWithTotalCount(&posts)
+	throwFail(t, err)
+	throwFail(t, AssertIs(total, expected))
+	throwFail(t, AssertIs(len(posts), 2))
+
+	// the total is selected in the same query
+	dry := dORM.DryRun()
+	_, _ = dry.QueryTable("post").Limit(2).AllWithTotalCount(&posts)
+	query, _ := dry.LastSQL()
+	throwFail(t, AssertIs(strings.Contains(query, ", COUNT(*) OVER() FROM"), true, query))
+
+	total, err = qs.OrderBy("id").Limit(2, 2).AllWithTotalCount(&posts, "ID", "Title")
+	throwFail(t, err)
+	throwFail(t, AssertIs(total, expected))
+	throwFail(t, AssertNot(posts[0].Title, ""))
+
+	// the page is beyond the last row
+	var empty []*Post
+	total, err = qs.OrderBy("id").Limit(2, 1000).AllWithTotalCount(&empty)
+	throwFail(t, err)
+	throwFail(t, AssertIs(total, expected))
+	throwFail(t, AssertIs(len(empty), 0))
+
+	total, err = qs.Filter("title", "nothing").AllWithTotalCount(&empty)
+	throwFail(t, err)
+	throwFail(t, AssertIs(total, 0))
+
+	// fall back to All and Count
+	var users []*User
+	total, err = dORM.QueryTable("user").Distinct().Limit(1).AllWithTotalCount(&users, "UserName")
+	throwFail(t, err)
+	throwFail(t, AssertIs(total, 3))
+	throwFail(t, AssertIs(len(users), 1))
+
+	// the database without window function, e.g. mysql 5.7
+	throwFailNow(t, RegisterDataBase("no-window-func", DBARGS.Driver, DBARGS.Source))
+	rewriters := getSQLRewriters()
+	RegisterSQLRewriter(func(op, query string) string {
+		return strings.Replace(query, "COUNT(*) OVER()", "COUNT(*) NO_WINDOW()", 1)
+	})
+	defer sqlRewriters.Store(rewriters)
+	o := NewOrmUsingDB("no-window-func")
+	total, err = o.QueryTable("post").OrderBy("id").Limit(2).AllWithTotalCount(&posts)
+	throwFail(t, err)
+	throwFail(t, AssertIs(total, expected))
+	throwFail(t, AssertIs(len(posts), 2))
+	throwFail(t, AssertIs(isWindowFuncSupported(getDbAlias("no-window-func")), false))
+	throwFail(t, AssertIs(isWindowFuncSupported(getDbAlias("default")), true))
+
+	// the other errors are returned
+	_, err = o.QueryTable("post").Filter("id", 1).AllWithTotalCount(&posts, "nothing")
+	throwFail(t, AssertNot(err, nil))
+}
+
+func TestRegisterQueryScoper(t *testing.T) {
+	type userKey struct{}
+	RegisterQueryScoper(func(ctx context.Context, table string) *Condition {
+		throwFail(t, AssertIs(table, "post"))
+		if id, ok := ctx.Value(userKey{}).(int); ok {
+			return NewCondition().And("user", id)
+		}
+		return nil
+	})
+	defer RegisterQueryScoper(nil)
+	mi, _ := modelCache.getByMd(&Post{})
+	mi.queryScoped = true
+	defer func() {
+		mi.queryScoped = false
+	}()
+
+	qs := dORM.QueryTable("post")
+	total, err := qs.Count()
+	throwFail(t, err)
+	expected, err := qs.Filter("user", 2).Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(expected > 0 && expected < total, true))
+
+	ctx := context.WithValue(context.Background(), userKey{}, 2)
+	num, err := qs.CountWithCtx(ctx)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, expected))
+
+	var posts []*Post
+	num, err = qs.Filter("id__gt", 0).AllWithCtx(ctx, &posts)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, expected))
+	for _, post := range posts {
+		throwFail(t, AssertIs(post.User.ID, 2))
+	}
+
+	// the union queries are scoped by ctx of the caller
+	num, err = qs.Filter("id__lte", 2).Union(qs.Filter("id__gt", 2)).AllWithCtx(ctx, &posts)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, expected))
+
+	// the scope can't be bypassed by binding the args of prepared statement
+	_, err = qs.Filter("id__gt", 0).PrepareWithCtx(ctx)
+	throwFail(t, AssertNot(err, nil))
+
+	// update and delete are scoped too
+	to, err := dORM.Begin()
+	throwFail(t, err)
+	defer to.Rollback()
+	num, err = to.QueryTable("post").UpdateWithCtx(ctx, Params{"content": "scoped"})
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, expected))
+	num, err = to.QueryTable("post").Filter("content", "scoped").Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, expected))
+	num, err = to.QueryTable("post").Filter("id__gt", 0).DeleteWithCtx(ctx)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, expected))
+	num, err = to.QueryTable("post").Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, total-expected))
+
+	// the scope doesn't make the empty condition deletable
+	throwFailNow(t, AssertIs(func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("%v", r)
+			}
+		}()
+		_, err = to.QueryTable("post").DeleteWithCtx(ctx)
+		return
+	}() != nil, true))
+}
+
+func TestExcludeExists(t *testing.T) {
+	qs := dORM.QueryTable("user")
+	total, err := qs.Count()
+	throwFail(t, err)
+
+	// the users without posts
+	var users []*User
+	num, err := qs.ExcludeExists("posts", nil).All(&users)
+	throwFail(t, err)
+	withPosts, err := qs.Filter("posts__id__isnull", false).Distinct().Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, total-withPosts))
+
+	cond := NewCondition().And("title", "Introduction")
+	num, err = qs.ExcludeExists("posts", cond).OrderBy("id").All(&users)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, total-1))
+	for _, user := range users {
+		throwFail(t, AssertNot(user.UserName, "slene"))
+	}
+
+	// the condition of the subquery can follow the relations of the related model
+	cond = NewCondition().And("tags__tag__name", "golang")
+	num, err = qs.ExcludeExists("posts", cond).Count()
+	throwFail(t, err)
+	withTag, err := qs.Filter("posts__tags__tag__name", "golang").Distinct().Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, total-withTag))
+
+	// foreign key
+	num, err = dORM.QueryTable("post").ExcludeExists("user", NewCondition().And("user_name", "slene")).Count()
+	throwFail(t, err)
+	posts, err := dORM.QueryTable("post").Count()
+	throwFail(t, err)
+	bySlene, err := dORM.QueryTable("post").Filter("user__user_name", "slene").Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, posts-bySlene))
+
+	if IsSqlite {
+		dry := dORM.DryRun()
+		_, _ = dry.QueryTable("user").Filter("is_staff", true).ExcludeExists("posts", NewCondition().And("title", "Introduction")).Count()
+		query, _ := dry.LastSQL()
+		throwFail(t, AssertIs(query, "SELECT COUNT(*) FROM `user` T0 WHERE T0.`is_staff` = ? AND "+
+			"NOT EXISTS (SELECT 1 FROM `post` TS0 WHERE TS0.`user_id` = T0.`id` AND ( TS0.`title` = ? ) ) "))
+	}
+
+	assert.Panics(t, func() {
+		_, _ = qs.ExcludeExists("user_name", nil).Count()
+	})
+}
+
+func TestResetAutoIncrement(t *testing.T) {
+	if !IsMysql && !IsSqlite && !IsPostgres {
+		return
+	}
+	qs := dORM.QueryTable("null_value")
+	defer qs.Filter("id__gt", 0).Delete()
+
+	for i := 0; i < 2; i++ {
+		_, err := dORM.Insert(&NullValue{Value: "reset"})
+		throwFailNow(t, err)
+	}
+	_, err := qs.Filter("id__gt", 0).Delete()
+	throwFailNow(t, err)
+
+	throwFailNow(t, ResetAutoIncrement("default", &NullValue{}))
+	value := &NullValue{Value: "reset"}
+	_, err = dORM.Insert(value)
+	throwFail(t, err)
+	throwFail(t, AssertIs(value.ID, 1))
+
+	throwFail(t, AssertNot(ResetAutoIncrement("unknown", &NullValue{}), nil))
+	throwFail(t, AssertIs(errors.Is(ResetAutoIncrement("default", &DbDefaultTask{}), ErrTableNotFound), true))
+	throwFail(t, AssertNot(ResetAutoIncrement("default", &StrPk{}), nil))
+
+	_, err = newdbBaseOracle().GenerateResetAutoIncrementSQL("null_value", "id")
+	throwFail(t, AssertIs(err, ErrNotImplement))
+	query, _ := newdbBaseMysql().GenerateResetAutoIncrementSQL("null_value", "id")
+	throwFail(t, AssertIs(query, "ALTER TABLE `null_value` AUTO_INCREMENT = 1"))
+	query, _ = newdbBasePostgres().GenerateResetAutoIncrementSQL("null_value", "id")
+	throwFail(t, AssertIs(query, "SELECT setval(pg_get_serial_sequence('null_value', 'id'), 1, false)"))
+}
+
+func TestQuerySeterPrepare(t *testing.T) {
+	qs := dORM.QueryTable("user")
+	p, err := qs.Filter("user_name", "").OrderBy("id").Prepare()
+	throwFailNow(t, err)
+	defer p.Close()
+
+	var user User
+	for _, name := range []string{"slene", "astaxie", "nobody"} {
+		throwFail(t, p.One(&user, name))
+		throwFail(t, AssertIs(user.UserName, name))
+	}
+	throwFail(t, AssertIs(p.One(&user, "unknown"), ErrNoRows))
+
+	var users []*User
+	num, err := p.All(&users, "slene")
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+	throwFail(t, AssertIs(users[0].UserName, "slene"))
+
+	_, err = p.All(&users)
+	throwFail(t, AssertNot(err, nil))
+	throwFail(t, p.Close())
+	throwFail(t, AssertIs(p.One(&user, "slene"), ErrStmtClosed))
+
+	// the args of IN are flattened
+	p, err = qs.Filter("user_name__in", "", "").Filter("is_staff", false).OrderBy("id").Prepare()
+	throwFailNow(t, err)
+	defer p.Close()
+	num, err = p.AllWithCtx(context.Background(), &users, []string{"slene", "astaxie"}, false)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+	throwFail(t, AssertIs(users[0].UserName, "slene"))
+	throwFail(t, AssertIs(p.OneWithCtx(context.Background(), &user, "slene", "nobody", false), ErrMultiRows))
+}
+
+func TestSelectColumns(t *testing.T) {
+	var posts, fulls []*Post
+	qs := dORM.QueryTable("post").RelatedSel("user__profile").Filter("user__profile__id__gt", 0).OrderBy("id")
+	num, err := qs.All(&fulls)
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(num > 0, true))
+	num, err = qs.SelectColumns("id", "title", "user__user_name", "user__profile__age").All(&posts)
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(num, len(fulls)))
+	for i, post := range posts {
+		full := fulls[i]
+		throwFail(t, AssertIs(post.Title, full.Title))
+		throwFail(t, AssertIs(post.Content, ""))
+		throwFail(t, AssertIs(post.User.ID, full.User.ID))
+		throwFail(t, AssertIs(post.User.UserName, full.User.UserName))
+		throwFail(t, AssertIs(post.User.Email, ""))
+		throwFail(t, AssertIs(post.User.Profile.ID, full.User.Profile.ID))
+		throwFail(t, AssertIs(post.User.Profile.Age, full.User.Profile.Age))
+		throwFail(t, AssertIs(post.User.Profile.Money, 0.0))
+	}
+
+	// the related models without columns are queried fully
+	var post Post
+	err = dORM.QueryTable("post").RelatedSel("user").SelectColumns("title").Filter("id", fulls[0].ID).One(&post)
+	throwFail(t, err)
+	throwFail(t, AssertIs(post.Title, fulls[0].Title))
+	throwFail(t, AssertIs(post.Content, ""))
+	throwFail(t, AssertIs(post.User.Email, fulls[0].User.Email))
+
+	// the cols of All are used for the model
+	err = qs.Filter("id", fulls[0].ID).SelectColumns("id", "user__user_name").One(&post, "title", "content")
+	throwFail(t, err)
+	throwFail(t, AssertIs(post.Content, fulls[0].Content))
+	throwFail(t, AssertIs(post.User.UserName, fulls[0].User.UserName))
+	throwFail(t, AssertIs(post.User.Email, ""))
+
+	_, err = dORM.QueryTable("post").SelectColumns("user__user_name").All(&posts)
+	throwFail(t, AssertNot(err, nil))
+	_, err = dORM.QueryTable("post").RelatedSel("user").SelectColumns("user__nothing").All(&posts)
+	throwFail(t, AssertNot(err, nil))
+}
+
+func TestFirst(t *testing.T) {
+	qs := dORM.QueryTable("user")
+
+	var user User
+	err := qs.OrderBy("-id").First(&user)
+	throwFail(t, err)
+	var last User
+	err = qs.OrderBy("-id").Limit(1).One(&last)
+	throwFail(t, err)
+	throwFail(t, AssertIs(user.ID, last.ID))
+
+	user = User{}
+	err = qs.Filter("user_name__in", "slene", "astaxie").OrderBy("user_name").First(&user, "id", "user_name")
+	throwFail(t, err)
+	throwFail(t, AssertIs(user.UserName, "astaxie"))
+	throwFail(t, AssertIs(user.Email, ""))
+
+	err = qs.Filter("user_name__in", "slene", "astaxie").OrderBy("user_name").Offset(1).FirstWithCtx(context.Background(), &user)
+	throwFail(t, err)
+	throwFail(t, AssertIs(user.UserName, "slene"))
+
+	err = qs.Filter("user_name", "nothing").First(&user)
+	throwFail(t, AssertIs(err, ErrNoRows))
+}
+
+func TestFilterIsNull(t *testing.T) {
+	qs := dORM.QueryTable("user")
+	all, err := qs.Count()
+	throwFail(t, err)
+	expected, err := qs.Filter("profile__isnull", true).Count()
+	throwFail(t, err)
+
+	num, err := qs.FilterIsNull("profile").Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, expected))
+	num, err = qs.FilterIsNotNull("profile").Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, all-expected))
+
+	dry := dORM.DryRun()
+	_, _ = dry.QueryTable("user").FilterIsNull("profile").FilterIsNotNull("email").Count()
+	query, args := dry.LastSQL()
+	throwFail(t, AssertIs(strings.Contains(query, "IS NULL"), true, query))
+	throwFail(t, AssertIs(strings.Contains(query, "IS NOT NULL"), true, query))
+	throwFail(t, AssertIs(len(args), 0))
+}
+
+func TestNotFoundError(t *testing.T) {
+	EnableNotFoundError(true)
+	defer EnableNotFoundError(false)
 
-			id, err = result.LastInsertId()
-			assert.Nil(t, err)
-			assert.Equal(t, id, tid+2)
+	err := dORM.Read(&User{ID: 100})
+	throwFail(t, AssertIs(errors.Is(err, ErrNoRows), true))
+	var notFound *NotFoundError
+	throwFailNow(t, AssertIs(errors.As(err, &notFound), true))
+	throwFail(t, AssertIs(notFound.Table, "user"))
+	throwFail(t, AssertIs(notFound.PK, 100))
+	throwFail(t, AssertIs(err.Error(), "no row found in table `user` with pk `100`"))
 
-			err = pre.Close()
-			assert.Nil(t, err)
+	err = dORM.Read(&User{UserName: "nobody_exists"}, "UserName")
+	throwFail(t, AssertIs(errors.Is(err, ErrNoRows), true))
+	throwFail(t, AssertIs(err.Error(), "no row found in table `user`"))
 
-			res, err := dORM.Raw("DELETE FROM tag WHERE name IN (?, ?, ?)", []string{"name1", "name2", "name3"}).Exec()
-			assert.Nil(t, err)
+	EnableNotFoundError(false)
+	throwFail(t, AssertIs(dORM.Read(&User{ID: 100}), ErrNoRows))
+}
 
-			num, err := res.RowsAffected()
-			assert.Nil(t, err)
-			assert.Equal(t, num, int64(3))
-		}
-	} else if IsPostgres {
-		pre, err = dORM.Raw(`INSERT INTO "tag" ("name") VALUES (?) RETURNING "id"`).Prepare()
-		assert.Nil(t, err)
-		if pre != nil {
-			_, err = pre.Exec("name1")
-			assert.Nil(t, err)
+func TestForUpdateNoWait(t *testing.T) {
+	var user User
+	err := dORM.QueryTable("user").Filter("user_name", "slene").ForUpdateNoWait().One(&user)
+	if IsSqlite {
+		throwFail(t, AssertIs(err, ErrNotImplement))
+	} else {
+		throwFail(t, err)
+		throwFail(t, AssertIs(user.UserName, "slene"))
+	}
 
-			_, err = pre.Exec("name2")
-			assert.Nil(t, err)
+	err = convertLockError(errors.New("pq: could not obtain lock on row in relation \"user\""))
+	throwFail(t, AssertIs(errors.Is(err, ErrLockNotAvailable), true))
+	err = convertLockError(errors.New("Error 3572: Statement aborted because lock(s) could not be acquired immediately and NOWAIT is set."))
+	throwFail(t, AssertIs(errors.Is(err, ErrLockNotAvailable), true))
+	err = convertLockError(ErrNoRows)
+	throwFail(t, AssertIs(err, ErrNoRows))
+}
 
-			_, err = pre.Exec("name3")
-			assert.Nil(t, err)
+func TestArrayField(t *testing.T) {
+	m := &ArrayModel{
+		Nums:   []int{1, 2, 3},
+		Tags:   []string{"go", `say "hi"`, "a,b", `back\slash`},
+		Scores: []float64{1.5, -2},
+	}
+	id, err := dORM.Insert(m)
+	throwFailNow(t, err)
 
-			err = pre.Close()
-			assert.Nil(t, err)
+	empty := &ArrayModel{Nums: []int{}}
+	_, err = dORM.Insert(empty)
+	throwFailNow(t, err)
 
-			res, err := dORM.Raw(`DELETE FROM "tag" WHERE "name" IN (?, ?, ?)`, []string{"name1", "name2", "name3"}).Exec()
-			assert.Nil(t, err)
+	r := &ArrayModel{ID: int(id)}
+	throwFailNow(t, dORM.Read(r))
+	throwFail(t, AssertIs(len(r.Nums), 3))
+	throwFail(t, AssertIs(r.Nums[2], 3))
+	throwFail(t, AssertIs(len(r.Tags), 4))
+	throwFail(t, AssertIs(r.Tags[1], `say "hi"`))
+	throwFail(t, AssertIs(r.Tags[2], "a,b"))
+	throwFail(t, AssertIs(r.Tags[3], `back\slash`))
+	throwFail(t, AssertIs(r.Scores[0], 1.5))
 
-			if err == nil {
-				num, err := res.RowsAffected()
-				assert.Nil(t, err)
-				assert.Equal(t, num, int64(3))
-			}
-		}
-	}
-}
+	r = &ArrayModel{ID: empty.ID}
+	throwFailNow(t, dORM.Read(r))
+	throwFail(t, AssertIs(r.Nums != nil && len(r.Nums) == 0, true))
+	throwFail(t, AssertIs(r.Tags == nil, true))
 
-func TestUpdate(t *testing.T) {
-	qs := dORM.QueryTable("user")
-	num, err := qs.Filter("user_name", "slene").Filter("is_staff", false).Update(Params{
-		"is_staff":  true,
-		"is_active": true,
-	})
+	qs := dORM.QueryTable("array_model")
+	num, err := qs.Filter("nums", []int{1, 2, 3}).Count()
 	throwFail(t, err)
 	throwFail(t, AssertIs(num, 1))
 
-	// with join
-	num, err = qs.Filter("user_name", "slene").Filter("profile__age", 28).Filter("is_staff", true).Update(Params{
-		"is_staff": false,
-	})
+	num, err = qs.Filter("id", id).Update(Params{"nums": []int{4, 5}})
 	throwFail(t, err)
 	throwFail(t, AssertIs(num, 1))
 
-	num, err = qs.Filter("user_name", "slene").Update(Params{
-		"Nums": ColValue(ColAdd, 100),
-	})
-	throwFail(t, err)
-	throwFail(t, AssertIs(num, 1))
+	r = &ArrayModel{ID: int(id)}
+	throwFailNow(t, dORM.Read(r))
+	throwFail(t, AssertIs(len(r.Nums), 2))
+	throwFail(t, AssertIs(r.Nums[1], 5))
 
-	num, err = qs.Filter("user_name", "slene").Update(Params{
-		"Nums": ColValue(ColMinus, 50),
-	})
-	throwFail(t, err)
-	throwFail(t, AssertIs(num, 1))
+	if IsPostgres {
+		num, err = qs.Filter("tags__contains", []string{"go"}).Count()
+		throwFail(t, err)
+		throwFail(t, AssertIs(num, 1))
+	}
+}
 
-	num, err = qs.Filter("user_name", "slene").Update(Params{
-		"Nums": ColValue(ColMultiply, 3),
-	})
-	throwFail(t, err)
-	throwFail(t, AssertIs(num, 1))
+func TestDryRun(t *testing.T) {
+	dry := dORM.DryRun()
 
-	num, err = qs.Filter("user_name", "slene").Update(Params{
-		"Nums": ColValue(ColExcept, 5),
-	})
-	throwFail(t, err)
-	throwFail(t, AssertIs(num, 1))
+	query, args := dry.LastSQL()
+	throwFail(t, AssertIs(query, ""))
+	throwFail(t, AssertIs(len(args), 0))
 
-	user := User{UserName: "slene"}
-	err = dORM.Read(&user, "UserName")
+	user := &User{ID: 2, UserName: "dry_run"}
+	num, err := dry.Update(user, "UserName")
 	throwFail(t, err)
-	throwFail(t, AssertIs(user.Nums, 30))
+	throwFail(t, AssertIs(num, 0))
+
+	query, args = dry.LastSQL()
+	throwFail(t, AssertIs(strings.HasPrefix(query, "UPDATE"), true))
+	throwFail(t, AssertIs(args[0], "dry_run"))
+	throwFail(t, AssertIs(args[len(args)-1], 2))
+
+	u := &User{ID: 2}
+	throwFail(t, dORM.Read(u))
+	throwFail(t, AssertNot(u.UserName, "dry_run"))
+
+	var users []*User
+	_, err = dry.QueryTable("user").Filter("user_name", "slene").All(&users)
+	throwFail(t, AssertIs(err, ErrDryRun))
+	query, args = dry.LastSQL()
+	throwFail(t, AssertIs(strings.HasPrefix(query, "SELECT"), true))
+	throwFail(t, AssertIs(args[0], "slene"))
+
+	err = dry.Read(&User{ID: 2})
+	throwFail(t, AssertIs(err, context.Canceled))
+
+	_, err = dry.Begin()
+	throwFail(t, AssertIs(err, ErrDryRun))
+
+	query, _ = dORM.LastSQL()
+	throwFail(t, AssertIs(query, ""))
 }
 
-func TestDelete(t *testing.T) {
-	qs := dORM.QueryTable("user_profile")
-	num, err := qs.Filter("user__user_name", "slene").Delete()
-	throwFail(t, err)
-	throwFail(t, AssertIs(num, 1))
+func TestSQLRecorder(t *testing.T) {
+	recorder := NewSQLRecorder()
+	o := dORM.WithSQLRecorder(recorder)
 
-	qs = dORM.QueryTable("user")
-	num, err = qs.Filter("user_name", "slene").Filter("profile__isnull", true).Count()
+	u := &User{ID: 2}
+	throwFail(t, o.Read(u))
+	_, err := o.QueryTable("user").Filter("user_name", "slene").Count()
 	throwFail(t, err)
-	throwFail(t, AssertIs(num, 1))
 
-	qs = dORM.QueryTable("comment")
-	num, err = qs.Count()
+	records := recorder.Records()
+	throwFail(t, AssertIs(len(records), 2))
+	throwFail(t, AssertIs(strings.HasPrefix(records[0].SQL, "SELECT"), true))
+	throwFail(t, AssertIs(records[0].Args[0], 2))
+	throwFail(t, AssertIs(strings.Contains(records[1].SQL, "COUNT(*)"), true))
+	throwFail(t, AssertIs(records[1].Args[0], "slene"))
+
+	recorder.Reset()
+	throwFail(t, AssertIs(len(recorder.Records()), 0))
+
+	to, err := o.Begin()
+	throwFailNow(t, err)
+	_, err = to.QueryTable("user").Filter("id", 2).Update(Params{"nums": 1})
 	throwFail(t, err)
-	throwFail(t, AssertIs(num, 6))
+	throwFail(t, to.Rollback())
 
-	qs = dORM.QueryTable("post")
-	num, err = qs.Filter("Id", 3).Delete()
+	records = recorder.Records()
+	throwFail(t, AssertIs(len(records), 3))
+	throwFail(t, AssertIs(records[0].SQL, "START TRANSACTION"))
+	throwFail(t, AssertIs(strings.HasPrefix(records[1].SQL, "UPDATE"), true))
+	throwFail(t, AssertIs(records[2].SQL, "ROLLBACK"))
+
+	// the Ormer without recorder is not affected
+	throwFail(t, dORM.Read(u))
+	throwFail(t, AssertIs(len(recorder.Records()), 3))
+}
+
+func TestDeleteOnDelete(t *testing.T) {
+	post := &Post{User: &User{ID: 1}, Title: "on delete"}
+	_, err := dORM.Insert(post)
+	throwFailNow(t, err)
+	_, err = dORM.Insert(&Comment{Post: post, Content: "cascade"})
+	throwFailNow(t, err)
+
+	// cascade
+	num, err := dORM.Delete(post)
 	throwFail(t, err)
 	throwFail(t, AssertIs(num, 1))
-
-	qs = dORM.QueryTable("comment")
-	num, err = qs.Count()
+	num, err = dORM.QueryTable("comment").Filter("post", post.ID).Count()
 	throwFail(t, err)
-	throwFail(t, AssertIs(num, 4))
+	throwFail(t, AssertIs(num, 0))
 
-	qs = dORM.QueryTable("comment")
-	num, err = qs.Filter("Post__User", 3).Delete()
-	throwFail(t, err)
-	throwFail(t, AssertIs(num, 3))
+	profile := &Profile{Age: 30}
+	_, err = dORM.Insert(profile)
+	throwFailNow(t, err)
+	user := &User{UserName: "ondelete", Email: "ondelete@beego.vip", Profile: profile}
+	_, err = dORM.Insert(user)
+	throwFailNow(t, err)
 
-	qs = dORM.QueryTable("comment")
-	num, err = qs.Count()
+	// set_null
+	num, err = dORM.Delete(profile)
 	throwFail(t, err)
 	throwFail(t, AssertIs(num, 1))
+	u := &User{ID: user.ID}
+	throwFail(t, dORM.Read(u))
+	throwFail(t, AssertIs(u.Profile == nil, true))
+
+	_, err = dORM.Delete(u)
+	throwFail(t, err)
 }
 
 func TestTransaction(t *testing.T) {
@@ -2272,6 +4679,21 @@ func TestTransaction(t *testing.T) {
 	assert.Equal(t, int64(1), num)
 }
 
+func TestDoTxContextCanceled(t *testing.T) {
+	o := NewOrm()
+	ctx, cancel := context.WithCancel(context.Background())
+	err := o.DoTxWithCtx(ctx, func(ctx context.Context, txOrm TxOrmer) error {
+		_, txErr := txOrm.Insert(&Tag{Name: "canceled"})
+		cancel()
+		return txErr
+	})
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	num, err := o.QueryTable("tag").Filter("name", "canceled").Count()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), num)
+}
+
 func TestTxOrmRollbackUnlessCommit(t *testing.T) {
 	o := NewOrm()
 	var tag Tag
@@ -2355,6 +4777,33 @@ func TestTransactionIsolationLevel(t *testing.T) {
 	throwFail(t, AssertIs(num, 1))
 }
 
+func TestBeginIsolationLevel(t *testing.T) {
+	o := NewOrm()
+
+	to, err := o.BeginSerializable(context.Background())
+	throwFail(t, err)
+	throwFail(t, to.Rollback())
+
+	to, err = o.BeginReadCommitted(context.Background())
+	if IsSqlite {
+		throwFail(t, AssertIs(to, nil))
+		throwFail(t, AssertIs(errors.Is(err, ErrIsolation), true))
+		throwFail(t, AssertIs(err.Error(), "<Ormer> isolation level is not supported by the driver: `Read Committed`"))
+
+		err = o.DoTxWithOpts(&sql.TxOptions{Isolation: sql.LevelRepeatableRead}, func(ctx context.Context, txOrm TxOrmer) error {
+			return nil
+		})
+		throwFail(t, AssertIs(errors.Is(err, ErrIsolation), true))
+		return
+	}
+	throwFail(t, err)
+	throwFail(t, to.Rollback())
+
+	to, err = o.BeginRepeatableRead(context.Background())
+	throwFail(t, err)
+	throwFail(t, to.Rollback())
+}
+
 func TestBeginTxWithContextCanceled(t *testing.T) {
 	o := NewOrm()
 	ctx, cancel := context.WithCancel(context.Background())
@@ -2494,6 +4943,181 @@ func TestIntegerPk(t *testing.T) {
 	throwFail(t, AssertIs(num, 1))
 }
 
+func TestInsertMultiPlaceholderLimit(t *testing.T) {
+	typ := dORM.Driver().Type()
+	SetMaxPlaceholders(typ, 4)
+	defer SetMaxPlaceholders(typ, defaultMaxPlaceholders[typ])
+
+	its := make([]IntegerPk, 0, 5)
+	for i := 0; i < 5; i++ {
+		its = append(its, IntegerPk{ID: int64(100 + i), Value: fmt.Sprint(i)})
+	}
+
+	num, err := dORM.InsertMulti(100, its)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, len(its)))
+
+	cnt, err := dORM.QueryTable("integer_pk").Filter("id__gte", 100).Filter("id__lt", 105).Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(cnt, len(its)))
+}
+
+func TestSequencePk(t *testing.T) {
+	seq := &SequencePk{Value: "first"}
+	id, err := dORM.Insert(seq)
+	throwFail(t, err)
+	throwFail(t, AssertIs(id > 0, true))
+	throwFail(t, AssertIs(seq.ID, id))
+
+	seq = &SequencePk{ID: 100, Value: "explicit"}
+	_, err = dORM.Insert(seq)
+	throwFail(t, err)
+	throwFail(t, AssertIs(seq.ID, 100))
+
+	out := SequencePk{ID: 100}
+	err = dORM.Read(&out)
+	throwFail(t, err)
+	throwFail(t, AssertIs(out.Value, "explicit"))
+
+	seq = &SequencePk{Value: "next"}
+	_, err = dORM.Insert(seq)
+	throwFail(t, err)
+	throwFail(t, AssertIs(seq.ID > 100, true))
+}
+
+func TestEnumField(t *testing.T) {
+	m := &Member{Status: "active"}
+	_, err := dORM.Insert(m)
+	throwFail(t, err)
+
+	m.Status = "deleted"
+	_, err = dORM.Update(m)
+	throwFail(t, AssertIs(errors.Is(err, ErrEnumValue), true))
+
+	_, err = dORM.Insert(&Member{Status: "unknown"})
+	throwFail(t, AssertIs(errors.Is(err, ErrEnumValue), true))
+
+	num, err := dORM.QueryTable("member").Filter("id", m.ID).Update(Params{"status": "deleted"})
+	throwFail(t, AssertIs(errors.Is(err, ErrEnumValue), true))
+	throwFail(t, AssertIs(num, 0))
+
+	num, err = dORM.QueryTable("member").Filter("id", m.ID).Update(Params{"status": "banned"})
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+
+	mi, _ := modelCache.get("member")
+	al := &alias{Driver: DRMySQL, DbBaser: newdbBaseMysql()}
+	throwFail(t, AssertIs(getColumnTyp(al, mi.fields.GetByName("Status")), "enum('active','inactive','banned')"))
+}
+
+func TestFieldTransformer(t *testing.T) {
+	s := &Secret{Name: "slene", Email: "slene@gmail.com"}
+	_, err := dORM.Insert(s)
+	throwFail(t, err)
+	_, err = dORM.Insert(&Secret{Name: "nobody"})
+	throwFail(t, err)
+
+	// stored transformed
+	var raw string
+	err = dORM.Raw("SELECT email FROM secret WHERE id = ?", s.ID).QueryRow(&raw)
+	throwFail(t, err)
+	enc, _ := xorBytes([]byte("slene@gmail.com"))
+	throwFail(t, AssertIs(raw, base64.StdEncoding.EncodeToString(enc)))
+
+	out := Secret{ID: s.ID}
+	throwFail(t, dORM.Read(&out))
+	throwFail(t, AssertIs(out.Email, "slene@gmail.com"))
+
+	out = Secret{}
+	err = dORM.QueryTable("secret").Filter("email", "slene@gmail.com").One(&out)
+	throwFail(t, err)
+	throwFail(t, AssertIs(out.Name, "slene"))
+
+	num, err := dORM.QueryTable("secret").Filter("email__in", "slene@gmail.com", "other@gmail.com").Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+
+	num, err = dORM.QueryTable("secret").Filter("email", "").Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+
+	num, err = dORM.QueryTable("secret").Filter("id", s.ID).Update(Params{"email": "astaxie@gmail.com"})
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+
+	var emails ParamsList
+	_, err = dORM.QueryTable("secret").Filter("id", s.ID).ValuesFlat(&emails, "email")
+	throwFail(t, err)
+	throwFail(t, AssertIs(emails[0], "astaxie@gmail.com"))
+}
+
+func TestFieldCodec(t *testing.T) {
+	m := &CodecModel{
+		Payload: &CodecPayload{Name: "slene", Tags: []string{"golang", "orm"}},
+		Meta:    map[string]int{"views": 10},
+	}
+	_, err := dORM.Insert(m)
+	throwFailNow(t, err)
+
+	var raw string
+	err = dORM.Raw("SELECT meta FROM codec_model WHERE id = ?", m.ID).QueryRow(&raw)
+	throwFail(t, err)
+	throwFail(t, AssertIs(raw, `{"views":10}`))
+
+	out := CodecModel{ID: m.ID}
+	throwFailNow(t, dORM.Read(&out))
+	throwFailNow(t, AssertNot(out.Payload, nil))
+	throwFail(t, AssertIs(out.Payload.Name, "slene"))
+	throwFail(t, AssertIs(strings.Join(out.Payload.Tags, ","), "golang,orm"))
+	throwFail(t, AssertIs(out.Meta["views"], 10))
+
+	// nil is saved as NULL
+	n := &CodecModel{}
+	_, err = dORM.Insert(n)
+	throwFail(t, err)
+	num, err := dORM.QueryTable("codec_model").Filter("payload__isnull", true).Filter("meta__isnull", true).Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+	out = CodecModel{ID: n.ID}
+	throwFail(t, dORM.Read(&out))
+	throwFail(t, AssertIs(out.Payload == nil, true))
+	throwFail(t, AssertIs(out.Meta == nil, true))
+
+	num, err = dORM.QueryTable("codec_model").Filter("id", n.ID).Update(Params{"meta": map[string]int{"views": 1}})
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+	out = CodecModel{}
+	throwFail(t, dORM.QueryTable("codec_model").Filter("id", n.ID).One(&out))
+	throwFail(t, AssertIs(out.Meta["views"], 1))
+}
+
+func TestInsertWithPK(t *testing.T) {
+	var last Tag
+	throwFailNow(t, dORM.QueryTable("tag").OrderBy("-id").First(&last))
+
+	tag := &Tag{ID: last.ID + 100, Name: "imported"}
+	num, err := dORM.InsertWithPK(tag)
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(num, 1))
+	throwFail(t, AssertIs(tag.ID, last.ID+100))
+
+	out := Tag{ID: last.ID + 100}
+	throwFail(t, dORM.Read(&out))
+	throwFail(t, AssertIs(out.Name, "imported"))
+
+	_, err = dORM.InsertWithPK(&Tag{Name: "no pk"})
+	throwFail(t, AssertIs(err, ErrMissPK))
+
+	// the failed insert of a duplicate pk is a QueryError
+	_, err = dORM.InsertWithPK(&Tag{ID: tag.ID, Name: "duplicate"})
+	var qe *QueryError
+	throwFail(t, AssertIs(errors.As(err, &qe), true))
+
+	num, err = dORM.Delete(tag)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+}
+
 func TestInsertAuto(t *testing.T) {
 	u := &User{
 		UserName: "autoPre",
@@ -2555,6 +5179,21 @@ func TestUintPk(t *testing.T) {
 	dORM.Delete(u)
 }
 
+func TestScanReturningID(t *testing.T) {
+	al, _ := dataBaseCache.get("default")
+	query := "SELECT '18446744073709551615'"
+
+	mi, _ := modelCache.getByMd(&UintPk{})
+	id, err := scanReturningID(al.DB.QueryRow(query), mi)
+	throwFail(t, err)
+	throwFail(t, AssertIs(uint64(id), uint64(math.MaxUint64)))
+
+	// overflow for signed pk
+	mi, _ = modelCache.getByMd(&User{})
+	_, err = scanReturningID(al.DB.QueryRow(query), mi)
+	throwFail(t, AssertNot(err, nil))
+}
+
 func TestPtrPk(t *testing.T) {
 	parent := &IntegerPk{ID: 10, Value: "10"}
 
@@ -2628,9 +5267,9 @@ func TestIgnoreCaseTag(t *testing.T) {
 		Name02 string `orm:"COLUMN(Name)"`
 		Name03 string `orm:"Column(name)"`
 	}
-	modelCache.clean()
-	RegisterModel(&testTagModel{})
-	info, ok := modelCache.get("test_tag_model")
+	mc := NewModelCacheHandler()
+	throwFailNow(t, mc.register("", true, nil, &testTagModel{}))
+	info, ok := mc.get("test_tag_model")
 	throwFail(t, AssertIs(ok, true))
 	throwFail(t, AssertNot(info, nil))
 	if t == nil {
@@ -2643,7 +5282,6 @@ func TestIgnoreCaseTag(t *testing.T) {
 }
 
 func TestInsertOrUpdate(t *testing.T) {
-	RegisterModel(new(User))
 	userName := "unique_username133"
 	column := "user_name"
 	user := User{UserName: userName, Status: 1, Password: "o"}
@@ -2743,7 +5381,6 @@ func TestInsertOrUpdate(t *testing.T) {
 }
 
 func TestStrPkInsert(t *testing.T) {
-	RegisterModel(new(StrPk))
 	pk := `1`
 	value := `StrPkValues(*56`
 	strPk := &StrPk{
@@ -2940,3 +5577,55 @@ func captureDebugLogOutput(f func()) string {
 	f()
 	return buf.String()
 }
+
+type testQueryLogger struct {
+	queries []string
+	args    [][]interface{}
+}
+
+func (l *testQueryLogger) LogQuery(ctx context.Context, query string, args []interface{}, dur time.Duration, err error) {
+	l.queries = append(l.queries, query)
+	l.args = append(l.args, args)
+}
+
+func TestSetLogger(t *testing.T) {
+	logger := &testQueryLogger{}
+	SetLogger(logger)
+	defer SetLogger(nil)
+	Debug = true
+	defer func() {
+		Debug = false
+	}()
+
+	o := NewOrm()
+	output := captureDebugLogOutput(func() {
+		u := &User{ID: 2}
+		throwFail(t, o.Read(u))
+	})
+
+	assert.Equal(t, "", output)
+	assert.Equal(t, 1, len(logger.queries))
+	assert.True(t, strings.HasPrefix(logger.queries[0], "SELECT"))
+	assert.Equal(t, []interface{}{int64(2)}, logger.args[0])
+}
+
+func TestRedactLogArgs(t *testing.T) {
+	var redacted []string
+	RegisterLogRedactor(func(table, col string, val interface{}) interface{} {
+		redacted = append(redacted, table+"."+col)
+		return val
+	})
+	defer RegisterLogRedactor(nil)
+
+	mi, _ := modelCache.getByMd(&User{})
+	args := []interface{}{"slene", "secret", 1}
+	ctx := withArgFields(context.Background(), []*fieldInfo{mi.fields.GetByColumn("user_name"), mi.fields.GetByColumn("password"), nil})
+	assert.Equal(t, []interface{}{"slene", LogRedacted, 1}, redactLogArgs(ctx, args))
+	assert.Equal(t, []string{"user.user_name", "."}, redacted)
+	assert.Equal(t, "secret", args[1])
+
+	// the fields of ctx are ignored if they don't match the args
+	redacted = nil
+	assert.Equal(t, args[:2], redactLogArgs(ctx, args[:2]))
+	assert.Equal(t, []string{".", "."}, redacted)
+}
@@ -0,0 +1,221 @@
+// Copyright 2020 beego
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// union of querySets, the orders and limit apply to the whole result.
+type unionSet struct {
+	parts  []*querySet
+	all    []bool // whether parts[i] is joined by UNION ALL, all[0] is not used
+	orders []string
+	limit  int64
+	offset int64
+}
+
+var _ UnionSeter = new(unionSet)
+
+func newUnionSet(qs *querySet, other QuerySeter, all bool) UnionSeter {
+	u := &unionSet{
+		parts: []*querySet{qs},
+		all:   []bool{false},
+	}
+	return u.union(other, all)
+}
+
+func (u unionSet) union(other QuerySeter, all bool) UnionSeter {
+	qs, ok := other.(*querySet)
+	if !ok {
+		panic(fmt.Errorf("<UnionSeter> unknown QuerySeter type `%T`", other))
+	}
+	// copy on append, the unionSet may be shared
+	u.parts = append(u.parts[:len(u.parts):len(u.parts)], qs)
+	u.all = append(u.all[:len(u.all):len(u.all)], all)
+	return &u
+}
+
+// combine another QuerySeter with UNION
+func (u unionSet) Union(other QuerySeter) UnionSeter {
+	return u.union(other, false)
+}
+
+// combine another QuerySeter with UNION ALL
+func (u unionSet) UnionAll(other QuerySeter) UnionSeter {
+	return u.union(other, true)
+}
+
+// add ORDER expression of the whole result.
+// "column" means ASC, "-column" means DESC.
+func (u unionSet) OrderBy(exprs ...string) UnionSeter {
+	u.orders = exprs
+	return &u
+}
+
+// add LIMIT value of the whole result.
+// args[0] means offset, e.g. LIMIT num,offset.
+func (u unionSet) Limit(limit interface{}, args ...interface{}) UnionSeter {
+	u.limit = ToInt64(limit)
+	if len(args) > 0 {
+		u.offset = ToInt64(args[0])
+	}
+	return &u
+}
+
+// add OFFSET value of the whole result
+func (u unionSet) Offset(offset interface{}) UnionSeter {
+	u.offset = ToInt64(offset)
+	return &u
+}
+
+// query all rows into container, the same as RawSeter.QueryRows
+func (u *unionSet) All(container interface{}, cols ...string) (int64, error) {
+	return u.AllWithCtx(u.parts[0].orm.defaultCtx(), container, cols...)
+}
+
+func (u *unionSet) AllWithCtx(ctx context.Context, container interface{}, cols ...string) (int64, error) {
+	query, args, fields, err := u.getSQL(ctx, cols)
+	if err != nil {
+		return 0, err
+	}
+	return u.parts[0].orm.Raw(query, args...).QueryRowsWithCtx(withArgFields(ctx, fields), container)
+}
+
+// query all rows into []map[string]interface, the same as RawSeter.Values
+func (u *unionSet) Values(results *[]Params, exprs ...string) (int64, error) {
+	return u.ValuesWithCtx(u.parts[0].orm.defaultCtx(), results, exprs...)
+}
+
+func (u *unionSet) ValuesWithCtx(ctx context.Context, results *[]Params, exprs ...string) (int64, error) {
+	return u.readValues(ctx, results, exprs)
+}
+
+// query all rows into [][]interface, the same as RawSeter.ValuesList
+func (u *unionSet) ValuesList(results *[]ParamsList, exprs ...string) (int64, error) {
+	return u.ValuesListWithCtx(u.parts[0].orm.defaultCtx(), results, exprs...)
+}
+
+func (u *unionSet) ValuesListWithCtx(ctx context.Context, results *[]ParamsList, exprs ...string) (int64, error) {
+	return u.readValues(ctx, results, exprs)
+}
+
+func (u *unionSet) readValues(ctx context.Context, container interface{}, exprs []string) (int64, error) {
+	query, args, fields, err := u.getSQL(ctx, exprs)
+	if err != nil {
+		return 0, err
+	}
+	rs := &rawSet{query: query, args: args, orm: u.parts[0].orm}
+	return rs.readValues(withArgFields(ctx, fields), container, exprs)
+}
+
+// generate the union sql with unreplaced marks and the fields bound to the args
+func (u *unionSet) getSQL(ctx context.Context, cols []string) (string, []interface{}, []*fieldInfo, error) {
+	first := u.parts[0]
+	al := first.orm.alias
+	Q := al.DbBaser.TableQuote()
+
+	var (
+		query   string
+		args    []interface{}
+		fields  []*fieldInfo
+		colsNum int
+	)
+	for i, qs := range u.parts {
+		sel, params, fs, num, err := getUnionSelectSQL(ctx, al.DbBaser, qs, cols, qs.getTZ())
+		if err != nil {
+			return "", nil, nil, err
+		}
+		if i == 0 {
+			colsNum = num
+		} else if num != colsNum {
+			return "", nil, nil, fmt.Errorf("<UnionSeter> each query of union must have the same number of columns, %d and %d", colsNum, num)
+		}
+
+		// sqlite doesn't allow parenthesized select in union
+		if al.Driver != DRSqlite {
+			sel = "(" + sel + ")"
+		}
+		if i > 0 {
+			if u.all[i] {
+				query += " UNION ALL "
+			} else {
+				query += " UNION "
+			}
+		}
+		query += sel
+		args = append(args, params...)
+		fields = append(fields, fs...)
+	}
+
+	if len(u.orders) > 0 {
+		orders := make([]string, 0, len(u.orders))
+		for _, order := range u.orders {
+			sort := "ASC"
+			if strings.HasPrefix(order, "-") {
+				sort = "DESC"
+				order = order[1:]
+			}
+			fi, ok := first.mi.fields.GetByAny(order)
+			if !ok || !fi.dbcol {
+				return "", nil, nil, fmt.Errorf("%w `%s` for model `%s`", ErrWrongColumn, order, first.mi.fullName)
+			}
+			orders = append(orders, fmt.Sprintf("%s%s%s %s", Q, fi.column, Q, sort))
+		}
+		query += " ORDER BY " + strings.Join(orders, ", ")
+	}
+
+	if limit := newDbTables(first.mi, al.DbBaser).getLimitSQL(first.mi, u.offset, u.limit); limit != "" {
+		query += " " + limit
+	}
+	return query, args, fields, nil
+}
+
+// generate the select sql of one query in union, orders and limit of the query are ignored.
+func getUnionSelectSQL(ctx context.Context, base dbBaser, qs *querySet, cols []string, tz *time.Location) (string, []interface{}, []*fieldInfo, int, error) {
+	mi := qs.mi
+	Q := base.TableQuote()
+
+	tCols := mi.fields.dbcols
+	if len(cols) > 0 {
+		tCols = make([]string, 0, len(cols))
+		for _, col := range cols {
+			fi, ok := mi.fields.GetByAny(col)
+			if !ok || !fi.dbcol {
+				return "", nil, nil, 0, fmt.Errorf("%w `%s` for model `%s`", ErrWrongColumn, col, mi.fullName)
+			}
+			tCols = append(tCols, fi.column)
+		}
+	}
+
+	sep := fmt.Sprintf("%s, T0.%s", Q, Q)
+	sels := fmt.Sprintf("T0.%s%s%s", Q, strings.Join(tCols, sep), Q)
+
+	tables := newDbTables(mi, base)
+	where, args, fields := tables.getCondSQL(qs.scopedCond(ctx), false, tz)
+	groupBy := tables.getGroupSQL(qs.groups)
+	join := tables.getJoinSQL()
+
+	sqlSelect := "SELECT"
+	if qs.distinct {
+		sqlSelect += " DISTINCT"
+	}
+	query := fmt.Sprintf("%s %s FROM %s%s%s T0 %s%s%s",
+		sqlSelect, sels, Q, getQsTable(qs, mi), Q, join, where, groupBy)
+	return strings.TrimSpace(query), args, fields, len(tCols), nil
+}
@@ -50,6 +50,19 @@ type TableEngineI interface {
 	TableEngine() string
 }
 
+// TableCharsetI is usually used by model
+// when you want to use specific default charset of mysql table, like utf8mb4, you can implement this interface
+// for example:
+// type User struct {
+//   ...
+// }
+// func (u *User) TableCharset() string {
+//    return "utf8mb4"
+// }
+type TableCharsetI interface {
+	TableCharset() string
+}
+
 // TableIndexI is usually used by model
 // when you want to create indexes, you can implement this interface
 // for example:
@@ -101,6 +114,11 @@ type TxBeginner interface {
 	BeginWithCtx(ctx context.Context) (TxOrmer, error)
 	BeginWithOpts(opts *sql.TxOptions) (TxOrmer, error)
 	BeginWithCtxAndOpts(ctx context.Context, opts *sql.TxOptions) (TxOrmer, error)
+	// begin transaction with the isolation level,
+	// return ErrIsolation if the driver doesn't support it, e.g. sqlite only supports serializable.
+	BeginReadCommitted(ctx context.Context) (TxOrmer, error)
+	BeginRepeatableRead(ctx context.Context) (TxOrmer, error)
+	BeginSerializable(ctx context.Context) (TxOrmer, error)
 
 	// closure control transaction
 	DoTx(task func(ctx context.Context, txOrm TxOrmer) error) error
@@ -145,17 +163,50 @@ type DML interface {
 	//  user := new(User)
 	//  id, err = Ormer.Insert(user)
 	//  user must be a pointer and Insert will set user's pk field
+	// for unsigned pk larger than math.MaxInt64, which is returned by RETURNING sql,
+	// id keeps the same bits as the pk, use uint64(id) or the pk field of user.
 	Insert(md interface{}) (int64, error)
 	InsertWithCtx(ctx context.Context, md interface{}) (int64, error)
 	// mysql:InsertOrUpdate(model) or InsertOrUpdate(model,"colu=colu+value")
 	// if colu type is integer : can use(+-*/), string : convert(colu,"value")
 	// postgres: InsertOrUpdate(model,"conflictColumnName") or InsertOrUpdate(model,"conflictColumnName","colu=colu+value")
 	// if colu type is integer : can use(+-*/), string : colu || "value"
+	// for partial unique index, append the predicate of the index to the conflict column:
+	// InsertOrUpdate(model,"conflictColumnName WHERE deleted_at IS NULL")
+	// postgres only: the arg starting with WHERE after the conflict column is the condition of update,
+	// the inserted values are referred by EXCLUDED, and the existing row by the table name.
+	// 0 is returned if the row isn't updated for the condition.
+	// InsertOrUpdate(model,"id","WHERE EXCLUDED.updated > post.updated")
 	InsertOrUpdate(md interface{}, colConflitAndArgs ...string) (int64, error)
 	InsertOrUpdateWithCtx(ctx context.Context, md interface{}, colConflitAndArgs ...string) (int64, error)
+	// insert model data to database, do nothing if it conflicts with an existing row.
+	// return the affected rows, 0 means the row is skipped, and the pk field is set if inserted.
+	// mysql: INSERT IGNORE, conflictCols is not used
+	// postgres and sqlite: INSERT ... ON CONFLICT (conflictCols) DO NOTHING, any conflict if conflictCols is empty
+	// for example:
+	//  num, err := Ormer.InsertOrIgnore(&user, "UserName")
+	InsertOrIgnore(md interface{}, conflictCols ...string) (int64, error)
+	InsertOrIgnoreWithCtx(ctx context.Context, md interface{}, conflictCols ...string) (int64, error)
+	// insert model data with the value of pk field even if the pk is auto, e.g. to import data.
+	// the pk must not be zero, it's not read back from database and the pk field is not changed.
+	// return the affected rows.
+	// postgres doesn't advance the sequence of the pk, sync it after the import:
+	//  SELECT setval(pg_get_serial_sequence('user', 'id'), (SELECT MAX(id) FROM "user"))
+	InsertWithPK(md interface{}) (int64, error)
+	InsertWithPKWithCtx(ctx context.Context, md interface{}) (int64, error)
 	// insert some models to database
+	// bulk is lowered if needed so that one statement does not exceed
+	// the placeholder limit of the driver, see SetMaxPlaceholders.
+	// the auto pks generated by the database are set back to the models,
+	// postgres uses RETURNING, mysql and sqlite use the last insert id
+	// which requires consecutive auto increment ids, the other databases don't set them if bulk > 1.
 	InsertMulti(bulk int, mds interface{}) (int64, error)
 	InsertMultiWithCtx(ctx context.Context, bulk int, mds interface{}) (int64, error)
+	// insert some models to database like InsertMulti, and return the sql.Result of each INSERT statement,
+	// so RowsAffected and LastInsertId reported by the driver can be checked. bulk less than 1 is 1.
+	// the result of postgres only has RowsAffected, the pks are returned by RETURNING and set to the models.
+	InsertMultiResult(bulk int, mds interface{}) ([]sql.Result, error)
+	InsertMultiResultWithCtx(ctx context.Context, bulk int, mds interface{}) ([]sql.Result, error)
 	// update model to database.
 	// cols set the columns those want to update.
 	// find model by Id(pk) field and update columns specified by fields, if cols is null then update all columns
@@ -170,6 +221,13 @@ type DML interface {
 	// delete model in database
 	Delete(md interface{}, cols ...string) (int64, error)
 	DeleteWithCtx(ctx context.Context, md interface{}, cols ...string) (int64, error)
+	// delete the rows of model matching cond without loading them.
+	// ErrEmptyCondition is returned if cond is nil or empty, to avoid deleting all rows by accident,
+	// use NewCondition().AllowEmptyCondition() to delete all rows.
+	// for example:
+	//	num, err := Ormer.DeleteWhere(&User{}, NewCondition().And("status", 0))
+	DeleteWhere(md interface{}, cond *Condition) (int64, error)
+	DeleteWhereWithCtx(ctx context.Context, md interface{}, cond *Condition) (int64, error)
 
 	// return a raw query seter for raw sql string.
 	// for example:
@@ -189,9 +247,17 @@ type DQL interface {
 	//	this will find User by UserName field
 	// 	u = &User{UserName: "astaxie", Password: "pass"}
 	//	err = Ormer.Read(u, "UserName")
+	//	unknown field/column name in cols returns ErrWrongColumn
 	Read(md interface{}, cols ...string) error
 	ReadWithCtx(ctx context.Context, md interface{}, cols ...string) error
 
+	// Like Read(), but the times are converted in loc instead of the time zone of the database.
+	// for example:
+	//	loc, _ := time.LoadLocation("Asia/Shanghai")
+	//	err = Ormer.ReadInLocation(u, loc)
+	ReadInLocation(md interface{}, loc *time.Location, cols ...string) error
+	ReadInLocationWithCtx(ctx context.Context, md interface{}, loc *time.Location, cols ...string) error
+
 	// Like Read(), but with "FOR UPDATE" clause, useful in transaction.
 	// Some databases are not support this feature.
 	ReadForUpdate(md interface{}, cols ...string) error
@@ -201,6 +267,15 @@ type DQL interface {
 	ReadOrCreate(md interface{}, col1 string, cols ...string) (bool, int64, error)
 	ReadOrCreateWithCtx(ctx context.Context, md interface{}, col1 string, cols ...string) (bool, int64, error)
 
+	// Like ReadOrCreate(), but for a slice of models.
+	// the existing rows are read in one query by lookupCols, then the missing models are inserted.
+	// return the created flags in the order of mds.
+	// it isn't atomic, use it in a transaction with a unique index on lookupCols.
+	//	users := []*User{{Name: "slene"}, {Name: "astaxie"}}
+	//	created, err := o.ReadOrCreateMulti(&users, []string{"Name"})
+	ReadOrCreateMulti(mds interface{}, lookupCols []string) ([]bool, error)
+	ReadOrCreateMultiWithCtx(ctx context.Context, mds interface{}, lookupCols []string) ([]bool, error)
+
 	// load related models to md model.
 	// args are limit, offset int and order string.
 	//
@@ -216,6 +291,16 @@ type DQL interface {
 	LoadRelated(md interface{}, name string, args ...utils.KV) (int64, error)
 	LoadRelatedWithCtx(ctx context.Context, md interface{}, name string, args ...utils.KV) (int64, error)
 
+	// load related models to every model in mds, mds is a slice of models.
+	// the related rows are fetched with one IN query instead of one query per model.
+	// args are the same as LoadRelated, except hints.Limit and hints.Offset are not supported.
+	//
+	// example:
+	// 	Ormer.LoadRelatedBatch(posts, "Tags")
+	// 	for _, post := range posts {...}
+	LoadRelatedBatch(mds interface{}, name string, args ...utils.KV) (int64, error)
+	LoadRelatedBatchWithCtx(ctx context.Context, mds interface{}, name string, args ...utils.KV) (int64, error)
+
 	// create a models to models queryer
 	// for example:
 	// 	post := Post{Id: 4}
@@ -229,11 +314,19 @@ type DQL interface {
 	// table name can be string or struct.
 	// e.g. QueryTable("user"), QueryTable(&user{}) or QueryTable((*User)(nil)),
 	QueryTable(ptrStructOrTableName interface{}) QuerySeter
+	// like QueryTable, but return ErrTableNotFound instead of panic if the table is not registered.
+	// for example:
+	//	qs, err := o.QueryTableE(name)
+	QueryTableE(ptrStructOrTableName interface{}) (QuerySeter, error)
 	// NOTE: this method is deprecated, context parameter will not take effect.
 	// Use context.Context directly on methods with `WithCtx` suffix such as InsertWithCtx/UpdateWithCtx
 	QueryTableWithCtx(ctx context.Context, ptrStructOrTableName interface{}) QuerySeter
 
 	DBStats() *sql.DBStats
+	// check the database is alive
+	// for example:
+	//	err := o.Ping(ctx)
+	Ping(ctx context.Context) error
 }
 
 type DriverGetter interface {
@@ -254,11 +347,67 @@ type QueryExecutor interface {
 type Ormer interface {
 	QueryExecutor
 	TxBeginner
+
+	// return a new Ormer in dry run mode, which doesn't execute the sql
+	// but records it, use LastSQL to get the statement.
+	// the statements without rows like Insert/Update/Delete return zero result,
+	// the queries return ErrDryRun, or context.Canceled if they read a single row.
+	// for example:
+	//	dry := o.DryRun()
+	//	dry.Update(&user, "Name")
+	//	query, args := dry.LastSQL()
+	DryRun() Ormer
+	// return the last sql and args recorded in dry run mode
+	LastSQL() (string, []interface{})
+	// return a new Ormer which records every executed sql to recorder,
+	// including the transactions begun from it.
+	WithSQLRecorder(recorder *SQLRecorder) Ormer
+	// return the *sql.DB of the database, for the statements the orm can't express
+	RawDB() *sql.DB
 }
 
+// the methods without ctx of TxOrmer use the context which begins the transaction
 type TxOrmer interface {
 	QueryExecutor
 	TxCommitter
+	// return the *sql.Tx of the transaction, for the statements the orm can't express.
+	// it's nil after the transaction is committed or rolled back.
+	DB() *sql.Tx
+	// create a savepoint named name in the transaction, the name must be an identifier.
+	// for example:
+	//	txOrm.Savepoint("before_posts")
+	//	if _, err := txOrm.InsertMulti(100, posts); err != nil {
+	//		txOrm.RollbackTo("before_posts") // the user inserted before is kept
+	//	}
+	Savepoint(name string) error
+	// roll back the changes after the savepoint name, the transaction goes on.
+	RollbackTo(name string) error
+	// release the savepoint name, the changes after it are kept.
+	ReleaseSavepoint(name string) error
+	// execute the statements in order and return the result of each statement,
+	// it stops at the first failed statement and returns the results before it.
+	// for mysql with multiStatements=true in the data source, the statements without args
+	// are sent in one round trip, the only result returned is a *JoinedResult for the whole batch.
+	// for example:
+	//	txOrm.ExecBatch([]RawStmt{
+	//		{SQL: "ALTER TABLE user ADD COLUMN nick varchar(64)"},
+	//		{SQL: "UPDATE user SET nick = user_name"},
+	//	})
+	ExecBatch(stmts []RawStmt) ([]sql.Result, error)
+}
+
+// RawStmt is one statement of TxOrmer.ExecBatch
+type RawStmt struct {
+	SQL  string
+	Args []interface{}
+}
+
+// JoinedResult is the result of the statements which TxOrmer.ExecBatch sends in one round trip,
+// the driver reports the rows affected and the last insert id of the whole batch only.
+type JoinedResult struct {
+	sql.Result
+	// the number of the joined statements
+	Stmts int
 }
 
 // Inserter insert prepared statement
@@ -281,12 +430,57 @@ type QuerySeter interface {
 	Filter(string, ...interface{}) QuerySeter
 	// add raw sql to querySeter.
 	// for example:
-	// qs.FilterRaw("user_id IN (SELECT id FROM profile WHERE age>=18)")
+	// qs.FilterRaw("user_id", "IN (SELECT id FROM profile WHERE age>=?)", 18)
 	// //sql-> WHERE user_id IN (SELECT id FROM profile WHERE age>=18)
-	FilterRaw(string, string) QuerySeter
+	// if the field is empty, the raw sql is a whole predicate joined by AND:
+	// qs.FilterRaw("", "data @> ?", `{"a": 1}`)
+	// //sql-> WHERE ( data @> '{"a": 1}')
+	FilterRaw(field string, sql string, args ...interface{}) QuerySeter
+	// add IS NULL condition on the field to querySeter, no value is bound.
+	// for example:
+	//	qs.FilterIsNull("profile")
+	//	//sql-> WHERE T0.profile_id IS NULL
+	FilterIsNull(field string) QuerySeter
+	// add IS NOT NULL condition on the field to querySeter, no value is bound.
+	FilterIsNotNull(field string) QuerySeter
+	// add full text search condition on the columns to querySeter.
+	// MySQL uses MATCH(cols) AGAINST(query IN BOOLEAN MODE), the columns need a FULLTEXT index,
+	// PostgreSQL uses to_tsvector(cols) @@ plainto_tsquery(query),
+	// the other drivers panic with ErrNotImplement.
+	// for example:
+	//	qs.FilterFullText([]string{"title", "content"}, "golang orm")
+	FilterFullText(cols []string, query string) QuerySeter
 	// add NOT condition to querySeter.
 	// have the same usage as Filter
 	Exclude(string, ...interface{}) QuerySeter
+	// add the NOT EXISTS correlated subquery on the related rows of rel, e.g. the anti-join.
+	// rel is a foreign key, one to one or reverse relation field of the model,
+	// cond is the condition of the related model and can be nil.
+	// for example:
+	//	qs.QueryTable("user").ExcludeExists("posts", orm.NewCondition().And("title__contains", "orm"))
+	//	//sql-> WHERE NOT EXISTS (SELECT 1 FROM post TS0 WHERE TS0.user_id = T0.id AND ( TS0.title LIKE '%orm%' ) )
+	ExcludeExists(rel string, cond *Condition) QuerySeter
+	// add the condition to the current conditions with AND.
+	// for example:
+	//	cond := orm.NewCondition().And("age__gt", 18).Or("is_staff", true)
+	//	qs.Filter("status", 1).FilterCond(cond)
+	//	//sql-> WHERE status = 1 AND ( age > 18 OR is_staff = true )
+	FilterCond(cond *Condition) QuerySeter
+	// add the condition to the current conditions with AND NOT.
+	// have the same usage as FilterCond
+	ExcludeCond(cond *Condition) QuerySeter
+	// add condition expression to the current conditions with OR, have the same usage as Filter.
+	// the conditions are joined in order like Condition, AND takes precedence over OR.
+	// for example:
+	//	qs.Filter("status", 1).FilterOr("is_staff", true)
+	//	//sql-> WHERE status = 1 OR is_staff = true
+	FilterOr(expr string, args ...interface{}) QuerySeter
+	// add the condition to the current conditions with OR, the condition is grouped.
+	// for example:
+	//	cond := orm.NewCondition().And("age__gt", 18).And("is_staff", true)
+	//	qs.Filter("status", 1).FilterOrCond(cond)
+	//	//sql-> WHERE status = 1 OR ( age > 18 AND is_staff = true )
+	FilterOrCond(cond *Condition) QuerySeter
 	// set condition to QuerySeter.
 	// sql's where condition
 	//	cond := orm.NewCondition()
@@ -304,26 +498,90 @@ type QuerySeter interface {
 	//  //sql-> WHERE T0.`profile_id` IS NOT NULL AND NOT T0.`Status` IN (?) OR T1.`age` >  2000
 	//  num, err := qs.SetCond(cond).Count()
 	GetCond() *Condition
+	// return a copy of QuerySeter, which can be changed without affecting the original one.
+	// for example:
+	//	base := o.QueryTable("user").Filter("status", 1)
+	//	count, err := base.Clone().Count()
+	//	num, err := base.Clone().OrderBy("-id").Limit(10).All(&users)
+	Clone() QuerySeter
 	// add LIMIT value.
 	// args[0] means offset, e.g. LIMIT num,offset.
-	// if Limit <= 0 then Limit will be set to default limit ,eg 1000
-	// if QuerySeter doesn't call Limit, the sql's Limit will be set to default limit, eg 1000
+	// if Limit is 0 or QuerySeter doesn't call Limit, the sql's Limit will be set to DefaultRowsLimit,
+	// which is -1 (no limit) by default. Limit(-1) is the same as Unlimited,
+	// the other negative values and the values overflow int64 panic.
 	//  for example:
 	//	qs.Limit(10, 2)
 	//	// sql-> limit 10 offset 2
 	Limit(limit interface{}, args ...interface{}) QuerySeter
 	// add OFFSET value
 	// same as Limit function's args[0]
+	// the negative offset panics.
 	Offset(offset interface{}) QuerySeter
+	// remove the limit, even if DefaultRowsLimit is set.
+	// for example:
+	//	DefaultRowsLimit = 1000
+	//	qs.Unlimited().All(&users)
+	//	// sql-> no limit
+	Unlimited() QuerySeter
+	// execute the sql directly instead of the prepared statement cached by the StmtCacheSize option,
+	// it keeps the one-off queries out of the cache.
+	// for example:
+	//	qs.Filter("name__in", names).NoStmtCache().All(&users)
+	NoStmtCache() QuerySeter
+	// add keyset pagination condition, query the rows after the cursor value ordered by the cursor columns.
+	// col can be several columns separated by comma for composite cursors, then value is a []interface{}.
+	// for example:
+	//	qs.SeekGt("id", 100).Limit(10)
+	//	// sql-> WHERE id > 100 ORDER BY id LIMIT 10
+	//	qs.SeekGt("created,id", []interface{}{created, 100}).Limit(10)
+	//	// sql-> WHERE (created > ?) OR (created = ? AND id > ?) ORDER BY created, id LIMIT 10
+	SeekGt(col string, value interface{}) QuerySeter
 	// add GROUP BY expression
 	// for example:
 	//	qs.GroupBy("id")
 	GroupBy(exprs ...string) QuerySeter
+	// add HAVING condition on the alias defined by Aggregate, have the same usage as Filter.
+	// the supported operators are exact, gt, gte, lt, lte, in and between.
+	// for example:
+	//	qs.Aggregate("dept_name,sum(salary) as total").GroupBy("dept_name").Having("total__gt", 3000)
+	//	//sql-> GROUP BY dept_name HAVING sum(salary) > 3000
+	Having(expr string, args ...interface{}) QuerySeter
+	// add the values of col joined by sep in each group to the result of Values, ValuesList and ValuesFlat,
+	// the joined string is named alias. use it with GroupBy.
+	// for example:
+	//	qs.GroupBy("user").GroupConcat("title", "titles", ",").Values(&maps, "user")
+	//	// mysql:    SELECT T0.`user_id`, GROUP_CONCAT(T0.`title` SEPARATOR ',') `titles` ... GROUP BY T0.`user_id`
+	//	// postgres: SELECT T0."user_id", string_agg(CAST(T0."title" AS TEXT), ',') "titles" ...
+	GroupConcat(col, alias, sep string) QuerySeter
+	// add a column value for Update, the columns are set in the order of Set,
+	// and the values of Update are set after them.
+	// for example:
+	//	num, err = qs.Filter("user_name", "slene").
+	//		Set("nums", ColValue(ColAdd, 10)).
+	//		Set("status", 2).
+	//		Update(nil)
+	//	// UPDATE ... SET `nums` = `nums` + ?, `status` = ? WHERE ...
+	Set(col string, value interface{}) QuerySeter
 	// add ORDER expression.
 	// "column" means ASC, "-column" means DESC.
 	// for example:
 	//	qs.OrderBy("-status")
 	OrderBy(exprs ...string) QuerySeter
+	// append ORDER expression with the position of NULL values, NullsFirst or NullsLast.
+	// postgres and oracle use NULLS FIRST/LAST, mysql and tidb emulate it by ISNULL(column).
+	// sqlite ignores it, NULL values come first in ASC order and last in DESC order.
+	// for example:
+	//	qs.OrderBy("-status").OrderByNulls("profile__age", true, orm.NullsLast)
+	//	// sql-> ORDER BY status DESC, age DESC NULLS LAST
+	OrderByNulls(col string, desc bool, nulls NullsFirstOrLast) QuerySeter
+	// append random ORDER expression of the driver, after the other ORDER expressions.
+	// mysql and tidb use RAND(), postgres and sqlite use RANDOM(), oracle uses DBMS_RANDOM.VALUE.
+	// the database sorts all the matched rows to pick the rows,
+	// so it is slow on large tables, filter the rows as much as possible.
+	// for example:
+	//	qs.Filter("status", 1).OrderRandom().Limit(1).One(&product)
+	//	// sql-> WHERE status = 1 ORDER BY RAND() LIMIT 1
+	OrderRandom() QuerySeter
 	// add ORDER expression by order clauses
 	// for example:
 	//	OrderClauses(
@@ -350,17 +608,25 @@ type QuerySeter interface {
 	// for example:
 	//	qs.ForceIndex(`idx_name1`,`idx_name2`)
 	// ForceIndex, UseIndex , IgnoreIndex are mutually exclusive
+	// sqlite uses the first index in INDEXED BY, postgres ignores it.
 	ForceIndex(indexes ...string) QuerySeter
 	// add USE INDEX expression.
 	// for example:
 	//	qs.UseIndex(`idx_name1`,`idx_name2`)
 	// ForceIndex, UseIndex , IgnoreIndex are mutually exclusive
+	// sqlite uses the first index in INDEXED BY, postgres ignores it.
 	UseIndex(indexes ...string) QuerySeter
 	// add IGNORE INDEX expression.
 	// for example:
 	//	qs.IgnoreIndex(`idx_name1`,`idx_name2`)
 	// ForceIndex, UseIndex , IgnoreIndex are mutually exclusive
+	// sqlite and postgres ignore it.
 	IgnoreIndex(indexes ...string) QuerySeter
+	// use another table with the same structure as the model's table,
+	// it's useful for sharded or partitioned tables.
+	// for example:
+	//	qs.UseTable("events_202401").Filter("id__gt", 10).All(&events)
+	UseTable(table string) QuerySeter
 	// set relation model to query together.
 	// it will query relation models and assign to parent model.
 	// for example:
@@ -369,7 +635,19 @@ type QuerySeter interface {
 	//	// will  load related field only profile
 	//	qs.RelatedSel("profile").One(&user)
 	//	user.Profile.Age = 32
+	//	// will load the relations of the paths only, the models on the path are loaded too
+	//	qs.RelatedSel("user", "user__profile").All(&posts)
+	//	posts[0].User.Profile.Age = 32
+	// the paths don't use DefaultRelsDepth, use an int param to set the depth of other relations.
 	RelatedSel(params ...interface{}) QuerySeter
+	// set the columns to query, the columns of the related models selected by RelatedSel
+	// are the paths like "user__user_name", the pk and the relation columns of the related models are always queried.
+	// the columns of the model are used if the cols of All/One are empty, and the
+	// related models without columns set are queried fully.
+	// for example:
+	//	qs.RelatedSel("user").SelectColumns("id", "title", "user__user_name").All(&posts)
+	//	// posts[0].User has the ID and UserName only
+	SelectColumns(cols ...string) QuerySeter
 	// Set Distinct
 	// for example:
 	//  o.QueryTable("policy").Filter("Groups__Group__Users__User", user).
@@ -380,11 +658,41 @@ type QuerySeter interface {
 	// for example:
 	//  o.QueryTable("user").Filter("uid", uid).ForUpdate().All(&users)
 	ForUpdate() QuerySeter
+	// set FOR UPDATE NOWAIT to query, fail with ErrLockNotAvailable instead of waiting
+	// if the rows are locked by other transactions.
+	// only mysql 8.0+ and postgres support it, others return ErrNotImplement.
+	// for example:
+	//  err := txOrm.QueryTable("user").Filter("uid", uid).ForUpdateNoWait().One(&user)
+	//  if errors.Is(err, orm.ErrLockNotAvailable) {
+	//      // retry later
+	//  }
+	ForUpdateNoWait() QuerySeter
 	// return QuerySeter execution result number
 	// for example:
 	//	num, err = qs.Filter("profile__age__gt", 28).Count()
 	Count() (int64, error)
 	CountWithCtx(context.Context) (int64, error)
+	// return the number of distinct non-NULL values of the column, e.g. COUNT(DISTINCT T0.`age`)
+	// for example:
+	//	num, err = qs.Filter("status", 1).CountDistinct("profile__age")
+	CountDistinct(col string) (int64, error)
+	CountDistinctWithCtx(ctx context.Context, col string) (int64, error)
+	// query the aggregate function of the column and scan it into result,
+	// which must be a pointer, e.g. *int64, *float64 or *time.Time for Max and Min.
+	// result is set to zero value if the aggregate value is NULL, e.g. no row matches.
+	// for example:
+	//	var total int64
+	//	err = qs.Filter("user__user_name", "slene").Sum("score", &total)
+	//	var avg float64
+	//	err = qs.Avg("profile__age", &avg)
+	Sum(expr string, result interface{}) error
+	SumWithCtx(ctx context.Context, expr string, result interface{}) error
+	Max(expr string, result interface{}) error
+	MaxWithCtx(ctx context.Context, expr string, result interface{}) error
+	Min(expr string, result interface{}) error
+	MinWithCtx(ctx context.Context, expr string, result interface{}) error
+	Avg(expr string, result interface{}) error
+	AvgWithCtx(ctx context.Context, expr string, result interface{}) error
 	// check result empty or not after QuerySeter executed
 	// the same as QuerySeter.Count > 0
 	Exist() bool
@@ -414,13 +722,38 @@ type QuerySeter interface {
 	//	err = i.Close() //don't forget call Close
 	PrepareInsert() (Inserter, error)
 	PrepareInsertWithCtx(context.Context) (Inserter, error)
+	// prepare the select statement of all columns, it can be queried in times with the different args.
+	// the args are bound to the placeholders of the conditions in order,
+	// so they are the same number as the flattened args of the conditions, e.g. the values of IN.
+	// example:
+	//	p, err := qs.Filter("user_name", "").Filter("status__gt", 0).Prepare()
+	//	num, err = p.All(&users, "slene", 1)
+	//	err = p.One(&user, "astaxie", 2)
+	//	err = p.Close() //don't forget call Close
+	// the models scoped by the query scoper can't be prepared, the scope must be derived from
+	// the ctx of each query rather than bound by the caller.
+	Prepare() (PreparedQuerySeter, error)
+	PrepareWithCtx(context.Context) (PreparedQuerySeter, error)
 	// query all data and map to containers.
 	// cols means the columns when querying.
 	// for example:
 	//	var users []*User
 	//	qs.All(&users) // users[0],users[1],users[2] ...
+	// if the IN list of Filter("id__in", ids) exceeds the max placeholders of the driver,
+	// see SetMaxPlaceholders, the list is split into chunks which are queried one by one,
+	// and the rows are merged into container. the duplicate values of the list are removed,
+	// and it returns error with Limit, Offset, OrderBy, GroupBy, Distinct or Aggregate.
 	All(container interface{}, cols ...string) (int64, error)
 	AllWithCtx(ctx context.Context, container interface{}, cols ...string) (int64, error)
+	// query all rows into container like All, and return the total count of rows without limit and offset.
+	// the total is selected by COUNT(*) OVER() in one query, mysql needs 8.0 and sqlite needs 3.25.
+	// it falls back to All and Count if the driver doesn't support window function,
+	// or with Distinct or ForUpdate.
+	// for example:
+	//	var users []*User
+	//	total, err := qs.OrderBy("id").Limit(10, 20).AllWithTotalCount(&users)
+	AllWithTotalCount(container interface{}, cols ...string) (int64, error)
+	AllWithTotalCountWithCtx(ctx context.Context, container interface{}, cols ...string) (int64, error)
 	// query one row data and map to containers.
 	// cols means the columns when querying.
 	// for example:
@@ -428,6 +761,30 @@ type QuerySeter interface {
 	//	qs.One(&user) //user.UserName == "slene"
 	One(container interface{}, cols ...string) error
 	OneWithCtx(ctx context.Context, container interface{}, cols ...string) error
+	// query the first row by the current order and map to container,
+	// it doesn't check whether more rows match, ErrNoRows is returned if no row found.
+	// for example:
+	//	var user User
+	//	err := qs.Filter("is_staff", true).OrderBy("-created").First(&user)
+	First(container interface{}, cols ...string) error
+	FirstWithCtx(ctx context.Context, container interface{}, cols ...string) error
+	// return the query plan of the query All would run, the plan rows are returned as text as-is,
+	// one line per row and the columns are separated by tab.
+	// for example:
+	//	plan, err := qs.Filter("user_name", "slene").Explain(ctx)
+	Explain(ctx context.Context) (string, error)
+	// like Explain, but use EXPLAIN ANALYZE, note that the query is executed by the database.
+	// sqlite doesn't support it and returns ErrNotImplement.
+	ExplainAnalyze(ctx context.Context) (string, error)
+	// query a page of rows after the cursor value by keyset pagination,
+	// and return the cursor value of the last row for the next page, nil if no rows.
+	// the first page is queried when after is nil.
+	// for example:
+	//	var users []*User
+	//	cursor, err := qs.Paginate(&users, "id", nil, 10)
+	//	cursor, err = qs.Paginate(&users, "id", cursor, 10)
+	Paginate(container interface{}, col string, after interface{}, limit int) (interface{}, error)
+	PaginateWithCtx(ctx context.Context, container interface{}, col string, after interface{}, limit int) (interface{}, error)
 	// query all data and map to []map[string]interface.
 	// expres means condition expression.
 	// it converts data to []map[column]value.
@@ -436,6 +793,13 @@ type QuerySeter interface {
 	//	qs.Values(&maps) //maps[0]["UserName"]=="slene"
 	Values(results *[]Params, exprs ...string) (int64, error)
 	ValuesWithCtx(ctx context.Context, results *[]Params, exprs ...string) (int64, error)
+	// query one row data and map to Params, like Values.
+	// return ErrNoRows if no row found, ErrMultiRows if more than one row found.
+	// for example:
+	//	var m Params
+	//	err := qs.Filter("user_name", "slene").ValuesOne(&m, "id", "email")
+	ValuesOne(result *Params, exprs ...string) error
+	ValuesOneWithCtx(ctx context.Context, result *Params, exprs ...string) error
 	// query all data and map to [][]interface
 	// it converts data to [][column_index]value
 	// for example:
@@ -443,6 +807,30 @@ type QuerySeter interface {
 	//	qs.ValuesList(&list) // list[0][1] == "slene"
 	ValuesList(results *[]ParamsList, exprs ...string) (int64, error)
 	ValuesListWithCtx(ctx context.Context, results *[]ParamsList, exprs ...string) (int64, error)
+	// query all data and map to [][]interface like ValuesList,
+	// the values are converted to the go types of the model fields instead of
+	// int64, float64, string, etc. the value of relation field is the pk of related model.
+	// NULL is nil whatever the type of field is.
+	// for example:
+	//	var list []ParamsList
+	//	qs.ValuesListTyped(&list, "id", "status", "created")
+	//	status := list[0][1].(int16)
+	//	created := list[0][2].(time.Time)
+	ValuesListTyped(results *[]ParamsList, exprs ...string) (int64, error)
+	ValuesListTypedWithCtx(ctx context.Context, results *[]ParamsList, exprs ...string) (int64, error)
+	// query the exprs and map the rows to the struct slice container like RawSeter.QueryRows,
+	// the struct doesn't need to be registered, so it fits the ad-hoc shape of joins.
+	// the columns are named by the exprs, or by the column names if no expr is given,
+	// and mapped to the fields by the column of orm tag, the db tag or the field name.
+	// for example:
+	//	type UserPost struct {
+	//		Title    string
+	//		UserName string `db:"user__user_name"`
+	//	}
+	//	var rows []UserPost
+	//	qs.Scan(&rows, "title", "user__user_name")
+	Scan(container interface{}, exprs ...string) (int64, error)
+	ScanWithCtx(ctx context.Context, container interface{}, exprs ...string) (int64, error)
 	// query all data and map to []interface.
 	// it's designed for one column record set, auto change to []value, not [][column]value.
 	// for example:
@@ -481,6 +869,69 @@ type QuerySeter interface {
 	// var res []result
 	//  o.QueryTable("dept_info").Aggregate("dept_name,sum(salary) as total").GroupBy("dept_name").All(&res)
 	Aggregate(s string) QuerySeter
+	// fold all rows into one value, rows are read one by one without loading into a slice.
+	// md is a pointer to the model.
+	// it stops when ctx is done, and returns the partial result with the error.
+	// for example:
+	//	total, err := qs.Reduce(ctx, 0, func(acc, md interface{}) interface{} {
+	//		return acc.(int) + md.(*User).Nums
+	//	})
+	Reduce(ctx context.Context, initial interface{}, fn func(acc, md interface{}) interface{}) (interface{}, error)
+	// read the rows one by one and send them to the returned channel, md is a pointer to the model.
+	// the rows are read in a new goroutine, it blocks when the channel is full.
+	// both channels are closed when all rows are sent or ctx is done,
+	// the error channel receives the error of the query or ctx at most once.
+	// for example:
+	//	rows, errs := qs.Channel(ctx, 100)
+	//	for md := range rows {
+	//		export(md.(*User))
+	//	}
+	//	if err := <-errs; err != nil {
+	//		...
+	//	}
+	Channel(ctx context.Context, bufSize int) (<-chan interface{}, <-chan error)
+	// combine the rows of another QuerySeter with UNION, duplicate rows are removed.
+	// both queries must select the same number of compatible columns,
+	// their own OrderBy and Limit are ignored, use the ones of UnionSeter instead.
+	// for example:
+	//	var users []*User
+	//	qs1 := o.QueryTable("user").Filter("status", 1)
+	//	qs2 := o.QueryTable("user").Filter("is_staff", true)
+	//	num, err := qs1.Union(qs2).OrderBy("-id").Limit(10).All(&users)
+	Union(other QuerySeter) UnionSeter
+	// like Union, but use UNION ALL, which keeps duplicate rows
+	UnionAll(other QuerySeter) UnionSeter
+	// convert the times of the query in loc instead of the time zone of the database,
+	// both the time args of conditions and the time fields read.
+	// for example:
+	//	qs.InLocation(time.UTC).Filter("created__gte", start).All(&users)
+	InLocation(loc *time.Location) QuerySeter
+}
+
+// UnionSeter union of QuerySeters
+// create from QuerySeter.Union or QuerySeter.UnionAll
+type UnionSeter interface {
+	Union(other QuerySeter) UnionSeter
+	UnionAll(other QuerySeter) UnionSeter
+	// add ORDER expression of the union result.
+	// "column" means ASC, "-column" means DESC.
+	// the fields are resolved by the model of the first QuerySeter.
+	OrderBy(exprs ...string) UnionSeter
+	// add LIMIT value of the union result.
+	// args[0] means offset, e.g. LIMIT num,offset.
+	Limit(limit interface{}, args ...interface{}) UnionSeter
+	// add OFFSET value of the union result
+	Offset(offset interface{}) UnionSeter
+	// query all rows into container, rows are mapped by column name like RawSeter.QueryRows.
+	// cols are the fields selected from every query, all fields are selected if empty.
+	All(container interface{}, cols ...string) (int64, error)
+	AllWithCtx(ctx context.Context, container interface{}, cols ...string) (int64, error)
+	// query all rows into []map[string]interface, the keys are column names.
+	Values(results *[]Params, exprs ...string) (int64, error)
+	ValuesWithCtx(ctx context.Context, results *[]Params, exprs ...string) (int64, error)
+	// query all rows into [][]interface
+	ValuesList(results *[]ParamsList, exprs ...string) (int64, error)
+	ValuesListWithCtx(ctx context.Context, results *[]ParamsList, exprs ...string) (int64, error)
 }
 
 // QueryM2Mer model to model query struct
@@ -517,6 +968,19 @@ type QueryM2Mer interface {
 	CountWithCtx(context.Context) (int64, error)
 }
 
+// PreparedQuerySeter the prepared select statement of QuerySeter
+// create From QuerySeter.Prepare
+type PreparedQuerySeter interface {
+	// query all rows with the args into container, like QuerySeter.All
+	All(container interface{}, args ...interface{}) (int64, error)
+	AllWithCtx(ctx context.Context, container interface{}, args ...interface{}) (int64, error)
+	// query one row with the args into container, like QuerySeter.One,
+	// ErrMultiRows is returned if more than one row is found
+	One(container interface{}, args ...interface{}) error
+	OneWithCtx(ctx context.Context, container interface{}, args ...interface{}) error
+	Close() error
+}
+
 // RawPreparer raw query statement
 type RawPreparer interface {
 	Exec(...interface{}) (sql.Result, error)
@@ -531,20 +995,40 @@ type RawPreparer interface {
 type RawSeter interface {
 	// execute sql and get result
 	Exec() (sql.Result, error)
+	ExecWithCtx(ctx context.Context) (sql.Result, error)
 	// query data and map to container
 	// for example:
 	//	var name string
 	//	var id int
 	//	rs.QueryRow(&id,&name) // id==2 name=="slene"
 	QueryRow(containers ...interface{}) error
+	QueryRowWithCtx(ctx context.Context, containers ...interface{}) error
 
 	// query data rows and map to container
 	//	var ids []int
 	//	var names []int
 	//	query = fmt.Sprintf("SELECT 'id','name' FROM %suser%s", Q, Q)
 	//	num, err = dORM.Raw(query).QueryRows(&ids,&names) // ids=>{1,2},names=>{"nobody","slene"}
+	// the field of unregistered struct tagged `orm:"type(json)"` is unmarshalled from the json column,
+	// e.g. the children aggregated by json_agg of postgres:
+	//	type UserPosts struct {
+	//		UserName string
+	//		Posts    []Post `orm:"column(posts);type(json)"`
+	//	}
 	QueryRows(containers ...interface{}) (int64, error)
+	QueryRowsWithCtx(ctx context.Context, containers ...interface{}) (int64, error)
+	// query the sql which returns multiple result sets, e.g. calling the stored procedure,
+	// each result set is mapped to the container at the same position like QueryRows.
+	// the total number of the rows is returned.
+	//	var users []*User
+	//	var posts []*Post
+	//	num, err = dORM.Raw("CALL user_posts(?)", 1).QueryMulti(&users, &posts)
+	QueryMulti(containers ...interface{}) (int64, error)
+	QueryMultiWithCtx(ctx context.Context, containers ...interface{}) (int64, error)
 	SetArgs(...interface{}) RawSeter
+	// execute the sql directly instead of the prepared statement cached by the StmtCacheSize option,
+	// it keeps the one-off queries out of the cache.
+	NoStmtCache() RawSeter
 	// query data to []map[string]interface
 	// see QuerySeter's Values
 	Values(container *[]Params, cols ...string) (int64, error)
@@ -620,11 +1104,16 @@ type dbBaser interface {
 	Read(context.Context, dbQuerier, *modelInfo, reflect.Value, *time.Location, []string, bool) error
 	ReadBatch(context.Context, dbQuerier, *querySet, *modelInfo, *Condition, interface{}, *time.Location, []string) (int64, error)
 	Count(context.Context, dbQuerier, *querySet, *modelInfo, *Condition, *time.Location) (int64, error)
+	AggregateColumn(context.Context, dbQuerier, *querySet, *modelInfo, *Condition, string, string, interface{}, *time.Location) error
 	ReadValues(context.Context, dbQuerier, *querySet, *modelInfo, *Condition, []string, interface{}, *time.Location) (int64, error)
+	ValuesSQL(*querySet, *modelInfo, *Condition, []string, *time.Location, bool) (string, []interface{}, []*fieldInfo, []*fieldInfo, error)
 
 	Insert(context.Context, dbQuerier, *modelInfo, reflect.Value, *time.Location) (int64, error)
 	InsertOrUpdate(context.Context, dbQuerier, *modelInfo, reflect.Value, *alias, ...string) (int64, error)
+	InsertOrIgnore(context.Context, dbQuerier, *modelInfo, reflect.Value, *alias, []string) (int64, int64, error)
+	InsertWithPK(context.Context, dbQuerier, *modelInfo, reflect.Value, *time.Location) (int64, error)
 	InsertMulti(context.Context, dbQuerier, *modelInfo, reflect.Value, int, *time.Location) (int64, error)
+	InsertMultiResult(context.Context, dbQuerier, *modelInfo, reflect.Value, int, *time.Location) ([]sql.Result, error)
 	InsertValue(context.Context, dbQuerier, *modelInfo, bool, []string, []interface{}) (int64, error)
 	InsertStmt(context.Context, stmtQuerier, *modelInfo, reflect.Value, *time.Location) (int64, error)
 
@@ -635,6 +1124,7 @@ type dbBaser interface {
 	DeleteBatch(context.Context, dbQuerier, *querySet, *modelInfo, *Condition, *time.Location) (int64, error)
 
 	SupportUpdateJoin() bool
+	SupportIsolationLevel(sql.IsolationLevel) bool
 	OperatorSQL(string) string
 	GenerateOperatorSQL(*modelInfo, *fieldInfo, string, []interface{}, *time.Location) (string, []interface{})
 	GenerateOperatorLeftCol(*fieldInfo, string, *string)
@@ -651,8 +1141,16 @@ type dbBaser interface {
 	ShowTablesQuery() string
 	ShowColumnsQuery(string) string
 	IndexExists(context.Context, dbQuerier, string, string) bool
+	IsConnError(error) bool
 	collectFieldValue(*modelInfo, *fieldInfo, reflect.Value, bool, *time.Location) (interface{}, error)
 	setval(context.Context, dbQuerier, *modelInfo, []string) error
+	setIns(dbBaser)
+	insertedIDs(sql.Result, int) []int64
 
 	GenerateSpecifyIndex(tableName string, useIndex int, indexes []string) string
+	GenerateOrderNulls(column string, sort string, nulls order_clause.Nulls) string
+	GenerateRandomOrder() string
+	GenerateFullTextSQL(columns []string) (string, error)
+	GenerateGroupConcatSQL(column string, sep string) (string, error)
+	GenerateResetAutoIncrementSQL(table string, column string) (string, error)
 }
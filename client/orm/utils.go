@@ -257,6 +257,19 @@ func SetNameStrategy(s string) {
 	nameStrategy = s
 }
 
+// the clock of the auto_now/auto_now_add timestamps
+var nowFunc = time.Now
+
+// SetNowFunc Change the clock used to stamp the auto_now/auto_now_add fields,
+// e.g. a fixed time in the tests. nil restores time.Now.
+// the start time of the transactions isn't stamped by it, as the filters measure the duration by time.Since.
+func SetNowFunc(now func() time.Time) {
+	if now == nil {
+		now = time.Now
+	}
+	nowFunc = now
+}
+
 // camel string, xx_yy to XxYy
 func camelString(s string) string {
 	data := make([]byte, 0, len(s))